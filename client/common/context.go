@@ -3,7 +3,9 @@ package common
 import (
 	"context"
 	"im/pkg/config"
+	"im/pkg/plato"
 	apigatewayService "im/server/apigateway/rpc/service"
+	discoveryService "im/server/discovery/rpc/service"
 	imGatewayService "im/server/imgateway/rpc/service"
 	"log/slog"
 	"net"
@@ -18,7 +20,12 @@ type Context struct {
 	Config            *config.ClientConfig
 	ApiGatewayClient  apigatewayService.APIGatewayClient
 	IMGatewayClient   imGatewayService.IMGatewayClient
+	DiscoveryClient   discoveryService.DiscoveryClient
 	IMGatewayLongConn net.Conn
+	IMGatewayEncoder  *plato.Encoder
+	UpLinkWindow      *UpLinkWindow
+	MessageDedup      *MessageDedup
+	SeqTracker        *SeqTracker
 	MessageReadChan   chan ChatMessage
 	MessageWriteChan  chan ChatMessage
 	LoginPage         fyne.Window
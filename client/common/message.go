@@ -3,49 +3,113 @@ package common
 import (
 	"fmt"
 	"im/pkg/plato"
+	"im/pkg/registry"
+	"im/pkg/xtls"
+	apigatewayService "im/server/apigateway/rpc/service"
+	discoveryService "im/server/discovery/rpc/service"
 	"io"
 	"log"
+	"net"
+	"time"
 
-	"google.golang.org/protobuf/proto"
+	"github.com/google/uuid"
 )
 
+const maxReconnectAttempts = 5
+
 func CreateConn(ctx *Context, token string) {
-	msg, err := proto.Marshal(&plato.MessageCreateConn{
-		Token: token,
-	})
-	if err != nil {
-		log.Fatalf("failed to marshal: %v", err)
+	if err := ctx.IMGatewayEncoder.EncodeMessage(plato.MsgTypeCreateConn, &plato.MessageCreateConn{
+		Token:    token,
+		DeviceId: ctx.Config.DeviceId,
+		Platform: ctx.Config.Platform,
+	}); err != nil {
+		log.Printf("failed to write: %v", err)
 	}
-	ctx.IMGatewayLongConn.Write(plato.Marshal(1, plato.MsgTypeCreateConn, nil, msg))
 }
 
 func Read(ctx *Context) {
+	decoder := plato.NewDecoder(ctx.IMGatewayLongConn)
 	for {
-		buf := make([]byte, 10)
-		n, err := ctx.IMGatewayLongConn.Read(buf)
+		frame, err := decoder.Decode()
 		if err != nil {
 			if err != io.EOF {
-				fmt.Printf("\nfailed to read: %v\n", err)
+				fmt.Printf("\nfailed to decode: %v\n", err)
 			}
 			break
 		}
-		fixHeader := &plato.FixHeaderProtocol{}
-		if err := fixHeader.Unmarshal(buf[:n]); err != nil {
-			fmt.Printf("\nfailed to unmarshal: %v\n", err)
-			break
-		}
-		content := make([]byte, fixHeader.GetVarHeaderLen()+fixHeader.GetBodyLen())
-		if _, err := ctx.IMGatewayLongConn.Read(content); err != nil {
-			if err != io.EOF {
-				fmt.Printf("\nfailed to read content: %v\n", err)
-				break
+		switch frame.MsgType {
+		case plato.MsgTypePong:
+			msg := plato.MessagePong{}
+			if err := frame.UnmarshalBody(&msg); err != nil {
+				fmt.Printf("\nfailed to unmarshal: %v\n", err)
+				continue
+			}
+			ctx.Logger.Debug("pong", "rtt", time.Since(time.UnixMilli(msg.GetTimestamp())).String())
+		case plato.MsgTypeKick:
+			msg := plato.MessageKick{}
+			if err := frame.UnmarshalBody(&msg); err != nil {
+				fmt.Printf("\nfailed to unmarshal: %v\n", err)
+				continue
+			}
+			ctx.Logger.Warn("kicked by server", "reason", msg.GetReason())
+		case plato.MsgTypeReconnect:
+			msg := plato.MessageReconnect{}
+			if err := frame.UnmarshalBody(&msg); err != nil {
+				fmt.Printf("\nfailed to unmarshal: %v\n", err)
+				continue
+			}
+			// 网关在发送重连通知前已写完其他帧，旧连接上不会再有消息
+			ctx.Logger.Info("gateway asks to reconnect", "reason", msg.GetReason())
+			conn, err := Reconnect(ctx)
+			if err != nil {
+				ctx.Logger.Error("failed to reconnect", "error", err)
+				return
+			}
+			decoder = plato.NewDecoder(conn)
+		case plato.MsgTypeResync:
+			msg := plato.MessageResync{}
+			if err := frame.UnmarshalBody(&msg); err != nil {
+				fmt.Printf("\nfailed to unmarshal: %v\n", err)
+				continue
+			}
+			// 网关写队列满时丢弃了下行消息，补拉之后的全部消息
+			FetchMissing(ctx, msg.GetSessionUuid(), msg.GetStartSeqid(), 0)
+		case plato.MsgTypeUpLinkAck:
+			msg := plato.MessageUpLinkAck{}
+			if err := frame.UnmarshalBody(&msg); err != nil {
+				fmt.Printf("\nfailed to unmarshal: %v\n", err)
+				continue
+			}
+			// 发送失败时保留在窗口中，等待超时重发
+			if len(msg.GetError()) > 0 {
+				ctx.Logger.Error("send message failed", "client_msg_id", msg.GetClientMsgId(), "error", msg.GetError())
+				continue
+			}
+			ctx.UpLinkWindow.Ack(msg.GetClientMsgId())
+			ctx.Logger.Debug("message sent", "client_msg_id", msg.GetClientMsgId(), "message_uuid", msg.GetMessageUuid(), "seq_id", msg.GetSeqId())
+			ctx.MessageDedup.Seen(msg.GetMessageUuid())
+			if start, end, ok := ctx.SeqTracker.Observe(msg.GetSessionUuid(), msg.GetSeqId()); ok {
+				FetchMissing(ctx, msg.GetSessionUuid(), start, end)
 			}
-		}
-		switch fixHeader.GetMsgType() {
 		case plato.MsgTypeMessageDownLink:
 			msg := plato.MessageDownLink{}
-			proto.Unmarshal(content[fixHeader.GetVarHeaderLen():], &msg)
+			if err := frame.UnmarshalBody(&msg); err != nil {
+				fmt.Printf("\nfailed to unmarshal: %v\n", err)
+				continue
+			}
 			fmt.Println("msg:", msg.GetSessionUuid(), msg.GetSenderUserUuid(), msg.GetPayload(), msg.GetSeqId())
+			// 无论是否重复都需要确认，否则网关会持续重传
+			if err := ctx.IMGatewayEncoder.EncodeMessage(plato.MsgTypeAck, &plato.MessageAck{
+				MessageUuid: msg.GetMessageUuid(),
+			}); err != nil {
+				log.Printf("failed to write ack: %v", err)
+			}
+			if start, end, ok := ctx.SeqTracker.Observe(msg.GetSessionUuid(), msg.GetSeqId()); ok {
+				FetchMissing(ctx, msg.GetSessionUuid(), start, end)
+			}
+			if ctx.MessageDedup.Seen(msg.GetMessageUuid()) {
+				continue
+			}
 			var avatarURI string
 			if users, ok := ctx.SessionUserTable[msg.GetSessionUuid()]; ok {
 				if user, ok := users[msg.GetSenderUserUuid()]; ok {
@@ -62,19 +126,129 @@ func Read(ctx *Context) {
 	}
 }
 
+// Connect 登录后选择网关建立长连接并认证
+func Connect(ctx *Context) error {
+	conn, err := xtls.Dial(ctx.Ctx, gatewayAddr(ctx), ctx.Config.TLSConfig, ctx.Logger)
+	if err != nil {
+		return err
+	}
+	ctx.IMGatewayLongConn = conn
+	ctx.IMGatewayEncoder.Reset(conn)
+	CreateConn(ctx, ctx.Token)
+	go Read(ctx)
+	go Heartbeat(ctx)
+	return nil
+}
+
+// 通过发现服务按用户ID选择网关，同一用户尽量落在同一网关，发现服务不可用时使用配置的地址
+func gatewayAddr(ctx *Context) string {
+	clientKey := ctx.Config.DeviceId
+	if ctx.User != nil {
+		clientKey = ctx.User.UUID
+	}
+	resp, err := ctx.DiscoveryClient.GetServiceIP(ctx.Ctx, &discoveryService.GetServiceIPRequest{
+		ServiceName: registry.ServiceIMGateway,
+		ClientKey:   clientKey,
+		Zone:        ctx.Config.Zone,
+	})
+	if err != nil {
+		ctx.Logger.Warn("failed to get gateway from discovery, use default address", "error", err, "address", ctx.Config.IMGatewayAddr)
+		return ctx.Config.IMGatewayAddr
+	}
+	return net.JoinHostPort(resp.ServiceAddress, resp.ServicePort)
+}
+
+// Reconnect 重新选择网关建立长连接并认证，未确认的上行消息由Retransmit重发
+func Reconnect(ctx *Context) (net.Conn, error) {
+	var conn net.Conn
+	var err error
+	for i := 1; i <= maxReconnectAttempts; i++ {
+		conn, err = xtls.Dial(ctx.Ctx, gatewayAddr(ctx), ctx.Config.TLSConfig, ctx.Logger)
+		if err == nil {
+			break
+		}
+		ctx.Logger.Warn("failed to dial gateway", "error", err, "attempt", i)
+		time.Sleep(time.Duration(i) * time.Second)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ctx.IMGatewayLongConn.Close()
+	ctx.IMGatewayLongConn = conn
+	ctx.IMGatewayEncoder.Reset(conn)
+	CreateConn(ctx, ctx.Token)
+	return conn, nil
+}
+
+// FetchMissing 补拉序列号区间(start, end]内缺失的消息
+func FetchMissing(ctx *Context, session_uuid string, start int64, end int64) {
+	ctx.Logger.Info("fetch missing messages", "session_uuid", session_uuid, "start_seqid", start, "end_seqid", end)
+	response, err := ctx.ApiGatewayClient.HistoryMessage(ctx.Ctx, &apigatewayService.HistoryMessageRequest{
+		SessionUuid: session_uuid,
+		StartSeqid:  start,
+		EndSeqid:    end,
+	})
+	if err != nil {
+		ctx.Logger.Error("failed to fetch missing messages", "error", err)
+		return
+	}
+	for _, msg := range response.Messages {
+		if ctx.MessageDedup.Seen(msg.MessageUuid) {
+			continue
+		}
+		ctx.MessageReadChan <- ChatMessage{
+			SessionUuid: msg.SessionUuid,
+			Content:     msg.Content,
+			IsSent:      ctx.User != nil && msg.SenderUuid == ctx.User.UUID,
+			AvatarURI:   fmt.Sprintf("assets/%s", msg.SenderAvatar),
+		}
+	}
+}
+
 func Write(ctx *Context) {
 	messageWriteChan := ctx.MessageWriteChan
 	for message := range messageWriteChan {
-		msg, err := proto.Marshal(&plato.MessageUpLink{
+		clientMsgId := message.ClientMsgId
+		if len(clientMsgId) == 0 {
+			clientMsgId = uuid.New().String()
+		}
+		msg := &plato.MessageUpLink{
 			SessionUuid: message.SessionUuid,
 			Payload:     message.Content,
-		})
-		if err != nil {
-			log.Fatalf("failed to marshal: %v", err)
+			ClientMsgId: clientMsgId,
 		}
-		data := plato.Marshal(1, plato.MsgTypeMessageUpLink, nil, msg)
-		if _, err := ctx.IMGatewayLongConn.Write(data); err != nil {
+		ctx.UpLinkWindow.Add(msg)
+		if err := ctx.IMGatewayEncoder.EncodeMessage(plato.MsgTypeMessageUpLink, msg); err != nil {
 			log.Printf("failed to write: %v", err)
 		}
 	}
 }
+
+// Retransmit 重发超时未确认的上行消息，client_msg_id不变以便服务端去重
+func Retransmit(ctx *Context) {
+	timeout := time.Duration(ctx.Config.AckTimeout) * time.Second
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		for _, msg := range ctx.UpLinkWindow.Expired(timeout) {
+			ctx.Logger.Info("retransmit message", "client_msg_id", msg.GetClientMsgId())
+			if err := ctx.IMGatewayEncoder.EncodeMessage(plato.MsgTypeMessageUpLink, msg); err != nil {
+				log.Printf("failed to retransmit: %v", err)
+			}
+		}
+	}
+}
+
+// Heartbeat 定时发送心跳，维持长连接
+func Heartbeat(ctx *Context) {
+	ticker := time.NewTicker(time.Duration(ctx.Config.HeartbeatInterval) * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := ctx.IMGatewayEncoder.EncodeMessage(plato.MsgTypePing, &plato.MessagePing{
+			Timestamp: time.Now().UnixMilli(),
+		}); err != nil {
+			log.Printf("failed to write heartbeat: %v", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package common
+
+import (
+	"im/pkg/plato"
+	"sync"
+	"time"
+)
+
+// UpLinkWindow 已发送但未收到服务端确认的上行消息
+type UpLinkWindow struct {
+	mu      sync.Mutex
+	pending map[string]*pendingUpLink
+}
+
+type pendingUpLink struct {
+	msg    *plato.MessageUpLink
+	sentAt time.Time
+}
+
+func NewUpLinkWindow() *UpLinkWindow {
+	return &UpLinkWindow{pending: make(map[string]*pendingUpLink)}
+}
+
+func (w *UpLinkWindow) Add(msg *plato.MessageUpLink) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending[msg.GetClientMsgId()] = &pendingUpLink{msg: msg, sentAt: time.Now()}
+}
+
+// Ack 服务端确认后移除，返回消息是否在窗口中
+func (w *UpLinkWindow) Ack(client_msg_id string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, ok := w.pending[client_msg_id]
+	delete(w.pending, client_msg_id)
+	return ok
+}
+
+// Expired 返回超时未确认的消息，并重置其发送时间
+func (w *UpLinkWindow) Expired(timeout time.Duration) []*plato.MessageUpLink {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	expired := make([]*plato.MessageUpLink, 0)
+	for _, pending := range w.pending {
+		if time.Since(pending.sentAt) < timeout {
+			continue
+		}
+		pending.sentAt = time.Now()
+		expired = append(expired, pending.msg)
+	}
+	return expired
+}
+
+// MessageDedup 记录最近收到的消息UUID，过滤重传导致的重复下行消息
+type MessageDedup struct {
+	mu       sync.Mutex
+	capacity int
+	seen     map[string]struct{}
+	order    []string
+}
+
+func NewMessageDedup(capacity int) *MessageDedup {
+	return &MessageDedup{
+		capacity: capacity,
+		seen:     make(map[string]struct{}, capacity),
+		order:    make([]string, 0, capacity),
+	}
+}
+
+// Seen 消息是否已收到过，未收到过则记录
+func (d *MessageDedup) Seen(message_uuid string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.seen[message_uuid]; ok {
+		return true
+	}
+	if len(d.order) >= d.capacity {
+		delete(d.seen, d.order[0])
+		d.order = d.order[1:]
+	}
+	d.seen[message_uuid] = struct{}{}
+	d.order = append(d.order, message_uuid)
+	return false
+}
+
+// SeqTracker 记录每个会话已收到的最大序列号，用于发现消息空洞
+type SeqTracker struct {
+	mu   sync.Mutex
+	last map[string]int64
+}
+
+func NewSeqTracker() *SeqTracker {
+	return &SeqTracker{last: make(map[string]int64)}
+}
+
+// Observe 记录收到的序列号，返回需要补拉的区间(start, end]
+// 会话首次出现时无法判断是否缺失，不补拉；乱序到达的旧消息不影响最大值
+func (t *SeqTracker) Observe(session_uuid string, seq_id int64) (int64, int64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	last, ok := t.last[session_uuid]
+	if seq_id > last {
+		t.last[session_uuid] = seq_id
+	}
+	if !ok || seq_id <= last+1 {
+		return 0, 0, false
+	}
+	return last, seq_id - 1, true
+}
@@ -2,6 +2,7 @@ package common
 
 type ChatMessage struct {
 	SessionUuid string // 会话UUID
+	ClientMsgId string // 客户端消息ID，重发时保持不变
 	Content   string // 消息内容
 	IsSent    bool   // true 表示发送的消息，false 表示接收的消息
 	AvatarURI string // 头像资源路径，支持本地文件或后续的远程 URL
@@ -5,24 +5,27 @@ import (
 	"im/client/common"
 	"im/client/page"
 	"im/pkg/config"
+	"im/pkg/plato"
+	"im/pkg/xtls"
 	apigatewayService "im/server/apigateway/rpc/service"
+	discoveryService "im/server/discovery/rpc/service"
 	imGatewayService "im/server/imgateway/rpc/service"
 	"image/color"
+	"io"
 	"log/slog"
-	"net"
 	"os"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/theme"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 // tokenAuth 实现了 credentials.PerRPCCredentials 接口
 type tokenAuth struct {
 	ctx    *common.Context
 	logger *slog.Logger
+	secure bool
 }
 
 // GetRequestMetadata 为每个 RPC 请求获取并设置认证元数据
@@ -35,7 +38,7 @@ func (t *tokenAuth) GetRequestMetadata(ctx context.Context, uri ...string) (map[
 
 // RequireTransportSecurity 指明是否需要安全的传输连接
 func (t *tokenAuth) RequireTransportSecurity() bool {
-	return false // 建议为 true 来配合 TLS
+	return t.secure
 }
 
 func main() {
@@ -43,6 +46,9 @@ func main() {
 		SessionUserTable:make(map[string]map[string]common.User, 0),
 	}
 	conf := config.NewConf().GetClientConfig()
+	if len(conf.DeviceId) == 0 {
+		conf.DeviceId, _ = os.Hostname()
+	}
 
 	level := slog.LevelInfo
 	if conf.Mode == "debug" {
@@ -52,26 +58,31 @@ func main() {
 		Level: level,
 	}))
 
-	apiGatewayConn, err := grpc.NewClient(conf.APIGatewayAddr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithPerRPCCredentials(&tokenAuth{ctx: ctx, logger: logger}))
+	dialOption, err := xtls.DialOption(context.Background(), conf.TLSConfig, logger)
+	if err != nil {
+		logger.Error("failed to load tls", "error", err)
+		return
+	}
+	apiGatewayConn, err := grpc.NewClient(conf.APIGatewayAddr, dialOption, grpc.WithPerRPCCredentials(&tokenAuth{ctx: ctx, logger: logger, secure: conf.TLSConfig.Enabled}))
 	if err != nil {
 		logger.Error("failed to create client", "error", err)
 		return
 	}
 	apiGatewayClient := apigatewayService.NewAPIGatewayClient(apiGatewayConn)
 
-	imGatewayConn, err := grpc.NewClient(conf.IMGatewayAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	imGatewayConn, err := grpc.NewClient(conf.IMGatewayAddr, dialOption)
 	if err != nil {
 		logger.Error("failed to create client", "error", err)
 		return
 	}
 	imGatewayClient := imGatewayService.NewIMGatewayClient(imGatewayConn)
 
-	imGatewayLongConn, err := net.Dial("tcp", conf.IMGatewayAddr)
+	discoveryConn, err := grpc.NewClient(conf.DiscoveryAddr, dialOption)
 	if err != nil {
-		logger.Error("failed to dial", "error", err)
+		logger.Error("failed to create client", "error", err)
 		return
 	}
-	defer imGatewayLongConn.Close()
+	discoveryClient := discoveryService.NewDiscoveryClient(discoveryConn)
 
 	a := app.New()
 
@@ -84,16 +95,24 @@ func main() {
 	ctx.Config = conf
 	ctx.ApiGatewayClient = apiGatewayClient
 	ctx.IMGatewayClient = imGatewayClient
-	ctx.IMGatewayLongConn = imGatewayLongConn
+	ctx.DiscoveryClient = discoveryClient
+	// 登录后才知道用户ID，由common.Connect选择网关并建立长连接
+	ctx.IMGatewayEncoder = plato.NewEncoder(io.Discard)
+	ctx.UpLinkWindow = common.NewUpLinkWindow()
+	ctx.MessageDedup = common.NewMessageDedup(1024)
+	ctx.SeqTracker = common.NewSeqTracker()
 	messageReadChan := make(chan common.ChatMessage)
 	messageWriteChan := make(chan common.ChatMessage)
 	ctx.MessageReadChan = messageReadChan
 	ctx.MessageWriteChan = messageWriteChan
-	go common.Read(ctx)
 	go common.Write(ctx)
+	go common.Retransmit(ctx)
 	ctx.LoginPage = page.LoginPage(ctx)
 	ctx.LoginPage.Show()
 	a.Run()
+	if ctx.IMGatewayLongConn != nil {
+		ctx.IMGatewayLongConn.Close()
+	}
 
 }
 
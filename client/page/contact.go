@@ -0,0 +1,192 @@
+package page
+
+import (
+	"fmt"
+	"im/client/common"
+	"im/model"
+	apigatewayService "im/server/apigateway/rpc/service"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ContactPage 通讯录窗口：联系人列表、好友申请和搜索添加朋友
+func ContactPage(homeCtx *HomePageContext) fyne.Window {
+	ctx := homeCtx.AppCtx
+	w := ctx.App.NewWindow("通讯录")
+	w.Resize(fyne.NewSize(360, 480))
+
+	contactBox := container.NewVBox()
+	requestBox := container.NewVBox()
+	resultBox := container.NewVBox()
+
+	// 联系人列表，点击联系人打开单聊
+	loadContacts := func() {
+		contactBox.RemoveAll()
+		response, err := ctx.ApiGatewayClient.ListContacts(ctx.Ctx, &apigatewayService.ListContactsRequest{})
+		if err != nil {
+			ctx.Logger.Error("Failed to list contacts", "error", err)
+			dialog.ShowError(err, w)
+			return
+		}
+		if len(response.Contacts) == 0 {
+			contactBox.Add(widget.NewLabel("还没有联系人，搜索用户添加朋友"))
+		}
+		for _, contact := range response.Contacts {
+			name := contact.UserName
+			// 优先显示备注
+			if len(contact.Remark) > 0 {
+				name = contact.Remark
+			}
+			contactBox.Add(common.NewTappableContainer(createUserRow(contact.UserAvatar, name, nil), func() {
+				homeCtx.openContact(contact.UserUuid, name, contact.UserAvatar)
+				w.Close()
+			}))
+		}
+		contactBox.Refresh()
+	}
+
+	// 好友申请列表，收到的待处理申请可以同意或拒绝
+	var loadRequests func()
+	loadRequests = func() {
+		requestBox.RemoveAll()
+		response, err := ctx.ApiGatewayClient.ListFriendRequests(ctx.Ctx, &apigatewayService.ListFriendRequestsRequest{})
+		if err != nil {
+			ctx.Logger.Error("Failed to list friend requests", "error", err)
+			dialog.ShowError(err, w)
+			return
+		}
+		if len(response.Requests) == 0 {
+			requestBox.Add(widget.NewLabel("没有好友申请"))
+		}
+		for _, request := range response.Requests {
+			received := request.ToUuid == ctx.User.UUID
+			avatar, name := request.ToAvatar, "发给 "+request.ToName
+			if received {
+				avatar, name = request.FromAvatar, request.FromName
+			}
+			if len(request.Message) > 0 {
+				name = fmt.Sprintf("%s：%s", name, request.Message)
+			}
+			var action fyne.CanvasObject
+			switch {
+			case request.Status == model.FriendRequestStatusPending && received:
+				action = container.NewHBox(
+					widget.NewButton("同意", func() {
+						if _, err := ctx.ApiGatewayClient.AcceptFriendRequest(ctx.Ctx, &apigatewayService.AcceptFriendRequestRequest{
+							RequestUuid: request.RequestUuid,
+						}); err != nil {
+							dialog.ShowError(err, w)
+						}
+						loadRequests()
+						loadContacts()
+					}),
+					widget.NewButton("拒绝", func() {
+						if _, err := ctx.ApiGatewayClient.RejectFriendRequest(ctx.Ctx, &apigatewayService.RejectFriendRequestRequest{
+							RequestUuid: request.RequestUuid,
+						}); err != nil {
+							dialog.ShowError(err, w)
+						}
+						loadRequests()
+					}),
+				)
+			case request.Status == model.FriendRequestStatusPending:
+				action = widget.NewLabel("等待验证")
+			case request.Status == model.FriendRequestStatusAccepted:
+				action = widget.NewLabel("已添加")
+			default:
+				action = widget.NewLabel("已拒绝")
+			}
+			requestBox.Add(createUserRow(avatar, name, action))
+		}
+		requestBox.Refresh()
+	}
+
+	// 搜索用户并发送好友申请
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder("输入账号或用户名搜索")
+	search := func() {
+		resultBox.RemoveAll()
+		keyword := strings.TrimSpace(searchEntry.Text)
+		if keyword == "" {
+			return
+		}
+		response, err := ctx.ApiGatewayClient.SearchUsers(ctx.Ctx, &apigatewayService.SearchUsersRequest{Keyword: keyword})
+		if err != nil {
+			ctx.Logger.Error("Failed to search users", "error", err)
+			dialog.ShowError(err, w)
+			return
+		}
+		if len(response.Users) == 0 {
+			resultBox.Add(widget.NewLabel("没有找到用户"))
+		}
+		for _, user := range response.Users {
+			var addButton *widget.Button
+			addButton = widget.NewButton("添加", func() {
+				response, err := ctx.ApiGatewayClient.SendFriendRequest(ctx.Ctx, &apigatewayService.SendFriendRequestRequest{
+					ToUuid:  user.UserUuid,
+					Message: "我是" + ctx.User.Name,
+				})
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				addButton.Disable()
+				// 对方也向自己发过申请时直接成为联系人
+				if response.Accepted {
+					addButton.SetText("已添加")
+					loadContacts()
+					return
+				}
+				addButton.SetText("已申请")
+				loadRequests()
+			})
+			if user.IsContact {
+				addButton.SetText("已添加")
+				addButton.Disable()
+			}
+			resultBox.Add(createUserRow(user.UserAvatar, user.UserName, addButton))
+		}
+		resultBox.Refresh()
+	}
+	searchEntry.OnSubmitted = func(string) {
+		search()
+	}
+	searchArea := container.NewBorder(nil, nil, nil, widget.NewButton("搜索", search), searchEntry)
+
+	tabs := container.NewAppTabs(
+		container.NewTabItem("联系人", container.NewVScroll(contactBox)),
+		container.NewTabItem("新的朋友", container.NewVScroll(requestBox)),
+		container.NewTabItem("添加朋友", container.NewBorder(searchArea, nil, nil, nil, container.NewVScroll(resultBox))),
+	)
+	// 切换标签时刷新，获取最新的联系人和申请状态
+	tabs.OnSelected = func(tab *container.TabItem) {
+		switch tab.Text {
+		case "联系人":
+			loadContacts()
+		case "新的朋友":
+			loadRequests()
+		}
+	}
+	loadContacts()
+	loadRequests()
+
+	w.SetContent(tabs)
+	return w
+}
+
+// createUserRow 创建用户列表项：头像、名称和右侧的操作
+func createUserRow(avatar string, name string, action fyne.CanvasObject) fyne.CanvasObject {
+	image := canvas.NewImageFromFile(fmt.Sprintf("assets/%s", avatar))
+	image.FillMode = canvas.ImageFillContain
+	image.SetMinSize(fyne.Size{Width: 32, Height: 32})
+
+	nameLabel := widget.NewLabel(name)
+	nameLabel.Truncation = fyne.TextTruncateEllipsis
+
+	return container.NewPadded(container.NewBorder(nil, nil, image, action, nameLabel))
+}
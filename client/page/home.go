@@ -12,6 +12,7 @@ import (
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
@@ -211,60 +212,117 @@ func (homeCtx *HomePageContext) createSessionItem(session common.Session) fyne.C
 		itemContent,
 		separator,
 	), func() {
+		homeCtx.openSession(session)
+	})
 
-		homeCtx.MessageBox.RemoveAll()
-		homeCtx.UsernName.Text = session.Name
-		homeCtx.CurrentSessionUUID = session.UUID
-		homeCtx.UsernName.Refresh()
+	return sessionItem
+}
 
-		fyne.Do(func() {
-			response, err := homeCtx.AppCtx.ApiGatewayClient.HistoryMessage(homeCtx.AppCtx.Ctx, &apigatewayService.HistoryMessageRequest{
-				SessionUuid: session.UUID,
-				StartSeqid:  0,
-			})
-			if err != nil {
-				homeCtx.AppCtx.Logger.Error("Failed to get history message", "error", err)
-				return
-			}
-			for _, msg := range response.Messages {
-				homeCtx.AppCtx.Logger.Debug("message", "message", msg, "user_uuid", homeCtx.AppCtx.User.UUID)
-				if msg.SenderUuid == homeCtx.AppCtx.User.UUID {
-					homeCtx.MessageBox.Add(createSentMessage(common.ChatMessage{
-						Content:   msg.Content,
-						IsSent:    true,
-						AvatarURI: fmt.Sprintf("assets/%s", msg.SenderAvatar),
-					}))
-				} else {
-					homeCtx.MessageBox.Add(createReceivedMessage(common.ChatMessage{
-						Content:   msg.Content,
-						IsSent:    false,
-						AvatarURI: fmt.Sprintf("assets/%s", msg.SenderAvatar),
-					}))
-				}
+// openSession 切换到会话并加载历史消息和会话成员
+func (homeCtx *HomePageContext) openSession(session common.Session) {
+	homeCtx.MessageBox.RemoveAll()
+	homeCtx.UsernName.Text = session.Name
+	homeCtx.CurrentSessionUUID = session.UUID
+	homeCtx.UsernName.Refresh()
+
+	fyne.Do(func() {
+		response, err := homeCtx.AppCtx.ApiGatewayClient.HistoryMessage(homeCtx.AppCtx.Ctx, &apigatewayService.HistoryMessageRequest{
+			SessionUuid: session.UUID,
+			StartSeqid:  0,
+		})
+		if err != nil {
+			homeCtx.AppCtx.Logger.Error("Failed to get history message", "error", err)
+			return
+		}
+		for _, msg := range response.Messages {
+			homeCtx.AppCtx.Logger.Debug("message", "message", msg, "user_uuid", homeCtx.AppCtx.User.UUID)
+			homeCtx.AppCtx.SeqTracker.Observe(msg.SessionUuid, msg.SeqId)
+			homeCtx.AppCtx.MessageDedup.Seen(msg.MessageUuid)
+			if msg.SenderUuid == homeCtx.AppCtx.User.UUID {
+				homeCtx.MessageBox.Add(createSentMessage(common.ChatMessage{
+					Content:   msg.Content,
+					IsSent:    true,
+					AvatarURI: fmt.Sprintf("assets/%s", msg.SenderAvatar),
+				}))
+			} else {
+				homeCtx.MessageBox.Add(createReceivedMessage(common.ChatMessage{
+					Content:   msg.Content,
+					IsSent:    false,
+					AvatarURI: fmt.Sprintf("assets/%s", msg.SenderAvatar),
+				}))
 			}
-			homeCtx.MessageBox.Refresh()
+		}
+		homeCtx.MessageBox.Refresh()
 
-			sessionUserList, err := homeCtx.AppCtx.ApiGatewayClient.GetSessionUserList(homeCtx.AppCtx.Ctx, &apigatewayService.GetSessionUserListRequest{
-				SessionUuid: session.UUID,
-			})
-			if err != nil {
-				homeCtx.AppCtx.Logger.Error("Failed to get session user list", "error", err)
-				return
-			}
-			users := make(map[string]common.User, 0)
-			for _, user := range sessionUserList.Users {
-				users[user.UserUuid] = common.User{
-					UUID:   user.UserUuid,
-					Name:   user.UserName,
-					Avatar: fmt.Sprintf("assets/%s", user.UserAvatar),
-				}
-			}
-			homeCtx.AppCtx.SessionUserTable[session.UUID] = users
+		sessionUserList, err := homeCtx.AppCtx.ApiGatewayClient.GetSessionUserList(homeCtx.AppCtx.Ctx, &apigatewayService.GetSessionUserListRequest{
+			SessionUuid: session.UUID,
 		})
+		if err != nil {
+			homeCtx.AppCtx.Logger.Error("Failed to get session user list", "error", err)
+			return
+		}
+		users := make(map[string]common.User, 0)
+		for _, user := range sessionUserList.Users {
+			users[user.UserUuid] = common.User{
+				UUID:   user.UserUuid,
+				Name:   user.UserName,
+				Avatar: fmt.Sprintf("assets/%s", user.UserAvatar),
+			}
+		}
+		homeCtx.AppCtx.SessionUserTable[session.UUID] = users
+	})
+}
 
+// openContact 打开与联系人的单聊会话，首次聊天时由服务端创建会话
+func (homeCtx *HomePageContext) openContact(contactUuid string, name string, avatar string) {
+	response, err := homeCtx.AppCtx.ApiGatewayClient.OpenSingleSession(homeCtx.AppCtx.Ctx, &apigatewayService.OpenSingleSessionRequest{
+		ContactUuid: contactUuid,
 	})
+	if err != nil {
+		homeCtx.AppCtx.Logger.Error("Failed to open single session", "error", err, "contact_uuid", contactUuid)
+		return
+	}
+	// 新创建的会话需要出现在会话列表中
+	homeCtx.loadSessions()
+	homeCtx.openSession(common.Session{
+		UUID:      response.SessionUuid,
+		Name:      name,
+		AvatarURI: fmt.Sprintf("assets/%s", avatar),
+	})
+}
 
-	return sessionItem
+// loadSessions 重新加载会话列表
+func (homeCtx *HomePageContext) loadSessions() {
+	ctx := homeCtx.AppCtx
+	sessionBox := homeCtx.SessionBox
+	fyne.Do(func() {
+		sessionBox.RemoveAll()
+		response, err := ctx.ApiGatewayClient.SessionList(ctx.Ctx, &apigatewayService.SessionListRequest{})
+		if err != nil {
+			ctx.Logger.Error("Failed to get session list", "error", err)
+			return
+		}
+		if len(response.Sessions) == 0 {
+			// 新用户没有会话，提示先添加联系人
+			hint := widget.NewLabel("还没有会话，点击通讯录添加朋友")
+			hint.Alignment = fyne.TextAlignCenter
+			sessionBox.Add(hint)
+		}
+		for _, v := range response.Sessions {
+			ctx.Logger.Debug("session", "session", v)
+			session := common.Session{
+				UUID:        v.Uuid,
+				Name:        v.Name,
+				AvatarURI:   fmt.Sprintf("assets/%s", v.Avatar),
+				LastMessage: v.LastMessage,
+				UnreadCount: int(v.UnreadCount),
+				LastTime:    v.LastTime,
+			}
+			item := homeCtx.createSessionItem(session)
+			sessionBox.Add(item)
+		}
+		sessionBox.Refresh()
+	})
 }
 
 // createReceivedMessage 创建接收到的消息组件（左侧布局）
@@ -345,7 +403,8 @@ type HomePageContext struct {
 	Messages           []common.ChatMessage
 	MessageBox         *fyne.Container
 	UsernName          *widget.Label
-	CurrentSessionUUID string // 当前会话UUID
+	SessionBox         *fyne.Container // 会话列表
+	CurrentSessionUUID string          // 当前会话UUID
 
 }
 
@@ -361,6 +420,7 @@ func HomePage(ctx *common.Context) fyne.Window {
 
 	// 使用VBox创建会话列表（更灵活）
 	sessionBox := container.NewVBox()
+	homeCtx.SessionBox = sessionBox
 
 	// 创建消息容器（VBox）
 	messageBox := container.NewVBox()
@@ -372,28 +432,7 @@ func HomePage(ctx *common.Context) fyne.Window {
 
 	go func() {
 		// for range time.NewTicker(5 * time.Second).C {
-		fyne.Do(func() {
-			sessionBox.RemoveAll()
-			response, err := ctx.ApiGatewayClient.SessionList(ctx.Ctx, &apigatewayService.SessionListRequest{})
-			if err != nil {
-				ctx.Logger.Error("Failed to get session list", "error", err)
-				return
-			}
-			for _, v := range response.Sessions {
-				ctx.Logger.Debug("session", "session", v)
-				session := common.Session{
-					UUID:        v.Uuid,
-					Name:        v.Name,
-					AvatarURI:   fmt.Sprintf("assets/%s", v.Avatar),
-					LastMessage: v.LastMessage,
-					UnreadCount: int(v.UnreadCount),
-					LastTime:    v.LastTime,
-				}
-				item := homeCtx.createSessionItem(session)
-				sessionBox.Add(item)
-			}
-			sessionBox.Refresh()
-		})
+		homeCtx.loadSessions()
 		// }
 	}()
 
@@ -409,7 +448,12 @@ func HomePage(ctx *common.Context) fyne.Window {
 	// 左侧内容：会话列表滚动容器（隐藏滚动条样式）
 	sessionScroll := container.NewVScroll(sessionBox)
 	sessionScroll.ScrollToTop()
-	leftContent := sessionScroll
+
+	// 通讯录按钮：管理联系人和好友申请，点击联系人打开单聊
+	contactButton := widget.NewButtonWithIcon("通讯录", theme.AccountIcon(), func() {
+		ContactPage(homeCtx).Show()
+	})
+	leftContent := container.NewBorder(contactButton, nil, nil, nil, sessionScroll)
 
 	// 将现有消息添加到容器中
 	for _, msg := range messages {
@@ -116,9 +116,14 @@ func LoginPage(ctx *common.Context) fyne.Window {
 			Phone:  responseUser.Mobile,
 		}
 
+		if err := common.Connect(ctx); err != nil {
+			errorLabel.SetText("❌ 连接服务器失败: " + err.Error())
+			errorLabel.Show()
+			return
+		}
+
 		// 登录成功
 		ctx.LoginPage.Close()
-		common.CreateConn(ctx, ctx.Token)
 		ctx.HomePage = HomePage(ctx)
 		ctx.HomePage.Show()
 	})
@@ -190,8 +195,13 @@ func LoginPage(ctx *common.Context) fyne.Window {
 			Phone:  responseUser.Mobile,
 		}
 
+		if err := common.Connect(ctx); err != nil {
+			errorLabel.SetText("❌ 连接服务器失败: " + err.Error())
+			errorLabel.Show()
+			return
+		}
+
 		ctx.LoginPage.Close()
-		common.CreateConn(ctx, ctx.Token)
 		ctx.HomePage = HomePage(ctx)
 		ctx.HomePage.Show()
 	})
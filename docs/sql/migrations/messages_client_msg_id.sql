@@ -0,0 +1,24 @@
+-- 已有部署升级到消息去重版本，需要MySQL 8.0
+-- 新增客户端消息ID字段，并为客户端消息ID和会话序列号建立唯一索引
+
+-- 1. 新增字段
+alter table messages add column client_msg_id varchar(255) not null default '' after content; -- 客户端消息ID 用于去重
+
+-- 2. 历史消息没有客户端消息ID，使用消息UUID回填，避免同一发送者的空值冲突
+update messages set client_msg_id = uuid where client_msg_id = '';
+
+-- 3. 历史消息中同一会话重复的序列号，保留最早的一条，其余顺延到会话当前最大序列号之后
+update messages m
+join (
+    select d.id, s.max_seq_id + row_number() over (partition by d.session_uuid order by d.id) as seq_id
+    from messages d
+    join (select session_uuid, max(seq_id) as max_seq_id from messages group by session_uuid) s on s.session_uuid = d.session_uuid
+    where exists (select 1 from messages o where o.session_uuid = d.session_uuid and o.seq_id = d.seq_id and o.id < d.id)
+) r on r.id = m.id
+set m.seq_id = r.seq_id;
+
+-- 4. 建立唯一索引
+create unique index idx_messages_sender_uuid_client_msg_id on messages (sender_uuid, client_msg_id);
+create unique index idx_messages_session_uuid_seq_id on messages (session_uuid, seq_id);
+
+-- 序列号顺延后Redis中的会话序列号可能落后，发送消息遇到序列号冲突时会按MySQL重置
@@ -17,6 +17,7 @@ create table session_members (
     id bigint auto_increment, -- 主键ID
     session_uuid varchar(255) not null, -- 会话UUID
     user_uuid varchar(255) not null, -- 用户UUID
+    role int not null default 3, -- 成员角色 1: 群主 2: 管理员 3: 普通成员
     created_at datetime default current_timestamp not null, -- 创建时间
     updated_at datetime default current_timestamp on update current_timestamp not null, -- 更新时间
     primary key (id) -- 主键ID
@@ -24,6 +25,35 @@ create table session_members (
 create unique index idx_session_members_user_uuid_session_uuid on session_members (user_uuid, session_uuid);
 create index idx_session_members_session_uuid on session_members (session_uuid);
 
+-- 联系人表 好友关系双向各存一行
+create table contacts (
+    id bigint auto_increment, -- 主键ID
+    user_uuid varchar(255) not null, -- 用户UUID
+    contact_uuid varchar(255) not null, -- 联系人UUID
+    remark varchar(255) not null default '', -- 备注
+    session_uuid varchar(255) not null default '', -- 单聊会话UUID 首次聊天时创建
+    created_at datetime default current_timestamp not null, -- 创建时间
+    updated_at datetime default current_timestamp on update current_timestamp not null, -- 更新时间
+    primary key (id) -- 主键ID
+);
+create unique index idx_contacts_user_uuid_contact_uuid on contacts (user_uuid, contact_uuid);
+
+-- 好友申请表
+create table friend_requests (
+    id bigint auto_increment, -- 主键ID
+    uuid varchar(255) not null, -- 申请UUID
+    from_uuid varchar(255) not null, -- 申请者UUID
+    to_uuid varchar(255) not null, -- 接收者UUID
+    message varchar(255) not null default '', -- 验证消息
+    status int not null, -- 状态 1: 待处理 2: 已同意 3: 已拒绝
+    created_at datetime default current_timestamp not null, -- 创建时间
+    updated_at datetime default current_timestamp on update current_timestamp not null, -- 更新时间
+    primary key (id) -- 主键ID
+);
+create unique index idx_friend_requests_uuid on friend_requests (uuid);
+create index idx_friend_requests_to_uuid_status on friend_requests (to_uuid, status);
+create index idx_friend_requests_from_uuid_to_uuid on friend_requests (from_uuid, to_uuid);
+
 
 -- 消息表
 create table messages (
@@ -32,14 +62,17 @@ create table messages (
     session_uuid varchar(255) not null, -- 会话UUID
     sender_uuid varchar(255) not null, -- 发送者UUID
     seq_id bigint not null, -- 消息序列号ID
-    message_type int not null, -- 消息类型 1: text
+    message_type int not null, -- 消息类型 1: text 2: system
     status int not null, -- 状态 1: 已发送 2: 已接收 3: 已读
     content text not null, -- 消息内容
+    client_msg_id varchar(255) not null default '', -- 客户端消息ID 用于去重
     created_at datetime default current_timestamp not null, -- 创建时间
     updated_at datetime default current_timestamp on update current_timestamp not null, -- 更新时间
     primary key(id) -- 主键ID
 );
 create unique index idx_messages_uuid on messages (uuid);
+create unique index idx_messages_sender_uuid_client_msg_id on messages (sender_uuid, client_msg_id);
+create unique index idx_messages_session_uuid_seq_id on messages (session_uuid, seq_id);
 
 -- 用户表
 create table user_base (
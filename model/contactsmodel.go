@@ -0,0 +1,167 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"im/pkg/xstrings"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ ContactsModel = (*customContactsModel)(nil)
+
+type (
+	// ContactsModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customContactsModel.
+	ContactsModel interface {
+		contactsModel
+		withSession(session sqlx.Session) ContactsModel
+		FindContact(ctx context.Context, userUuid string, contactUuid string) (*Contacts, error)
+		FindContactForUpdate(ctx context.Context, tx sqlx.Session, userUuid string, contactUuid string) (*Contacts, error)
+		FindContactsByUserUuid(ctx context.Context, userUuid string) ([]*Contacts, error)
+		FindContactUuids(ctx context.Context, userUuid string, contactUuids []string) ([]string, error)
+		AddContact(ctx context.Context, tx sqlx.Session, userUuid string, contactUuid string) error
+		RemoveContact(ctx context.Context, tx sqlx.Session, userUuid string, contactUuid string) error
+		UpdateRemark(ctx context.Context, userUuid string, contactUuid string, remark string) error
+		BindSession(ctx context.Context, tx sqlx.Session, userUuid string, contactUuid string, sessionUuid string) (bool, error)
+	}
+
+	customContactsModel struct {
+		*defaultContactsModel
+	}
+)
+
+// NewContactsModel returns a model for the database table.
+func NewContactsModel(conn sqlx.SqlConn) ContactsModel {
+	return &customContactsModel{
+		defaultContactsModel: newContactsModel(conn),
+	}
+}
+
+func (m *customContactsModel) withSession(session sqlx.Session) ContactsModel {
+	return NewContactsModel(sqlx.NewSqlConnFromSession(session))
+}
+
+// 查询用户的某个联系人，不是联系人时返回nil
+func (m *customContactsModel) FindContact(ctx context.Context, userUuid string, contactUuid string) (*Contacts, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_uuid = ? AND contact_uuid = ? LIMIT 1", contactsRows, m.table)
+	var resp Contacts
+	err := m.conn.QueryRowCtx(ctx, &resp, query, userUuid, contactUuid)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, errors.Join(err, fmt.Errorf("find contact %s of user %s failed", contactUuid, userUuid))
+	}
+	return &resp, nil
+}
+
+// 在事务中查询联系人并加锁，与记录单聊会话互斥，不是联系人时返回nil
+func (m *customContactsModel) FindContactForUpdate(ctx context.Context, tx sqlx.Session, userUuid string, contactUuid string) (*Contacts, error) {
+	var conn sqlx.Session
+	if tx == nil {
+		conn = m.conn
+	} else {
+		conn = tx
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_uuid = ? AND contact_uuid = ? LIMIT 1 FOR UPDATE", contactsRows, m.table)
+	var resp Contacts
+	err := conn.QueryRowCtx(ctx, &resp, query, userUuid, contactUuid)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, errors.Join(err, fmt.Errorf("find contact %s of user %s for update failed", contactUuid, userUuid))
+	}
+	return &resp, nil
+}
+
+// 查询用户的联系人列表，按添加顺序排列
+func (m *customContactsModel) FindContactsByUserUuid(ctx context.Context, userUuid string) ([]*Contacts, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_uuid = ? ORDER BY id ASC", contactsRows, m.table)
+	var resp []*Contacts
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, userUuid)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("find contacts by user uuid %s failed", userUuid))
+	}
+	return resp, nil
+}
+
+// 查询给定用户中哪些是联系人
+func (m *customContactsModel) FindContactUuids(ctx context.Context, userUuid string, contactUuids []string) ([]string, error) {
+	if len(contactUuids) == 0 {
+		return nil, nil
+	}
+	queryStr, args := xstrings.BuildInQuery(contactUuids)
+	query := fmt.Sprintf("SELECT contact_uuid FROM %s WHERE user_uuid = ? AND contact_uuid in (%s)", m.table, queryStr)
+	resp := []string{}
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, append([]any{userUuid}, args...)...)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("find contact uuids of user %s failed", userUuid))
+	}
+	return resp, nil
+}
+
+// 添加联系人，已存在时忽略
+func (m *customContactsModel) AddContact(ctx context.Context, tx sqlx.Session, userUuid string, contactUuid string) error {
+	var conn sqlx.Session
+	if tx == nil {
+		conn = m.conn
+	} else {
+		conn = tx
+	}
+	query := fmt.Sprintf("INSERT INTO %s (user_uuid, contact_uuid) VALUES (?, ?) ON DUPLICATE KEY UPDATE id = id", m.table)
+	_, err := conn.ExecCtx(ctx, query, userUuid, contactUuid)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("add contact failed"))
+	}
+	return nil
+}
+
+// 删除联系人
+func (m *customContactsModel) RemoveContact(ctx context.Context, tx sqlx.Session, userUuid string, contactUuid string) error {
+	var conn sqlx.Session
+	if tx == nil {
+		conn = m.conn
+	} else {
+		conn = tx
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_uuid = ? AND contact_uuid = ?", m.table)
+	_, err := conn.ExecCtx(ctx, query, userUuid, contactUuid)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("remove contact failed"))
+	}
+	return nil
+}
+
+// 修改联系人备注
+func (m *customContactsModel) UpdateRemark(ctx context.Context, userUuid string, contactUuid string, remark string) error {
+	query := fmt.Sprintf("UPDATE %s SET remark = ? WHERE user_uuid = ? AND contact_uuid = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, remark, userUuid, contactUuid)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("update contact remark failed"))
+	}
+	return nil
+}
+
+// 记录联系人的单聊会话，已记录会话或不是联系人时返回false
+func (m *customContactsModel) BindSession(ctx context.Context, tx sqlx.Session, userUuid string, contactUuid string, sessionUuid string) (bool, error) {
+	var conn sqlx.Session
+	if tx == nil {
+		conn = m.conn
+	} else {
+		conn = tx
+	}
+	query := fmt.Sprintf("UPDATE %s SET session_uuid = ? WHERE user_uuid = ? AND contact_uuid = ? AND session_uuid = ''", m.table)
+	result, err := conn.ExecCtx(ctx, query, sessionUuid, userUuid, contactUuid)
+	if err != nil {
+		return false, errors.Join(err, fmt.Errorf("bind contact session failed"))
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Join(err, fmt.Errorf("bind contact session failed"))
+	}
+	return rows > 0, nil
+}
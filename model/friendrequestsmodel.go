@@ -0,0 +1,117 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ FriendRequestsModel = (*customFriendRequestsModel)(nil)
+
+type (
+	// FriendRequestsModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customFriendRequestsModel.
+	FriendRequestsModel interface {
+		friendRequestsModel
+		withSession(session sqlx.Session) FriendRequestsModel
+		FindByUuid(ctx context.Context, uuid string) (*FriendRequests, error)
+		FindPending(ctx context.Context, fromUuid string, toUuid string) (*FriendRequests, error)
+		FindRecentByUserUuid(ctx context.Context, userUuid string, limit int64) ([]*FriendRequests, error)
+		CreateRequest(ctx context.Context, data *FriendRequests) error
+		UpdateStatus(ctx context.Context, tx sqlx.Session, uuid string, fromStatus int64, toStatus int64) (bool, error)
+	}
+
+	customFriendRequestsModel struct {
+		*defaultFriendRequestsModel
+	}
+)
+
+// 好友申请状态
+const (
+	FriendRequestStatusPending  = 1 // 待处理
+	FriendRequestStatusAccepted = 2 // 已同意
+	FriendRequestStatusRejected = 3 // 已拒绝
+)
+
+// NewFriendRequestsModel returns a model for the database table.
+func NewFriendRequestsModel(conn sqlx.SqlConn) FriendRequestsModel {
+	return &customFriendRequestsModel{
+		defaultFriendRequestsModel: newFriendRequestsModel(conn),
+	}
+}
+
+func (m *customFriendRequestsModel) withSession(session sqlx.Session) FriendRequestsModel {
+	return NewFriendRequestsModel(sqlx.NewSqlConnFromSession(session))
+}
+
+// 根据申请UUID查询好友申请
+func (m *customFriendRequestsModel) FindByUuid(ctx context.Context, uuid string) (*FriendRequests, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE uuid = ? LIMIT 1", friendRequestsRows, m.table)
+	var resp FriendRequests
+	err := m.conn.QueryRowCtx(ctx, &resp, query, uuid)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, errors.Join(err, fmt.Errorf("find friend request by uuid %s failed", uuid))
+	}
+	return &resp, nil
+}
+
+// 查询两个用户间待处理的好友申请
+func (m *customFriendRequestsModel) FindPending(ctx context.Context, fromUuid string, toUuid string) (*FriendRequests, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE from_uuid = ? AND to_uuid = ? AND status = ? ORDER BY id DESC LIMIT 1", friendRequestsRows, m.table)
+	var resp FriendRequests
+	err := m.conn.QueryRowCtx(ctx, &resp, query, fromUuid, toUuid, FriendRequestStatusPending)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, errors.Join(err, fmt.Errorf("find pending friend request from %s to %s failed", fromUuid, toUuid))
+	}
+	return &resp, nil
+}
+
+// 查询用户最近发出和收到的好友申请，按时间倒序
+func (m *customFriendRequestsModel) FindRecentByUserUuid(ctx context.Context, userUuid string, limit int64) ([]*FriendRequests, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE from_uuid = ? OR to_uuid = ? ORDER BY id DESC LIMIT ?", friendRequestsRows, m.table)
+	var resp []*FriendRequests
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, userUuid, userUuid, limit)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("find friend requests by user uuid %s failed", userUuid))
+	}
+	return resp, nil
+}
+
+// 创建好友申请
+func (m *customFriendRequestsModel) CreateRequest(ctx context.Context, data *FriendRequests) error {
+	query := fmt.Sprintf("INSERT INTO %s (uuid, from_uuid, to_uuid, message, status) VALUES (?, ?, ?, ?, ?)", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, data.Uuid, data.FromUuid, data.ToUuid, data.Message, data.Status)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("create friend request failed"))
+	}
+	return nil
+}
+
+// 修改申请状态，只有当前状态为fromStatus时修改成功，用于防止重复处理
+func (m *customFriendRequestsModel) UpdateStatus(ctx context.Context, tx sqlx.Session, uuid string, fromStatus int64, toStatus int64) (bool, error) {
+	var conn sqlx.Session
+	if tx == nil {
+		conn = m.conn
+	} else {
+		conn = tx
+	}
+	query := fmt.Sprintf("UPDATE %s SET status = ? WHERE uuid = ? AND status = ?", m.table)
+	result, err := conn.ExecCtx(ctx, query, toStatus, uuid, fromStatus)
+	if err != nil {
+		return false, errors.Join(err, fmt.Errorf("update friend request %s status failed", uuid))
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Join(err, fmt.Errorf("update friend request %s status failed", uuid))
+	}
+	return rows > 0, nil
+}
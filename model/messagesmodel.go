@@ -19,6 +19,9 @@ type (
 		withSession(session sqlx.Session) MessagesModel
 		FindLatestMessageBySessionUuid(ctx context.Context, sessionUuid string) (*Messages, error)
 		FindMessagesBySeqidGreaterThan(ctx context.Context, sessionUuid string, startSeqid int64) ([]*Messages, error)
+		FindBySenderUuidAndClientMsgId(ctx context.Context, senderUuid string, clientMsgId string) (*Messages, error)
+		FindMessagesBySeqidRange(ctx context.Context, sessionUuid string, startSeqid int64, endSeqid int64) ([]*Messages, error)
+		FindMaxSeqidBySessionUuid(ctx context.Context, sessionUuid string) (int64, error)
 	}
 
 	customMessagesModel struct {
@@ -28,6 +31,7 @@ type (
 
 const (
 	MessageTypeText = 1 // 文本消息
+	MessageTypeSystem = 2 // 系统消息 如成员变更
 )
 
 const (
@@ -36,6 +40,16 @@ const (
 	MessageStatusRead = 3 // 已读
 )
 
+// IsDuplicateClientMsgId 是否为发送者和客户端消息ID的唯一索引冲突
+func IsDuplicateClientMsgId(err error) bool {
+	return isDuplicateKey(err, "idx_messages_sender_uuid_client_msg_id")
+}
+
+// IsDuplicateSeqId 是否为会话序列号的唯一索引冲突
+func IsDuplicateSeqId(err error) bool {
+	return isDuplicateKey(err, "idx_messages_session_uuid_seq_id")
+}
+
 // NewMessagesModel returns a model for the database table.
 func NewMessagesModel(conn sqlx.SqlConn) MessagesModel {
 	return &customMessagesModel{
@@ -74,3 +88,42 @@ func (m *customMessagesModel) FindMessagesBySeqidGreaterThan(ctx context.Context
 	}
 	return resp, nil
 }
+
+// 根据发送者和客户端消息ID查询消息，用于重复请求去重
+func (m *customMessagesModel) FindBySenderUuidAndClientMsgId(ctx context.Context, senderUuid string, clientMsgId string) (*Messages, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE sender_uuid = ? AND client_msg_id = ? LIMIT 1", m.table)
+	var resp Messages
+	err := m.conn.QueryRowCtx(ctx, &resp, query, senderUuid, clientMsgId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, errors.Join(err, fmt.Errorf("find message by sender uuid %s and client msg id %s failed", senderUuid, clientMsgId))
+	}
+	return &resp, nil
+}
+
+// 查询序列号区间(startSeqid, endSeqid]内的消息列表
+func (m *customMessagesModel) FindMessagesBySeqidRange(ctx context.Context, sessionUuid string, startSeqid int64, endSeqid int64) ([]*Messages, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE session_uuid = ? AND seq_id > ? AND seq_id <= ? ORDER BY seq_id ASC", m.table)
+	var resp []*Messages
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, sessionUuid, startSeqid, endSeqid)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, errors.Join(err, fmt.Errorf("find messages by seqid range (%d, %d] failed", startSeqid, endSeqid))
+	}
+	return resp, nil
+}
+
+// 查询会话当前最大的序列号，没有消息时返回0
+func (m *customMessagesModel) FindMaxSeqidBySessionUuid(ctx context.Context, sessionUuid string) (int64, error) {
+	query := fmt.Sprintf("SELECT COALESCE(MAX(seq_id), 0) FROM %s WHERE session_uuid = ?", m.table)
+	var resp int64
+	err := m.conn.QueryRowCtx(ctx, &resp, query, sessionUuid)
+	if err != nil {
+		return 0, errors.Join(err, fmt.Errorf("find max seqid by session uuid %s failed", sessionUuid))
+	}
+	return resp, nil
+}
@@ -45,6 +45,7 @@ type (
 		MessageType int64     `db:"message_type"`
 		Status      int64     `db:"status"`
 		Content     string    `db:"content"`
+		ClientMsgId string    `db:"client_msg_id"`
 		CreatedAt   time.Time `db:"created_at"`
 		UpdatedAt   time.Time `db:"updated_at"`
 	}
@@ -78,14 +79,14 @@ func (m *defaultMessagesModel) FindOne(ctx context.Context, id int64) (*Messages
 }
 
 func (m *defaultMessagesModel) Insert(ctx context.Context, data *Messages) (sql.Result, error) {
-	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?, ?, ?)", m.table, messagesRowsExpectAutoSet)
-	ret, err := m.conn.ExecCtx(ctx, query, data.Uuid, data.SessionUuid, data.SenderUuid, data.SeqId, data.MessageType, data.Status, data.Content)
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?, ?, ?, ?)", m.table, messagesRowsExpectAutoSet)
+	ret, err := m.conn.ExecCtx(ctx, query, data.Uuid, data.SessionUuid, data.SenderUuid, data.SeqId, data.MessageType, data.Status, data.Content, data.ClientMsgId)
 	return ret, err
 }
 
 func (m *defaultMessagesModel) Update(ctx context.Context, data *Messages) error {
 	query := fmt.Sprintf("update %s set %s where `id` = ?", m.table, messagesRowsWithPlaceHolder)
-	_, err := m.conn.ExecCtx(ctx, query, data.Uuid, data.SessionUuid, data.SenderUuid, data.SeqId, data.MessageType, data.Status, data.Content, data.Id)
+	_, err := m.conn.ExecCtx(ctx, query, data.Uuid, data.SessionUuid, data.SenderUuid, data.SeqId, data.MessageType, data.Status, data.Content, data.ClientMsgId, data.Id)
 	return err
 }
 
@@ -19,8 +19,14 @@ type (
 		sessionMembersModel
 		withSession(session sqlx.Session) SessionMembersModel
 		FindSessionsByUserUuid(ctx context.Context, userUuid string) ([]string, error)
-		JoinSession(ctx context.Context, tx sqlx.Session, sessionUuid string, userUuid string) error
+		JoinSession(ctx context.Context, tx sqlx.Session, sessionUuid string, userUuid string, role int64) error
 		FindAllMembersBySessionUuid(ctx context.Context, sessionUuid string) ([]string, error)
+		FindMembersBySessionUuid(ctx context.Context, sessionUuid string) ([]*SessionMembers, error)
+		FindMembersForUpdate(ctx context.Context, tx sqlx.Session, sessionUuid string) ([]*SessionMembers, error)
+		LeaveSession(ctx context.Context, tx sqlx.Session, sessionUuid string, userUuid string) error
+		UpdateRole(ctx context.Context, tx sqlx.Session, sessionUuid string, userUuid string, role int64) error
+		DeleteBySessionUuid(ctx context.Context, tx sqlx.Session, sessionUuid string) error
+		FindSingleSession(ctx context.Context, userUuid string, otherUuid string) (string, error)
 	}
 
 	customSessionMembersModel struct {
@@ -28,6 +34,13 @@ type (
 	}
 )
 
+// 成员角色
+const (
+	MemberRoleOwner  = 1 // 群主
+	MemberRoleAdmin  = 2 // 管理员
+	MemberRoleMember = 3 // 普通成员
+)
+
 // NewSessionMembersModel returns a model for the database table.
 func NewSessionMembersModel(conn sqlx.SqlConn) SessionMembersModel {
 	return &customSessionMembersModel{
@@ -54,15 +67,15 @@ func (m *customSessionMembersModel) FindSessionsByUserUuid(ctx context.Context,
 }
 
 // 加入会话
-func (m *customSessionMembersModel) JoinSession(ctx context.Context, tx sqlx.Session, sessionUuid string, userUuid string) error {
+func (m *customSessionMembersModel) JoinSession(ctx context.Context, tx sqlx.Session, sessionUuid string, userUuid string, role int64) error {
 	var conn sqlx.Session
 	if tx == nil {
 		conn = m.conn
 	} else {
 		conn = tx
 	}
-	query := fmt.Sprintf("INSERT INTO %s (session_uuid, user_uuid) VALUES (?, ?)", m.table)
-	_, err := conn.ExecCtx(ctx, query, sessionUuid, userUuid)
+	query := fmt.Sprintf("INSERT INTO %s (session_uuid, user_uuid, role) VALUES (?, ?, ?)", m.table)
+	_, err := conn.ExecCtx(ctx, query, sessionUuid, userUuid, role)
 	if err != nil {
 		return errors.Join(err, fmt.Errorf("join session failed"))
 	}
@@ -79,3 +92,94 @@ func (m *customSessionMembersModel) FindAllMembersBySessionUuid(ctx context.Cont
 	}
 	return resp, nil
 }
+
+// 查找会话中的所有成员及角色，按加入顺序排列
+func (m *customSessionMembersModel) FindMembersBySessionUuid(ctx context.Context, sessionUuid string) ([]*SessionMembers, error) {
+	var resp []*SessionMembers
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE session_uuid = ? ORDER BY id ASC", sessionMembersRows, m.table)
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, sessionUuid)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("find members by session uuid %s failed", sessionUuid))
+	}
+	return resp, nil
+}
+
+// 在事务中查找会话的所有成员并加锁，按加入顺序排列，并发修改成员时基于最新成员判断
+func (m *customSessionMembersModel) FindMembersForUpdate(ctx context.Context, tx sqlx.Session, sessionUuid string) ([]*SessionMembers, error) {
+	var conn sqlx.Session
+	if tx == nil {
+		conn = m.conn
+	} else {
+		conn = tx
+	}
+	var resp []*SessionMembers
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE session_uuid = ? ORDER BY id ASC FOR UPDATE", sessionMembersRows, m.table)
+	err := conn.QueryRowsCtx(ctx, &resp, query, sessionUuid)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("find members for update by session uuid %s failed", sessionUuid))
+	}
+	return resp, nil
+}
+
+// 退出会话
+func (m *customSessionMembersModel) LeaveSession(ctx context.Context, tx sqlx.Session, sessionUuid string, userUuid string) error {
+	var conn sqlx.Session
+	if tx == nil {
+		conn = m.conn
+	} else {
+		conn = tx
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE session_uuid = ? AND user_uuid = ?", m.table)
+	_, err := conn.ExecCtx(ctx, query, sessionUuid, userUuid)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("leave session failed"))
+	}
+	return nil
+}
+
+// 修改成员角色
+func (m *customSessionMembersModel) UpdateRole(ctx context.Context, tx sqlx.Session, sessionUuid string, userUuid string, role int64) error {
+	var conn sqlx.Session
+	if tx == nil {
+		conn = m.conn
+	} else {
+		conn = tx
+	}
+	query := fmt.Sprintf("UPDATE %s SET role = ? WHERE session_uuid = ? AND user_uuid = ?", m.table)
+	_, err := conn.ExecCtx(ctx, query, role, sessionUuid, userUuid)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("update member role failed"))
+	}
+	return nil
+}
+
+// 删除会话的所有成员
+func (m *customSessionMembersModel) DeleteBySessionUuid(ctx context.Context, tx sqlx.Session, sessionUuid string) error {
+	var conn sqlx.Session
+	if tx == nil {
+		conn = m.conn
+	} else {
+		conn = tx
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE session_uuid = ?", m.table)
+	_, err := conn.ExecCtx(ctx, query, sessionUuid)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("delete members by session uuid %s failed", sessionUuid))
+	}
+	return nil
+}
+
+// 查找两个用户间已有的单聊会话，没有时返回空字符串
+func (m *customSessionMembersModel) FindSingleSession(ctx context.Context, userUuid string, otherUuid string) (string, error) {
+	query := fmt.Sprintf("SELECT a.session_uuid FROM %s a JOIN %s b ON a.session_uuid = b.session_uuid JOIN `sessions` s ON s.uuid = a.session_uuid "+
+		"WHERE a.user_uuid = ? AND b.user_uuid = ? AND s.session_type = ? AND s.status = ? ORDER BY s.id ASC LIMIT 1", m.table, m.table)
+	var resp string
+	err := m.conn.QueryRowCtx(ctx, &resp, query, userUuid, otherUuid, SessionTypeSingle, SessionStatusActive)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", errors.Join(err, fmt.Errorf("find single session between %s and %s failed", userUuid, otherUuid))
+	}
+	return resp, nil
+}
@@ -40,6 +40,7 @@ type (
 		Id          int64     `db:"id"`
 		SessionUuid string    `db:"session_uuid"`
 		UserUuid    string    `db:"user_uuid"`
+		Role        int64     `db:"role"`
 		CreatedAt   time.Time `db:"created_at"`
 		UpdatedAt   time.Time `db:"updated_at"`
 	}
@@ -73,14 +74,14 @@ func (m *defaultSessionMembersModel) FindOne(ctx context.Context, id int64) (*Se
 }
 
 func (m *defaultSessionMembersModel) Insert(ctx context.Context, data *SessionMembers) (sql.Result, error) {
-	query := fmt.Sprintf("insert into %s (%s) values (?, ?)", m.table, sessionMembersRowsExpectAutoSet)
-	ret, err := m.conn.ExecCtx(ctx, query, data.SessionUuid, data.UserUuid)
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?)", m.table, sessionMembersRowsExpectAutoSet)
+	ret, err := m.conn.ExecCtx(ctx, query, data.SessionUuid, data.UserUuid, data.Role)
 	return ret, err
 }
 
 func (m *defaultSessionMembersModel) Update(ctx context.Context, data *SessionMembers) error {
 	query := fmt.Sprintf("update %s set %s where `id` = ?", m.table, sessionMembersRowsWithPlaceHolder)
-	_, err := m.conn.ExecCtx(ctx, query, data.SessionUuid, data.UserUuid, data.Id)
+	_, err := m.conn.ExecCtx(ctx, query, data.SessionUuid, data.UserUuid, data.Role, data.Id)
 	return err
 }
 
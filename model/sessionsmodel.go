@@ -19,6 +19,8 @@ type (
 		withSession(session sqlx.Session) SessionsModel
 		FindByUuid(ctx context.Context, uuid string) (*Sessions, error)
 		CreateSession(ctx context.Context,tx sqlx.Session, session *Sessions) error
+		UpdateProfile(ctx context.Context, uuid string, name string, avatar string) error
+		UpdateStatus(ctx context.Context, tx sqlx.Session, uuid string, status int64) error
 	}
 
 	customSessionsModel struct {
@@ -77,3 +79,29 @@ func (m *customSessionsModel) CreateSession(ctx context.Context,tx sqlx.Session,
 	}
 	return nil
 }
+
+// 修改会话名称和头像
+func (m *customSessionsModel) UpdateProfile(ctx context.Context, uuid string, name string, avatar string) error {
+	query := fmt.Sprintf("UPDATE %s SET name = ?, avatar = ? WHERE uuid = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, name, avatar, uuid)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("update session %s profile failed", uuid))
+	}
+	return nil
+}
+
+// 修改会话状态
+func (m *customSessionsModel) UpdateStatus(ctx context.Context, tx sqlx.Session, uuid string, status int64) error {
+	var conn sqlx.Session
+	if tx == nil {
+		conn = m.conn
+	} else {
+		conn = tx
+	}
+	query := fmt.Sprintf("UPDATE %s SET status = ? WHERE uuid = ?", m.table)
+	_, err := conn.ExecCtx(ctx, query, status, uuid)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("update session %s status failed", uuid))
+	}
+	return nil
+}
@@ -21,7 +21,7 @@ type (
 		FindByUuid(ctx context.Context, uuid string) (*UserBase, error)
 		FindByUuids(ctx context.Context, uuids []string) ([]*UserBase, error)
 		RegisterUserBase(ctx context.Context, tx sqlx.Session, data *UserBase) error
-		FindAll(ctx context.Context) ([]*UserBase, error)
+		SearchByName(ctx context.Context, keyword string, limit int64) ([]*UserBase, error)
 	}
 
 	customUserBaseModel struct {
@@ -84,16 +84,13 @@ func (m *customUserBaseModel) RegisterUserBase(ctx context.Context, tx sqlx.Sess
 	return err
 }
 
-// 查询所有用户基本信息
-func (m *customUserBaseModel) FindAll(ctx context.Context) ([]*UserBase, error) {
-	query := fmt.Sprintf("SELECT * FROM %s", m.table)
+// 按用户名前缀搜索用户
+func (m *customUserBaseModel) SearchByName(ctx context.Context, keyword string, limit int64) ([]*UserBase, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE name LIKE ? AND status = ? ORDER BY id ASC LIMIT ?", m.table)
 	var resp []*UserBase
-	err := m.conn.QueryRowsCtx(ctx, &resp, query)
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, escapeLike(keyword)+"%", UserStatusActive, limit)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, errors.Join(err, fmt.Errorf("find all user base failed"))
+		return nil, errors.Join(err, fmt.Errorf("search user base by name %s failed", keyword))
 	}
 	return resp, nil
 }
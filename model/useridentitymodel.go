@@ -19,6 +19,7 @@ type (
 		withSession(session sqlx.Session) UserIdentityModel
 		FindByIdentifierAndIdentityType(ctx context.Context, identifier string, identityType int64) (*UserIdentity, error)
 		RegisterUserIdentity(ctx context.Context, tx sqlx.Session, identity *UserIdentity) error
+		FindUserUuidsByIdentifier(ctx context.Context, identifier string) ([]string, error)
 	}
 
 	customUserIdentityModel struct {
@@ -73,3 +74,14 @@ func (m *customUserIdentityModel) RegisterUserIdentity(ctx context.Context, tx s
 	_, err := conn.ExecCtx(ctx, "insert into user_identity (user_uuid, identity_type, identifier, credential) values (?, ?, ?, ?)", identity.UserUuid, identity.IdentityType, identity.Identifier, identity.Credential)
 	return err
 }
+
+// 根据手机号、邮箱或用户名精确查询用户UUID
+func (m *customUserIdentityModel) FindUserUuidsByIdentifier(ctx context.Context, identifier string) ([]string, error) {
+	query := fmt.Sprintf("SELECT DISTINCT user_uuid FROM %s WHERE identifier = ? AND identity_type in (?, ?, ?)", m.table)
+	resp := []string{}
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, identifier, IdentityTypePhone, IdentityTypeEmail, IdentityTypePassword)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("find user uuids by identifier %s failed", identifier))
+	}
+	return resp, nil
+}
@@ -16,32 +16,80 @@ type Config struct {
 }
 
 type ClientConfig struct {
-	Mode           string `env:"MODE" default:"dev"`
-	APIGatewayAddr string `env:"API_ADDR" default:"localhost:8088"`
-	IMGatewayAddr  string `env:"GATEWAY_ADDR" default:"localhost:8086"`
-	DiscoveryAddr  string `env:"DISCOVERY_ADDR" default:"localhost:8085"`
+	Mode              string    `env:"MODE" default:"dev"`
+	APIGatewayAddr    string    `env:"API_ADDR" default:"localhost:8088"`
+	IMGatewayAddr     string    `env:"GATEWAY_ADDR" default:"localhost:8086"`
+	DiscoveryAddr     string    `env:"DISCOVERY_ADDR" default:"localhost:8085"`
+	HeartbeatInterval int       `env:"HEARTBEAT_INTERVAL" default:"30"` // 心跳间隔 秒
+	AckTimeout        int       `env:"ACK_TIMEOUT" default:"5"`         // 上行消息ACK超时 秒，超时后重发
+	DeviceId          string    `env:"DEVICE_ID"`                       // 设备ID 为空时使用主机名
+	Platform          string    `env:"PLATFORM" default:"desktop"`      // 平台 desktop/mobile/web
+	Zone              string    `env:"ZONE"`                            // 所在可用区 优先连接同可用区的网关
+	TLSConfig         TLSConfig `env:"TLS"`
 }
 
 type IMGatewayConfig struct {
-	Mode              string      `env:"MODE" default:"dev"`
-	Addr              string      `env:"ADDR" default:":8086"`
-	RpcAddr           string      `env:"RPC_ADDR" default:"localhost:8087"`
-	RedisConfig       RedisConfig `env:"REDIS"`
-	DiscoveryEndpoint string      `env:"DISCOVERY_ENDPOINT" default:"localhost:8085"`
-	APIGatewayAddr    string      `env:"API_ADDR" default:"localhost:8088"`
+	Mode               string      `env:"MODE" default:"dev"`
+	Addr               string      `env:"ADDR" default:":8086"`
+	Transports         string      `env:"TRANSPORTS" default:"tcp"`       // 启用的长连接传输 tcp/websocket，逗号分隔
+	WebSocketAddr      string      `env:"WEBSOCKET_ADDR" default:":8089"` // WebSocket监听地址
+	WebSocketPath      string      `env:"WEBSOCKET_PATH" default:"/ws"`   // WebSocket升级路径
+	RpcAddr            string      `env:"RPC_ADDR" default:"localhost:8087"`
+	AdvertiseAddr      string      `env:"ADVERTISE_ADDR"`     // 注册到发现服务的长连接地址 为空时使用本机IP和监听端口
+	RpcAdvertiseAddr   string      `env:"RPC_ADVERTISE_ADDR"` // 写入路由表的RPC地址，其他网关据此转发消息 为空时使用本机IP和RPC监听端口
+	Weight             int         `env:"WEIGHT" default:"1"` // 注册到发现服务的权重
+	Zone               string      `env:"ZONE"`               // 所在可用区
+	Version            string      `env:"VERSION"`            // 版本 canary表示灰度实例
+	RedisConfig        RedisConfig `env:"REDIS"`
+	TLSConfig          TLSConfig   `env:"TLS"`           // 长连接和gRPC服务端、客户端共用
+	ServiceToken       string      `env:"SERVICE_TOKEN"` // RPC服务令牌，运维和网关间调用需携带 非dev模式下未启用mTLS校验客户端时必须配置
+	DiscoveryEndpoint  string      `env:"DISCOVERY_ENDPOINT" default:"localhost:8085"`
+	APIGatewayTarget   string      `env:"API_TARGET" default:"discovery:///apigateway"` // API网关的gRPC目标 也可配置固定地址
+	MaxVarHeaderLen    int         `env:"MAX_VAR_HEADER_LEN" default:"4096"`            // 长连接帧可变头上限
+	MaxBodyLen         int         `env:"MAX_BODY_LEN" default:"4194304"`               // 长连接帧消息体上限
+	HeartbeatInterval  int         `env:"HEARTBEAT_INTERVAL" default:"30"`              // 客户端心跳间隔 秒
+	HeartbeatMaxMiss   int         `env:"HEARTBEAT_MAX_MISS" default:"3"`               // 允许丢失的心跳次数，超过后驱逐连接
+	AckTimeout         int         `env:"ACK_TIMEOUT" default:"5"`                      // 下行消息ACK超时 秒，超时后重传
+	MaxRetransmit      int         `env:"MAX_RETRANSMIT" default:"3"`                   // 最大重传次数，超过后断开连接由客户端重连补拉
+	AckWindow          int         `env:"ACK_WINDOW" default:"1024"`                    // 单连接待确认消息上限
+	ExclusivePlatforms string      `env:"EXCLUSIVE_PLATFORMS" default:"desktop"`        // 同一用户只允许一个连接的平台，逗号分隔，新连接踢掉旧连接
+	SessionCacheTTL    int         `env:"SESSION_CACHE_TTL" default:"60"`               // 会话成员缓存有效期 秒
+	WriteQueueSize     int         `env:"WRITE_QUEUE_SIZE" default:"256"`               // 单连接写队列长度
+	WriteTimeout       int         `env:"WRITE_TIMEOUT" default:"10"`                   // 写超时 秒，超时后断开连接
+	WriteQueuePolicy   string      `env:"WRITE_QUEUE_POLICY" default:"disconnect"`      // 写队列满时的策略 disconnect: 断开连接 drop: 丢弃下行消息并通知客户端补拉
+	ShutdownTimeout    int         `env:"SHUTDOWN_TIMEOUT" default:"30"`                // 优雅退出超时 秒，包括通知客户端重连和排空写队列
 }
 
 type DiscoveryConfig struct {
-	Mode        string      `env:"MODE" default:"dev"`
-	Addr        string      `env:"ADDR" default:":8085"`
-	RedisConfig RedisConfig `env:"REDIS"`
+	Mode                 string      `env:"MODE" default:"dev"`
+	Addr                 string      `env:"ADDR" default:":8085"`
+	Storage              string      `env:"STORAGE" default:"redis"` // 注册信息存储 redis: 多副本共享 memory: 只支持单副本，不依赖Redis
+	RedisConfig          RedisConfig `env:"REDIS"`
+	TLSConfig            TLSConfig   `env:"TLS"`
+	ShutdownTimeout      int         `env:"SHUTDOWN_TIMEOUT" default:"30"`          // 优雅退出超时 秒
+	LeaseTTL             int         `env:"LEASE_TTL" default:"30"`                 // 默认租约有效期 秒，到期未续约的实例被清除
+	ReconcileInterval    int         `env:"RECONCILE_INTERVAL" default:"1"`         // 用存储校正本地缓存的间隔 秒，同时刷新实例负载
+	LoadBalance          string      `env:"LOAD_BALANCE" default:"consistent_hash"` // GetServiceIP的负载均衡策略 round_robin/consistent_hash/weighted_round_robin/least_conn/p2c
+	ServiceLoadBalance   string      `env:"SERVICE_LOAD_BALANCE"`                   // 按服务指定策略 如 imgateway=p2c,apigateway=round_robin
+	ServiceCanary        string      `env:"SERVICE_CANARY"`                         // 按服务指定灰度比例 如 imgateway=10 表示10%的client_key路由到version=canary的实例
+	HealthCheckInterval  int         `env:"HEALTH_CHECK_INTERVAL" default:"10"`     // 主动健康检查间隔 秒，0表示不检查
+	HealthCheckTimeout   int         `env:"HEALTH_CHECK_TIMEOUT" default:"3"`       // 单次健康检查超时 秒
+	HealthCheckThreshold int         `env:"HEALTH_CHECK_THRESHOLD" default:"3"`     // 连续失败次数，达到后标记为不健康
+	ServiceHealthCheck   string      `env:"SERVICE_HEALTH_CHECK"`                   // 按服务指定 间隔:失败次数 如 imgateway=5:2,apigateway=0 间隔为0表示不检查
 }
 
 type APIGatewayConfig struct {
-	Mode        string      `env:"MODE" default:"dev"`
-	Addr        string      `env:"ADDR" default:":8088"`
-	RedisConfig RedisConfig `env:"REDIS"`
-	MysqlConfig MysqlConfig `env:"MYSQL"`
+	Mode              string      `env:"MODE" default:"dev"`
+	Addr              string      `env:"ADDR" default:":8088"`
+	AdvertiseAddr     string      `env:"ADVERTISE_ADDR"`     // 注册到发现服务的地址 为空时使用本机IP和监听端口
+	Weight            int         `env:"WEIGHT" default:"1"` // 注册到发现服务的权重
+	Zone              string      `env:"ZONE"`               // 所在可用区
+	Version           string      `env:"VERSION"`            // 版本 canary表示灰度实例
+	DiscoveryEndpoint string      `env:"DISCOVERY_ENDPOINT" default:"localhost:8085"`
+	RedisConfig       RedisConfig `env:"REDIS"`
+	MysqlConfig       MysqlConfig `env:"MYSQL"`
+	TLSConfig         TLSConfig   `env:"TLS"`
+	ShutdownTimeout   int         `env:"SHUTDOWN_TIMEOUT" default:"30"` // 优雅退出超时 秒
 }
 type MysqlConfig struct {
 	Addr     string `env:"ADDR" default:"127.0.0.1:3306"`
@@ -56,6 +104,17 @@ type RedisConfig struct {
 	DB       int    `env:"DB" default:"0"`
 }
 
+// TLSConfig 服务端使用证书和私钥，配置CA时校验客户端证书（mTLS）
+// 客户端使用CA校验服务端，配置证书和私钥时向服务端出示
+type TLSConfig struct {
+	Enabled        bool   `env:"ENABLED" default:"false"`
+	CertFile       string `env:"CERT_FILE"`
+	KeyFile        string `env:"KEY_FILE"`
+	CAFile         string `env:"CA_FILE"`
+	ServerName     string `env:"SERVER_NAME"`                  // 客户端校验的服务端名称 为空时使用连接地址
+	ReloadInterval int    `env:"RELOAD_INTERVAL" default:"60"` // 证书文件变更检测间隔 秒，0表示不检测
+}
+
 func NewConf() *Config {
 	conf := Config{}
 	Unmarshal(&conf)
@@ -0,0 +1,39 @@
+package event
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 服务间通过Redis发布订阅传递的事件频道
+const (
+	ChannelSessionMembers = "im:session:members" // 会话成员变更 消息内容为会话UUID
+)
+
+// PublishSessionMembersChanged 通知所有网关会话成员已变更，需要丢弃缓存
+func PublishSessionMembersChanged(ctx context.Context, redisClient *redis.Client, sessionUuid string) error {
+	return redisClient.Publish(ctx, ChannelSessionMembers, sessionUuid).Err()
+}
+
+// SubscribeSessionMembersChanged 订阅会话成员变更，直到ctx取消
+func SubscribeSessionMembersChanged(ctx context.Context, redisClient *redis.Client, handler func(sessionUuid string)) error {
+	pubsub := redisClient.Subscribe(ctx, ChannelSessionMembers)
+	defer pubsub.Close()
+	// 等待订阅生效，避免订阅前发布的事件丢失
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			handler(msg.Payload)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package graceful
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// NotifyContext 收到SIGINT或SIGTERM时取消返回的ctx
+func NotifyContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+}
+
+// StopGRPC 等待进行中的请求完成后停止服务，超时后强制停止，返回是否正常完成
+func StopGRPC(server *grpc.Server, timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		server.Stop()
+		<-done
+		return false
+	}
+}
+
+// RegisterHealth 注册grpc.health.v1健康检查服务，初始状态为SERVING
+// 退出时先调用Shutdown标记为NOT_SERVING，发现服务的健康检查和客户端不再选择该实例
+func RegisterHealth(server *grpc.Server) *health.Server {
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	return healthServer
+}
@@ -22,6 +22,7 @@ var isIgnoreMethods = []string{
 	"/apigateway.APIGateway/Register",
 	"/apigateway.APIGateway/GetSessionUserList",
 	"/apigateway.APIGateway/SendMessage",
+	"/grpc.health.v1.Health/Check",
 }
 
 func jwtUnaryInterceptor(ctx context.Context, logger *slog.Logger, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
@@ -0,0 +1,40 @@
+package grpcmiddreware
+
+import (
+	"context"
+	"crypto/subtle"
+	"slices"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceTokenKey 服务间调用携带令牌的metadata键
+const ServiceTokenKey = "service-token"
+
+// ServiceTokenUnaryInterceptor 校验methods中方法的服务令牌，token为空时不校验
+func ServiceTokenUnaryInterceptor(token string, methods ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if len(token) == 0 || !slices.Contains(methods, info.FullMethod) {
+			return handler(ctx, req)
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get(ServiceTokenKey)
+		if len(values) == 0 || subtle.ConstantTimeCompare([]byte(values[0]), []byte(token)) != 1 {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid service token")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// ServiceTokenClientInterceptor 调用时附加服务令牌，token为空时不附加
+func ServiceTokenClientInterceptor(token string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if len(token) > 0 {
+			ctx = metadata.AppendToOutgoingContext(ctx, ServiceTokenKey, token)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -1,19 +1,97 @@
 package loadbalance
 
 import (
-	"hash/fnv"
+	"cmp"
+	"hash/crc32"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
 )
 
+// 每个实例默认的虚拟节点数
+const DefaultVirtualNodes = 160
+
 // 一致性哈希负载均衡
-type ConsistentHashBalancer struct{}
+// 每个实例按 虚拟节点数*权重 在哈希环上放置节点，key顺时针遇到的第一个节点所属的实例即为选中实例
+// 实例增减时只有该实例负责的key会迁移，其他key保持不变
+type ConsistentHashBalancer struct {
+	virtualNodes int
+	mu           sync.RWMutex
+	ring         *hashRing // 最近一次实例列表对应的哈希环，实例不变时复用
+}
+
+type hashRing struct {
+	instances []Instance
+	hashes    []uint32 // 升序
+	owners    []int    // 与hashes一一对应的实例下标
+}
+
+// NewConsistentHashBalancer virtualNodes为权重1的实例的虚拟节点数，小于等于0时使用默认值
+func NewConsistentHashBalancer(virtualNodes int) *ConsistentHashBalancer {
+	if virtualNodes <= 0 {
+		virtualNodes = DefaultVirtualNodes
+	}
+	return &ConsistentHashBalancer{virtualNodes: virtualNodes}
+}
+
+func (c *ConsistentHashBalancer) Select(instances []Instance, key string) int {
+	if len(instances) == 0 {
+		return -1
+	}
+	ring := c.getRing(instances)
+	hash := crc32.ChecksumIEEE([]byte(key))
+	i := sort.Search(len(ring.hashes), func(i int) bool { return ring.hashes[i] >= hash })
+	if i == len(ring.hashes) {
+		i = 0
+	}
+	return ring.owners[i]
+}
+
+func (c *ConsistentHashBalancer) getRing(instances []Instance) *hashRing {
+	c.mu.RLock()
+	ring := c.ring
+	c.mu.RUnlock()
+	if ring != nil && sameRing(ring.instances, instances) {
+		return ring
+	}
+	ring = c.buildRing(instances)
+	c.mu.Lock()
+	c.ring = ring
+	c.mu.Unlock()
+	return ring
+}
 
-func NewConsistentHashBalancer() *ConsistentHashBalancer {
-	return &ConsistentHashBalancer{}
+// 哈希环只与实例地址、权重和顺序有关，负载等其他字段变化时不需要重建
+func sameRing(a, b []Instance) bool {
+	return slices.EqualFunc(a, b, func(x, y Instance) bool {
+		return x.Addr == y.Addr && x.Weight == y.Weight
+	})
 }
 
-func (c *ConsistentHashBalancer) Select(length int64, key string) int64 {
-	hash := fnv.New32a()
-	hash.Write([]byte(key))
-	index := hash.Sum32() % uint32(length)
-	return int64(index)
+func (c *ConsistentHashBalancer) buildRing(instances []Instance) *hashRing {
+	type node struct {
+		hash  uint32
+		owner int
+	}
+	nodes := make([]node, 0, len(instances)*c.virtualNodes)
+	for i, instance := range instances {
+		for j := range c.virtualNodes * weightOf(instance) {
+			nodes = append(nodes, node{hash: crc32.ChecksumIEEE([]byte(instance.Addr + "#" + strconv.Itoa(j))), owner: i})
+		}
+	}
+	// 哈希冲突时按地址排序，保证结果与实例顺序无关
+	slices.SortFunc(nodes, func(a, b node) int {
+		return cmp.Or(cmp.Compare(a.hash, b.hash), cmp.Compare(instances[a.owner].Addr, instances[b.owner].Addr))
+	})
+	ring := &hashRing{
+		instances: slices.Clone(instances),
+		hashes:    make([]uint32, len(nodes)),
+		owners:    make([]int, len(nodes)),
+	}
+	for i, n := range nodes {
+		ring.hashes[i] = n.hash
+		ring.owners[i] = n.owner
+	}
+	return ring
 }
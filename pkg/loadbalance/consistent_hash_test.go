@@ -0,0 +1,134 @@
+package loadbalance
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+const testKeys = 10000
+
+func newInstances(n int) []Instance {
+	instances := make([]Instance, 0, n)
+	for i := range n {
+		instances = append(instances, Instance{Addr: fmt.Sprintf("10.0.0.%d:8086", i+1)})
+	}
+	return instances
+}
+
+// 返回每个key选中的实例地址
+func assign(b LoadBalancer, instances []Instance) map[string]string {
+	result := make(map[string]string, testKeys)
+	for i := range testKeys {
+		key := fmt.Sprintf("user-%d", i)
+		result[key] = instances[b.Select(instances, key)].Addr
+	}
+	return result
+}
+
+func TestConsistentHashAddInstance(t *testing.T) {
+	b := NewConsistentHashBalancer(DefaultVirtualNodes)
+	instances := newInstances(10)
+	before := assign(b, instances)
+	added := append(slices.Clone(instances), Instance{Addr: "10.0.0.100:8086"})
+	after := assign(b, added)
+
+	moved := 0
+	for key, addr := range before {
+		if after[key] == addr {
+			continue
+		}
+		moved++
+		// 只会迁移到新实例
+		if after[key] != "10.0.0.100:8086" {
+			t.Fatalf("key %s moved from %s to existing instance %s", key, addr, after[key])
+		}
+	}
+	// 理想情况迁移1/11
+	if ratio := float64(moved) / testKeys; ratio < 0.05 || ratio > 0.15 {
+		t.Fatalf("expected about 9%% keys moved, got %.2f%%", ratio*100)
+	}
+}
+
+func TestConsistentHashRemoveInstance(t *testing.T) {
+	b := NewConsistentHashBalancer(DefaultVirtualNodes)
+	instances := newInstances(10)
+	before := assign(b, instances)
+	removed := instances[3]
+	after := assign(b, slices.Delete(slices.Clone(instances), 3, 4))
+
+	moved := 0
+	for key, addr := range before {
+		if after[key] == addr {
+			continue
+		}
+		moved++
+		// 只有被移除实例的key迁移
+		if addr != removed.Addr {
+			t.Fatalf("key %s moved from %s which was not removed", key, addr)
+		}
+	}
+	if ratio := float64(moved) / testKeys; ratio < 0.05 || ratio > 0.15 {
+		t.Fatalf("expected about 10%% keys moved, got %.2f%%", ratio*100)
+	}
+}
+
+func TestConsistentHashOrderIndependent(t *testing.T) {
+	instances := newInstances(5)
+	reversed := slices.Clone(instances)
+	slices.Reverse(reversed)
+	before := assign(NewConsistentHashBalancer(DefaultVirtualNodes), instances)
+	after := assign(NewConsistentHashBalancer(DefaultVirtualNodes), reversed)
+	for key, addr := range before {
+		if after[key] != addr {
+			t.Fatalf("key %s assigned to %s and %s", key, addr, after[key])
+		}
+	}
+}
+
+func TestConsistentHashWeight(t *testing.T) {
+	b := NewConsistentHashBalancer(DefaultVirtualNodes)
+	instances := newInstances(3)
+	instances[0].Weight = 3
+	counts := make(map[string]int)
+	for _, addr := range assign(b, instances) {
+		counts[addr]++
+	}
+	// 权重3的实例约占3/5
+	if ratio := float64(counts[instances[0].Addr]) / testKeys; ratio < 0.5 || ratio > 0.7 {
+		t.Fatalf("expected about 60%% keys on weighted instance, got %.2f%%", ratio*100)
+	}
+}
+
+func TestConsistentHashReuseRing(t *testing.T) {
+	b := NewConsistentHashBalancer(DefaultVirtualNodes)
+	instances := newInstances(3)
+	b.Select(instances, "key")
+	ring := b.ring
+
+	// 负载变化不重建哈希环
+	loaded := slices.Clone(instances)
+	for i := range loaded {
+		loaded[i].Load = int64(i * 100)
+	}
+	b.Select(loaded, "key")
+	if b.ring != ring {
+		t.Fatalf("expected ring reused when only load changed")
+	}
+
+	// 权重变化重建
+	loaded[0].Weight = 2
+	b.Select(loaded, "key")
+	if b.ring == ring {
+		t.Fatalf("expected ring rebuilt when weight changed")
+	}
+}
+
+func TestSelectEmpty(t *testing.T) {
+	if index := NewConsistentHashBalancer(0).Select(nil, "key"); index != -1 {
+		t.Fatalf("expected -1, got %d", index)
+	}
+	if index := NewRoundRobinBalancer().Select(nil, "key"); index != -1 {
+		t.Fatalf("expected -1, got %d", index)
+	}
+}
@@ -0,0 +1,28 @@
+package loadbalance
+
+import "sync/atomic"
+
+// 最少连接负载均衡，选择单位权重负载最小的实例
+// 负载由实例定期上报，上报之间负载相同的实例轮流选择，避免集中到同一实例
+type LeastConnBalancer struct {
+	counter uint64
+}
+
+func NewLeastConnBalancer() *LeastConnBalancer {
+	return &LeastConnBalancer{}
+}
+
+func (l *LeastConnBalancer) Select(instances []Instance, _ string) int {
+	if len(instances) == 0 {
+		return -1
+	}
+	offset := int(atomic.AddUint64(&l.counter, 1) % uint64(len(instances)))
+	best := offset
+	for i := 1; i < len(instances); i++ {
+		index := (offset + i) % len(instances)
+		if lessLoaded(instances[index], instances[best]) {
+			best = index
+		}
+	}
+	return best
+}
@@ -1,6 +1,52 @@
 package loadbalance
 
+import "fmt"
+
+// 负载均衡策略名称
+const (
+	StrategyRoundRobin         = "round_robin"
+	StrategyConsistentHash     = "consistent_hash"
+	StrategyWeightedRoundRobin = "weighted_round_robin"
+	StrategyLeastConn          = "least_conn"
+	StrategyP2C                = "p2c"
+)
+
+// Instance 参与负载均衡的实例
+type Instance struct {
+	Addr   string // 实例标识 address:port
+	Weight int    // 权重，小于等于0时视为1
+	Load   int64  // 实例上报的负载，如长连接数
+}
+
 // 负载均衡算法接口
+// Select 返回选中实例在instances中的下标，instances为空时返回-1
 type LoadBalancer interface {
-	Select(length int64, key string) int64
+	Select(instances []Instance, key string) int
+}
+
+// New 按策略名称创建负载均衡器
+func New(strategy string) (LoadBalancer, error) {
+	switch strategy {
+	case StrategyRoundRobin:
+		return NewRoundRobinBalancer(), nil
+	case StrategyConsistentHash:
+		return NewConsistentHashBalancer(DefaultVirtualNodes), nil
+	case StrategyWeightedRoundRobin:
+		return NewWeightedRoundRobinBalancer(), nil
+	case StrategyLeastConn:
+		return NewLeastConnBalancer(), nil
+	case StrategyP2C:
+		return NewP2CBalancer(), nil
+	default:
+		return nil, fmt.Errorf("unknown load balance strategy: %s", strategy)
+	}
+}
+
+func weightOf(instance Instance) int {
+	return max(instance.Weight, 1)
+}
+
+// a的单位权重负载是否小于b
+func lessLoaded(a Instance, b Instance) bool {
+	return a.Load*int64(weightOf(b)) < b.Load*int64(weightOf(a))
 }
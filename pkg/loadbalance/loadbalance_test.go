@@ -0,0 +1,89 @@
+package loadbalance
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWeightedRoundRobin(t *testing.T) {
+	b := NewWeightedRoundRobinBalancer()
+	instances := []Instance{{Addr: "a", Weight: 5}, {Addr: "b", Weight: 1}, {Addr: "c", Weight: 1}}
+	var order strings.Builder
+	for range 14 {
+		order.WriteString(instances[b.Select(instances, "")].Addr)
+	}
+	if order.String() != "aabacaaaabacaa" {
+		t.Fatalf("unexpected order %s", order.String())
+	}
+
+	// 实例下线后按剩余实例的权重分配
+	instances = instances[:2]
+	counts := make(map[string]int)
+	for range 12 {
+		counts[instances[b.Select(instances, "")].Addr]++
+	}
+	if counts["a"] != 10 || counts["b"] != 2 {
+		t.Fatalf("unexpected distribution %v", counts)
+	}
+}
+
+func TestLeastConn(t *testing.T) {
+	b := NewLeastConnBalancer()
+	instances := []Instance{{Addr: "a", Load: 100}, {Addr: "b", Load: 30}, {Addr: "c", Load: 50}}
+	for range 5 {
+		if index := b.Select(instances, ""); index != 1 {
+			t.Fatalf("expected least loaded instance, got %s", instances[index].Addr)
+		}
+	}
+
+	// 按单位权重负载比较
+	instances[0].Weight = 4
+	if index := b.Select(instances, ""); index != 0 {
+		t.Fatalf("expected weighted instance, got %s", instances[index].Addr)
+	}
+
+	// 负载相同的实例轮流选择
+	instances = []Instance{{Addr: "a", Load: 10}, {Addr: "b", Load: 10}, {Addr: "c", Load: 90}}
+	counts := make(map[string]int)
+	for range 10 {
+		counts[instances[b.Select(instances, "")].Addr]++
+	}
+	if counts["a"] == 0 || counts["b"] == 0 || counts["c"] != 0 {
+		t.Fatalf("unexpected distribution %v", counts)
+	}
+}
+
+func TestP2C(t *testing.T) {
+	b := NewP2CBalancer()
+	instances := []Instance{{Addr: "a", Load: 10}, {Addr: "b", Load: 1000}, {Addr: "c", Load: 20}}
+	counts := make(map[string]int)
+	for range 1000 {
+		counts[instances[b.Select(instances, "")].Addr]++
+	}
+	// 负载最大的实例总是输给另一个候选
+	if counts["b"] != 0 {
+		t.Fatalf("most loaded instance selected %d times", counts["b"])
+	}
+	// a与任一实例比较都胜出约占2/3，c只在与b比较时胜出约占1/3
+	if counts["a"] < 200 || counts["c"] < 200 {
+		t.Fatalf("unexpected distribution %v", counts)
+	}
+	if index := b.Select(instances[:1], ""); index != 0 {
+		t.Fatalf("expected single instance, got %d", index)
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, strategy := range []string{StrategyRoundRobin, StrategyConsistentHash, StrategyWeightedRoundRobin, StrategyLeastConn, StrategyP2C} {
+		b, err := New(strategy)
+		if err != nil {
+			t.Fatalf("failed to create %s: %v", strategy, err)
+		}
+		if index := b.Select([]Instance{{Addr: "a"}}, "key"); index != 0 {
+			t.Fatalf("%s: expected 0, got %d", strategy, index)
+		}
+	}
+	if _, err := New("random"); err == nil {
+		t.Fatalf("expected error for unknown strategy")
+	}
+}
@@ -0,0 +1,29 @@
+package loadbalance
+
+import "math/rand/v2"
+
+// 两次随机选择负载均衡，随机选两个实例取单位权重负载较小的一个
+// 负载上报有延迟时不会像最少连接一样把请求集中到同一实例
+type P2CBalancer struct{}
+
+func NewP2CBalancer() *P2CBalancer {
+	return &P2CBalancer{}
+}
+
+func (p *P2CBalancer) Select(instances []Instance, _ string) int {
+	if len(instances) == 0 {
+		return -1
+	}
+	if len(instances) == 1 {
+		return 0
+	}
+	a := rand.IntN(len(instances))
+	b := rand.IntN(len(instances) - 1)
+	if b >= a {
+		b++
+	}
+	if lessLoaded(instances[b], instances[a]) {
+		return b
+	}
+	return a
+}
@@ -11,7 +11,10 @@ func NewRoundRobinBalancer() *RoundRobinBalancer {
 	return &RoundRobinBalancer{}
 }
 
-func (r *RoundRobinBalancer) Select(length int64, _ string) int64 {
-	index := atomic.AddUint64(&r.counter, 1) % uint64(length)
-	return int64(index)
+func (r *RoundRobinBalancer) Select(instances []Instance, _ string) int {
+	if len(instances) == 0 {
+		return -1
+	}
+	index := atomic.AddUint64(&r.counter, 1) % uint64(len(instances))
+	return int(index)
 }
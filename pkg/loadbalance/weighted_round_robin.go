@@ -0,0 +1,42 @@
+package loadbalance
+
+import "sync"
+
+// 平滑加权轮询负载均衡
+// 每次选择时所有实例的当前权重加上各自权重，选中当前权重最大的实例并减去总权重
+// 权重为 5,1,1 时选择顺序为 a,a,b,a,c,a,a，不会连续集中在高权重实例
+type WeightedRoundRobinBalancer struct {
+	mu      sync.Mutex
+	current map[string]int // 实例 -> 当前权重
+}
+
+func NewWeightedRoundRobinBalancer() *WeightedRoundRobinBalancer {
+	return &WeightedRoundRobinBalancer{current: make(map[string]int)}
+}
+
+func (w *WeightedRoundRobinBalancer) Select(instances []Instance, _ string) int {
+	if len(instances) == 0 {
+		return -1
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// 移除已下线的实例
+	if len(w.current) > len(instances) {
+		alive := make(map[string]int, len(instances))
+		for _, instance := range instances {
+			alive[instance.Addr] = w.current[instance.Addr]
+		}
+		w.current = alive
+	}
+	best, total := -1, 0
+	for i, instance := range instances {
+		weight := weightOf(instance)
+		total += weight
+		w.current[instance.Addr] += weight
+		if best < 0 || w.current[instance.Addr] > w.current[instances[best].Addr] {
+			best = i
+		}
+	}
+	w.current[instances[best].Addr] -= total
+	return best
+}
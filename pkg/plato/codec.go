@@ -0,0 +1,136 @@
+package plato
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	DefaultMaxVarHeaderLen = 4 << 10 // 默认可变头上限 4KB
+	DefaultMaxBodyLen      = 4 << 20 // 默认消息体上限 4MB
+)
+
+// Frame 一个完整的协议帧
+type Frame struct {
+	Version   int
+	MsgType   int
+	VarHeader []byte
+	Body      []byte
+}
+
+// UnmarshalBody 将消息体反序列化为proto消息
+func (f *Frame) UnmarshalBody(msg proto.Message) error {
+	return proto.Unmarshal(f.Body, msg)
+}
+
+// Decoder 从字节流中按帧读取，处理TCP拆包、粘包
+type Decoder struct {
+	r               io.Reader
+	fixHeader       [FixHeaderLen]byte
+	maxVarHeaderLen int
+	maxBodyLen      int
+}
+
+type DecoderOption func(*Decoder)
+
+// WithMaxVarHeaderLen 设置可变头上限，小于等于0时使用默认值
+func WithMaxVarHeaderLen(n int) DecoderOption {
+	return func(d *Decoder) {
+		if n > 0 {
+			d.maxVarHeaderLen = n
+		}
+	}
+}
+
+// WithMaxBodyLen 设置消息体上限，小于等于0时使用默认值
+func WithMaxBodyLen(n int) DecoderOption {
+	return func(d *Decoder) {
+		if n > 0 {
+			d.maxBodyLen = n
+		}
+	}
+}
+
+func NewDecoder(r io.Reader, opts ...DecoderOption) *Decoder {
+	d := &Decoder{
+		r:               bufio.NewReader(r),
+		maxVarHeaderLen: DefaultMaxVarHeaderLen,
+		maxBodyLen:      DefaultMaxBodyLen,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Decode 读取下一个完整的帧
+// 在帧边界处连接关闭返回io.EOF，帧不完整返回io.ErrUnexpectedEOF
+func (d *Decoder) Decode() (*Frame, error) {
+	if _, err := io.ReadFull(d.r, d.fixHeader[:]); err != nil {
+		return nil, err
+	}
+	fixHeader := &FixHeaderProtocol{}
+	if err := fixHeader.Unmarshal(d.fixHeader[:]); err != nil {
+		return nil, err
+	}
+	varHeaderLen := fixHeader.GetVarHeaderLen()
+	if varHeaderLen > d.maxVarHeaderLen {
+		return nil, fmt.Errorf("%w: %d > %d", ErrVarHeaderTooLarge, varHeaderLen, d.maxVarHeaderLen)
+	}
+	bodyLen := fixHeader.GetBodyLen()
+	if bodyLen > d.maxBodyLen {
+		return nil, fmt.Errorf("%w: %d > %d", ErrBodyTooLarge, bodyLen, d.maxBodyLen)
+	}
+	content := make([]byte, varHeaderLen+bodyLen)
+	if _, err := io.ReadFull(d.r, content); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return &Frame{
+		Version:   fixHeader.GetVersion(),
+		MsgType:   fixHeader.GetMsgType(),
+		VarHeader: content[:varHeaderLen],
+		Body:      content[varHeaderLen:],
+	}, nil
+}
+
+// Encoder 按帧写入字节流，并发安全，保证帧之间不会交错
+type Encoder struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode 写入一个完整的帧
+func (e *Encoder) Encode(msgType int8, header []byte, body []byte) error {
+	data := Marshal(Version, msgType, header, body)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err := e.w.Write(data)
+	return err
+}
+
+// Reset 替换底层writer，用于重连后继续使用同一个Encoder
+func (e *Encoder) Reset(w io.Writer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.w = w
+}
+
+// EncodeMessage 将proto消息作为消息体写入一个完整的帧
+func (e *Encoder) EncodeMessage(msgType int8, msg proto.Message) error {
+	body, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return e.Encode(msgType, nil, body)
+}
@@ -0,0 +1,79 @@
+package plato
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestDecoderFragmented(t *testing.T) {
+	buf := &bytes.Buffer{}
+	encoder := NewEncoder(buf)
+	if err := encoder.EncodeMessage(MsgTypeMessageUpLink, &MessageUpLink{SessionUuid: "s1", Payload: "hello"}); err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	if err := encoder.Encode(MsgTypeCreateConn, []byte("h"), []byte("body")); err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+
+	// 每次只读一个字节，模拟TCP拆包；两帧在同一缓冲区中，模拟粘包
+	decoder := NewDecoder(iotest.OneByteReader(buf))
+	frame, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	msg := &MessageUpLink{}
+	if err := frame.UnmarshalBody(msg); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if frame.MsgType != MsgTypeMessageUpLink || msg.GetSessionUuid() != "s1" || msg.GetPayload() != "hello" {
+		t.Fatalf("unexpected frame: %v %v", frame, msg)
+	}
+
+	frame, err = decoder.Decode()
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if frame.MsgType != MsgTypeCreateConn || string(frame.VarHeader) != "h" || string(frame.Body) != "body" {
+		t.Fatalf("unexpected frame: %v", frame)
+	}
+
+	if _, err := decoder.Decode(); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	t.Run("unknown version", func(t *testing.T) {
+		data := Marshal(2, MsgTypeMessageUpLink, nil, []byte("body"))
+		if _, err := NewDecoder(bytes.NewReader(data)).Decode(); !errors.Is(err, ErrUnknownVersion) {
+			t.Fatalf("expected ErrUnknownVersion, got %v", err)
+		}
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		data := Marshal(Version, MsgTypeMessageUpLink, nil, make([]byte, 17))
+		if _, err := NewDecoder(bytes.NewReader(data), WithMaxBodyLen(16)).Decode(); !errors.Is(err, ErrBodyTooLarge) {
+			t.Fatalf("expected ErrBodyTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("var header too large", func(t *testing.T) {
+		data := Marshal(Version, MsgTypeMessageUpLink, make([]byte, 17), nil)
+		if _, err := NewDecoder(bytes.NewReader(data), WithMaxVarHeaderLen(16)).Decode(); !errors.Is(err, ErrVarHeaderTooLarge) {
+			t.Fatalf("expected ErrVarHeaderTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("truncated frame", func(t *testing.T) {
+		data := Marshal(Version, MsgTypeMessageUpLink, nil, []byte("body"))
+		if _, err := NewDecoder(bytes.NewReader(data[:len(data)-1])).Decode(); err != io.ErrUnexpectedEOF {
+			t.Fatalf("expected io.ErrUnexpectedEOF, got %v", err)
+		}
+		if _, err := NewDecoder(bytes.NewReader(data[:5])).Decode(); err != io.ErrUnexpectedEOF {
+			t.Fatalf("expected io.ErrUnexpectedEOF, got %v", err)
+		}
+	})
+}
@@ -2,20 +2,44 @@ package plato
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 )
 
 //go:generate protoc --go_out=./ plato.proto
 
 const (
-	MsgTypeMessageUpLink = 1 // 消息上行
-	MsgTypeMessageDownLink = 2 // 消息下行
-	MsgTypeOpenSession = 3 // 打开会话
-	MsgTypeJoinSession = 4 // 加入会话
-	MsgTypeLeaveSession = 5 // 离开会话
-	MsgTypeCreateConn = 6 // 创建连接
+	MsgTypeMessageUpLink   = 1  // 消息上行
+	MsgTypeMessageDownLink = 2  // 消息下行
+	MsgTypeOpenSession     = 3  // 打开会话
+	MsgTypeJoinSession     = 4  // 加入会话
+	MsgTypeLeaveSession    = 5  // 离开会话
+	MsgTypeCreateConn      = 6  // 创建连接
+	MsgTypePing            = 7  // 心跳请求
+	MsgTypePong            = 8  // 心跳响应
+	MsgTypeAck             = 9  // 下行消息确认
+	MsgTypeUpLinkAck       = 10 // 上行消息确认
+	MsgTypeKick            = 11 // 踢下线
+	MsgTypeResync          = 12 // 通知客户端补拉消息
+	MsgTypeReconnect       = 13 // 网关下线，通知客户端重连其他网关
+)
 
+const (
+	PlatformDesktop = "desktop" // 桌面端
+	PlatformMobile  = "mobile"  // 移动端
+	PlatformWeb     = "web"     // 网页端
+)
+
+const (
+	Version      = 1  // 当前协议版本
+	FixHeaderLen = 10 // 固定头长度
+)
 
+var (
+	ErrUnknownVersion    = errors.New("plato: unknown version")
+	ErrShortFixHeader    = errors.New("plato: fix header too short")
+	ErrVarHeaderTooLarge = errors.New("plato: var header too large")
+	ErrBodyTooLarge      = errors.New("plato: body too large")
 )
 
 type FixHeaderProtocol struct {
@@ -25,34 +49,33 @@ type FixHeaderProtocol struct {
 	bodyLen      [4]byte
 }
 
-
-
-
+// Check 校验固定头，长度上限由Decoder负责
 func (p *FixHeaderProtocol) Check() error {
-	fmt.Println("version:", p.GetVersion())
-	fmt.Println("msgType:", p.GetMsgType())
-	fmt.Println("varHeaderLen:", p.GetVarHeaderLen())
-	fmt.Println("bodyLen:", p.GetBodyLen())
+	if p.GetVersion() != Version {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, p.GetVersion())
+	}
 	return nil
 }
 
 func Marshal(version int8, msgType int8, header []byte, body []byte) []byte {
-	msg := make([]byte, 10+len(header)+len(body))
+	msg := make([]byte, FixHeaderLen+len(header)+len(body))
 	copy(msg[:1], []byte{byte(version)}[:])
 	copy(msg[1:2], []byte{byte(msgType)}[:])
 	int32ToBytes(int32(len(header)), msg[2:6])
 	int32ToBytes(int32(len(body)), msg[6:10])
-	copy(msg[10:10+len(header)], header)
-	copy(msg[10+len(header):10+len(header)+len(body)], body)
+	copy(msg[FixHeaderLen:FixHeaderLen+len(header)], header)
+	copy(msg[FixHeaderLen+len(header):FixHeaderLen+len(header)+len(body)], body)
 	return msg
 }
 
-
 func int32ToBytes(value int32, bytes []byte) {
 	binary.BigEndian.PutUint32(bytes, uint32(value))
 }
 
 func (p *FixHeaderProtocol) Unmarshal(data []byte) error {
+	if len(data) < FixHeaderLen {
+		return fmt.Errorf("%w: %d", ErrShortFixHeader, len(data))
+	}
 	copy(p.version[:], data[:1])
 	copy(p.msgType[:], data[1:2])
 	copy(p.varHeaderLen[:], data[2:6])
@@ -23,8 +23,9 @@ const (
 
 type MessageUpLink struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	SessionUuid   string                 `protobuf:"bytes,1,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"` // 会话UUID
-	Payload       string                 `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`                            // 消息
+	SessionUuid   string                 `protobuf:"bytes,1,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"`   // 会话UUID
+	Payload       string                 `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`                              // 消息
+	ClientMsgId   string                 `protobuf:"bytes,3,opt,name=client_msg_id,json=clientMsgId,proto3" json:"client_msg_id,omitempty"` // 客户端消息ID 重试时保持不变，用于去重
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -73,12 +74,21 @@ func (x *MessageUpLink) GetPayload() string {
 	return ""
 }
 
+func (x *MessageUpLink) GetClientMsgId() string {
+	if x != nil {
+		return x.ClientMsgId
+	}
+	return ""
+}
+
 type MessageDownLink struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	SessionUuid    string                 `protobuf:"bytes,1,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"`            // 会话UUID
 	SenderUserUuid string                 `protobuf:"bytes,2,opt,name=sender_user_uuid,json=senderUserUuid,proto3" json:"sender_user_uuid,omitempty"` // 发送者UUID
 	Payload        string                 `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`                                       // 消息
 	SeqId          int64                  `protobuf:"varint,4,opt,name=seq_id,json=seqId,proto3" json:"seq_id,omitempty"`                             // 序列号ID 会话内有序
+	MessageUuid    string                 `protobuf:"bytes,5,opt,name=message_uuid,json=messageUuid,proto3" json:"message_uuid,omitempty"`            // 消息UUID 客户端ACK及去重使用
+	MessageType    int64                  `protobuf:"varint,6,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`           // 消息类型 1: 文本 2: 系统消息
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -141,9 +151,25 @@ func (x *MessageDownLink) GetSeqId() int64 {
 	return 0
 }
 
+func (x *MessageDownLink) GetMessageUuid() string {
+	if x != nil {
+		return x.MessageUuid
+	}
+	return ""
+}
+
+func (x *MessageDownLink) GetMessageType() int64 {
+	if x != nil {
+		return x.MessageType
+	}
+	return 0
+}
+
 type MessageCreateConn struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"` // 用户token
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`                       // 用户token
+	DeviceId      string                 `protobuf:"bytes,2,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"` // 设备ID 同一设备重连时替换旧连接
+	Platform      string                 `protobuf:"bytes,3,opt,name=platform,proto3" json:"platform,omitempty"`                 // 平台 desktop/mobile/web
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -185,21 +211,688 @@ func (x *MessageCreateConn) GetToken() string {
 	return ""
 }
 
+func (x *MessageCreateConn) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *MessageCreateConn) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+// 服务端踢下线，客户端收到后不应自动重连
+type MessageKick struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"` // 原因
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageKick) Reset() {
+	*x = MessageKick{}
+	mi := &file_plato_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageKick) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageKick) ProtoMessage() {}
+
+func (x *MessageKick) ProtoReflect() protoreflect.Message {
+	mi := &file_plato_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageKick.ProtoReflect.Descriptor instead.
+func (*MessageKick) Descriptor() ([]byte, []int) {
+	return file_plato_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MessageKick) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type MessagePing struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // 客户端发送时间 毫秒
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessagePing) Reset() {
+	*x = MessagePing{}
+	mi := &file_plato_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessagePing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessagePing) ProtoMessage() {}
+
+func (x *MessagePing) ProtoReflect() protoreflect.Message {
+	mi := &file_plato_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessagePing.ProtoReflect.Descriptor instead.
+func (*MessagePing) Descriptor() ([]byte, []int) {
+	return file_plato_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MessagePing) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type MessagePong struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // 原样返回Ping中的时间，用于计算RTT
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessagePong) Reset() {
+	*x = MessagePong{}
+	mi := &file_plato_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessagePong) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessagePong) ProtoMessage() {}
+
+func (x *MessagePong) ProtoReflect() protoreflect.Message {
+	mi := &file_plato_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessagePong.ProtoReflect.Descriptor instead.
+func (*MessagePong) Descriptor() ([]byte, []int) {
+	return file_plato_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *MessagePong) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+// 客户端确认收到下行消息
+type MessageAck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageUuid   string                 `protobuf:"bytes,1,opt,name=message_uuid,json=messageUuid,proto3" json:"message_uuid,omitempty"` // 消息UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageAck) Reset() {
+	*x = MessageAck{}
+	mi := &file_plato_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageAck) ProtoMessage() {}
+
+func (x *MessageAck) ProtoReflect() protoreflect.Message {
+	mi := &file_plato_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageAck.ProtoReflect.Descriptor instead.
+func (*MessageAck) Descriptor() ([]byte, []int) {
+	return file_plato_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MessageAck) GetMessageUuid() string {
+	if x != nil {
+		return x.MessageUuid
+	}
+	return ""
+}
+
+// 服务端确认上行消息已落库
+type MessageUpLinkAck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientMsgId   string                 `protobuf:"bytes,1,opt,name=client_msg_id,json=clientMsgId,proto3" json:"client_msg_id,omitempty"` // 客户端消息ID
+	SessionUuid   string                 `protobuf:"bytes,2,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"`   // 会话UUID
+	MessageUuid   string                 `protobuf:"bytes,3,opt,name=message_uuid,json=messageUuid,proto3" json:"message_uuid,omitempty"`   // 服务端分配的消息UUID
+	SeqId         int64                  `protobuf:"varint,4,opt,name=seq_id,json=seqId,proto3" json:"seq_id,omitempty"`                    // 服务端分配的序列号ID
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`                                  // 失败原因 为空表示成功
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageUpLinkAck) Reset() {
+	*x = MessageUpLinkAck{}
+	mi := &file_plato_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageUpLinkAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageUpLinkAck) ProtoMessage() {}
+
+func (x *MessageUpLinkAck) ProtoReflect() protoreflect.Message {
+	mi := &file_plato_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageUpLinkAck.ProtoReflect.Descriptor instead.
+func (*MessageUpLinkAck) Descriptor() ([]byte, []int) {
+	return file_plato_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MessageUpLinkAck) GetClientMsgId() string {
+	if x != nil {
+		return x.ClientMsgId
+	}
+	return ""
+}
+
+func (x *MessageUpLinkAck) GetSessionUuid() string {
+	if x != nil {
+		return x.SessionUuid
+	}
+	return ""
+}
+
+func (x *MessageUpLinkAck) GetMessageUuid() string {
+	if x != nil {
+		return x.MessageUuid
+	}
+	return ""
+}
+
+func (x *MessageUpLinkAck) GetSeqId() int64 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *MessageUpLinkAck) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// 创建群聊会话，创建者自动加入
+type MessageOpenSession struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`       // 请求ID 原样返回
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                  // 会话名称
+	MemberUuids   []string               `protobuf:"bytes,3,rep,name=member_uuids,json=memberUuids,proto3" json:"member_uuids,omitempty"` // 初始成员UUID列表
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageOpenSession) Reset() {
+	*x = MessageOpenSession{}
+	mi := &file_plato_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageOpenSession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageOpenSession) ProtoMessage() {}
+
+func (x *MessageOpenSession) ProtoReflect() protoreflect.Message {
+	mi := &file_plato_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageOpenSession.ProtoReflect.Descriptor instead.
+func (*MessageOpenSession) Descriptor() ([]byte, []int) {
+	return file_plato_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *MessageOpenSession) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *MessageOpenSession) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MessageOpenSession) GetMemberUuids() []string {
+	if x != nil {
+		return x.MemberUuids
+	}
+	return nil
+}
+
+// 邀请用户加入会话
+type MessageJoinSession struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`       // 请求ID 原样返回
+	SessionUuid   string                 `protobuf:"bytes,2,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"` // 会话UUID
+	UserUuids     []string               `protobuf:"bytes,3,rep,name=user_uuids,json=userUuids,proto3" json:"user_uuids,omitempty"`       // 被邀请的用户UUID列表
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageJoinSession) Reset() {
+	*x = MessageJoinSession{}
+	mi := &file_plato_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageJoinSession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageJoinSession) ProtoMessage() {}
+
+func (x *MessageJoinSession) ProtoReflect() protoreflect.Message {
+	mi := &file_plato_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageJoinSession.ProtoReflect.Descriptor instead.
+func (*MessageJoinSession) Descriptor() ([]byte, []int) {
+	return file_plato_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *MessageJoinSession) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *MessageJoinSession) GetSessionUuid() string {
+	if x != nil {
+		return x.SessionUuid
+	}
+	return ""
+}
+
+func (x *MessageJoinSession) GetUserUuids() []string {
+	if x != nil {
+		return x.UserUuids
+	}
+	return nil
+}
+
+// 退出会话
+type MessageLeaveSession struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`       // 请求ID 原样返回
+	SessionUuid   string                 `protobuf:"bytes,2,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"` // 会话UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageLeaveSession) Reset() {
+	*x = MessageLeaveSession{}
+	mi := &file_plato_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageLeaveSession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageLeaveSession) ProtoMessage() {}
+
+func (x *MessageLeaveSession) ProtoReflect() protoreflect.Message {
+	mi := &file_plato_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageLeaveSession.ProtoReflect.Descriptor instead.
+func (*MessageLeaveSession) Descriptor() ([]byte, []int) {
+	return file_plato_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *MessageLeaveSession) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *MessageLeaveSession) GetSessionUuid() string {
+	if x != nil {
+		return x.SessionUuid
+	}
+	return ""
+}
+
+// 会话操作结果，使用请求的消息类型返回
+type MessageSessionResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`       // 请求ID
+	SessionUuid   string                 `protobuf:"bytes,2,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"` // 会话UUID
+	MessageUuid   string                 `protobuf:"bytes,3,opt,name=message_uuid,json=messageUuid,proto3" json:"message_uuid,omitempty"` // 成员变更系统消息UUID
+	SeqId         int64                  `protobuf:"varint,4,opt,name=seq_id,json=seqId,proto3" json:"seq_id,omitempty"`                  // 成员变更系统消息序列号ID
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`                                // 失败原因 为空表示成功
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageSessionResult) Reset() {
+	*x = MessageSessionResult{}
+	mi := &file_plato_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageSessionResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageSessionResult) ProtoMessage() {}
+
+func (x *MessageSessionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_plato_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageSessionResult.ProtoReflect.Descriptor instead.
+func (*MessageSessionResult) Descriptor() ([]byte, []int) {
+	return file_plato_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *MessageSessionResult) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *MessageSessionResult) GetSessionUuid() string {
+	if x != nil {
+		return x.SessionUuid
+	}
+	return ""
+}
+
+func (x *MessageSessionResult) GetMessageUuid() string {
+	if x != nil {
+		return x.MessageUuid
+	}
+	return ""
+}
+
+func (x *MessageSessionResult) GetSeqId() int64 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *MessageSessionResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// 服务端丢弃了部分下行消息，客户端需要补拉序列号大于start_seqid的消息
+type MessageResync struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionUuid   string                 `protobuf:"bytes,1,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"` // 会话UUID
+	StartSeqid    int64                  `protobuf:"varint,2,opt,name=start_seqid,json=startSeqid,proto3" json:"start_seqid,omitempty"`   // 开始序列号（不包含）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageResync) Reset() {
+	*x = MessageResync{}
+	mi := &file_plato_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageResync) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageResync) ProtoMessage() {}
+
+func (x *MessageResync) ProtoReflect() protoreflect.Message {
+	mi := &file_plato_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageResync.ProtoReflect.Descriptor instead.
+func (*MessageResync) Descriptor() ([]byte, []int) {
+	return file_plato_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *MessageResync) GetSessionUuid() string {
+	if x != nil {
+		return x.SessionUuid
+	}
+	return ""
+}
+
+func (x *MessageResync) GetStartSeqid() int64 {
+	if x != nil {
+		return x.StartSeqid
+	}
+	return 0
+}
+
+// 网关即将下线，客户端收到后应重新连接，与踢下线不同
+type MessageReconnect struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"` // 原因
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageReconnect) Reset() {
+	*x = MessageReconnect{}
+	mi := &file_plato_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageReconnect) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageReconnect) ProtoMessage() {}
+
+func (x *MessageReconnect) ProtoReflect() protoreflect.Message {
+	mi := &file_plato_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageReconnect.ProtoReflect.Descriptor instead.
+func (*MessageReconnect) Descriptor() ([]byte, []int) {
+	return file_plato_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *MessageReconnect) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
 var File_plato_proto protoreflect.FileDescriptor
 
 const file_plato_proto_rawDesc = "" +
 	"\n" +
-	"\vplato.proto\x12\x05plato\"L\n" +
+	"\vplato.proto\x12\x05plato\"p\n" +
 	"\rMessageUpLink\x12!\n" +
 	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuid\x12\x18\n" +
-	"\apayload\x18\x02 \x01(\tR\apayload\"\x8f\x01\n" +
+	"\apayload\x18\x02 \x01(\tR\apayload\x12\"\n" +
+	"\rclient_msg_id\x18\x03 \x01(\tR\vclientMsgId\"\xd5\x01\n" +
 	"\x0fMessageDownLink\x12!\n" +
 	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuid\x12(\n" +
 	"\x10sender_user_uuid\x18\x02 \x01(\tR\x0esenderUserUuid\x12\x18\n" +
 	"\apayload\x18\x03 \x01(\tR\apayload\x12\x15\n" +
-	"\x06seq_id\x18\x04 \x01(\x03R\x05seqId\")\n" +
+	"\x06seq_id\x18\x04 \x01(\x03R\x05seqId\x12!\n" +
+	"\fmessage_uuid\x18\x05 \x01(\tR\vmessageUuid\x12!\n" +
+	"\fmessage_type\x18\x06 \x01(\x03R\vmessageType\"b\n" +
 	"\x11MessageCreateConn\x12\x14\n" +
-	"\x05token\x18\x01 \x01(\tR\x05tokenB\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1b\n" +
+	"\tdevice_id\x18\x02 \x01(\tR\bdeviceId\x12\x1a\n" +
+	"\bplatform\x18\x03 \x01(\tR\bplatform\"%\n" +
+	"\vMessageKick\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\"+\n" +
+	"\vMessagePing\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\"+\n" +
+	"\vMessagePong\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\"/\n" +
+	"\n" +
+	"MessageAck\x12!\n" +
+	"\fmessage_uuid\x18\x01 \x01(\tR\vmessageUuid\"\xa9\x01\n" +
+	"\x10MessageUpLinkAck\x12\"\n" +
+	"\rclient_msg_id\x18\x01 \x01(\tR\vclientMsgId\x12!\n" +
+	"\fsession_uuid\x18\x02 \x01(\tR\vsessionUuid\x12!\n" +
+	"\fmessage_uuid\x18\x03 \x01(\tR\vmessageUuid\x12\x15\n" +
+	"\x06seq_id\x18\x04 \x01(\x03R\x05seqId\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"j\n" +
+	"\x12MessageOpenSession\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12!\n" +
+	"\fmember_uuids\x18\x03 \x03(\tR\vmemberUuids\"u\n" +
+	"\x12MessageJoinSession\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12!\n" +
+	"\fsession_uuid\x18\x02 \x01(\tR\vsessionUuid\x12\x1d\n" +
+	"\n" +
+	"user_uuids\x18\x03 \x03(\tR\tuserUuids\"W\n" +
+	"\x13MessageLeaveSession\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12!\n" +
+	"\fsession_uuid\x18\x02 \x01(\tR\vsessionUuid\"\xa8\x01\n" +
+	"\x14MessageSessionResult\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12!\n" +
+	"\fsession_uuid\x18\x02 \x01(\tR\vsessionUuid\x12!\n" +
+	"\fmessage_uuid\x18\x03 \x01(\tR\vmessageUuid\x12\x15\n" +
+	"\x06seq_id\x18\x04 \x01(\x03R\x05seqId\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"S\n" +
+	"\rMessageResync\x12!\n" +
+	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuid\x12\x1f\n" +
+	"\vstart_seqid\x18\x02 \x01(\x03R\n" +
+	"startSeqid\"*\n" +
+	"\x10MessageReconnect\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reasonB\n" +
 	"Z\b./;platob\x06proto3"
 
 var (
@@ -214,11 +907,22 @@ func file_plato_proto_rawDescGZIP() []byte {
 	return file_plato_proto_rawDescData
 }
 
-var file_plato_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_plato_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_plato_proto_goTypes = []any{
-	(*MessageUpLink)(nil),     // 0: plato.MessageUpLink
-	(*MessageDownLink)(nil),   // 1: plato.MessageDownLink
-	(*MessageCreateConn)(nil), // 2: plato.MessageCreateConn
+	(*MessageUpLink)(nil),        // 0: plato.MessageUpLink
+	(*MessageDownLink)(nil),      // 1: plato.MessageDownLink
+	(*MessageCreateConn)(nil),    // 2: plato.MessageCreateConn
+	(*MessageKick)(nil),          // 3: plato.MessageKick
+	(*MessagePing)(nil),          // 4: plato.MessagePing
+	(*MessagePong)(nil),          // 5: plato.MessagePong
+	(*MessageAck)(nil),           // 6: plato.MessageAck
+	(*MessageUpLinkAck)(nil),     // 7: plato.MessageUpLinkAck
+	(*MessageOpenSession)(nil),   // 8: plato.MessageOpenSession
+	(*MessageJoinSession)(nil),   // 9: plato.MessageJoinSession
+	(*MessageLeaveSession)(nil),  // 10: plato.MessageLeaveSession
+	(*MessageSessionResult)(nil), // 11: plato.MessageSessionResult
+	(*MessageResync)(nil),        // 12: plato.MessageResync
+	(*MessageReconnect)(nil),     // 13: plato.MessageReconnect
 }
 var file_plato_proto_depIdxs = []int32{
 	0, // [0:0] is the sub-list for method output_type
@@ -239,7 +943,7 @@ func file_plato_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_plato_proto_rawDesc), len(file_plato_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
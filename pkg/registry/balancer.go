@@ -0,0 +1,79 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"im/pkg/loadbalance"
+
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+)
+
+// 负载均衡策略名称，通过服务配置选择
+const (
+	BalancerRoundRobin     = "discovery_round_robin"
+	BalancerConsistentHash = "discovery_consistent_hash"
+)
+
+func init() {
+	// 只在就绪的连接中选择，服务配置启用健康检查时不健康的实例不参与
+	balancer.Register(base.NewBalancerBuilder(BalancerRoundRobin, &pickerBuilder{
+		newBalancer: func() loadbalance.LoadBalancer { return loadbalance.NewRoundRobinBalancer() },
+	}, base.Config{HealthCheck: true}))
+	balancer.Register(base.NewBalancerBuilder(BalancerConsistentHash, &pickerBuilder{
+		newBalancer: func() loadbalance.LoadBalancer {
+			return loadbalance.NewConsistentHashBalancer(loadbalance.DefaultVirtualNodes)
+		},
+	}, base.Config{HealthCheck: true}))
+}
+
+// ServiceConfig 返回使用指定负载均衡策略的服务配置，用于grpc.WithDefaultServiceConfig
+func ServiceConfig(balancerName string) string {
+	return fmt.Sprintf(`{"loadBalancingConfig":[{%q:{}}]}`, balancerName)
+}
+
+type balanceKey struct{}
+
+// WithBalanceKey 设置一致性哈希使用的key，相同key的请求发往同一实例
+func WithBalanceKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, balanceKey{}, key)
+}
+
+type pickerBuilder struct {
+	newBalancer func() loadbalance.LoadBalancer
+}
+
+func (b *pickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+	subConns := make([]balancer.SubConn, 0, len(info.ReadySCs))
+	instances := make([]loadbalance.Instance, 0, len(info.ReadySCs))
+	for subConn, subConnInfo := range info.ReadySCs {
+		subConns = append(subConns, subConn)
+		instances = append(instances, loadbalance.Instance{Addr: subConnInfo.Address.Addr})
+	}
+	return &picker{
+		subConns:  subConns,
+		instances: instances,
+		balancer:  b.newBalancer(),
+		fallback:  loadbalance.NewRoundRobinBalancer(),
+	}
+}
+
+type picker struct {
+	subConns  []balancer.SubConn
+	instances []loadbalance.Instance // 与subConns一一对应
+	balancer  loadbalance.LoadBalancer
+	fallback  loadbalance.LoadBalancer // 请求未设置key时轮询，避免全部落到同一实例
+}
+
+func (p *picker) Pick(info balancer.PickInfo) (balancer.PickResult, error) {
+	key, _ := info.Ctx.Value(balanceKey{}).(string)
+	lb := p.balancer
+	if len(key) == 0 {
+		lb = p.fallback
+	}
+	index := lb.Select(p.instances, key)
+	return balancer.PickResult{SubConn: p.subConns[index]}, nil
+}
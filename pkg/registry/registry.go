@@ -0,0 +1,219 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"im/server/discovery/rpc/service"
+	"log/slog"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 注册到发现服务的服务名称
+const (
+	ServiceAPIGateway = "apigateway"
+	ServiceIMGateway  = "imgateway"
+)
+
+// 实例元数据中的其他协议端口，注册地址只包含主协议端口
+const (
+	MetadataRPCPort       = service.MetadataRPCPort // 发现服务对实例做健康检查时使用
+	MetadataWebSocketPort = "websocket_port"
+	MetadataWebSocketPath = "websocket_path"
+)
+
+// 未取得租约时的重试间隔
+const retryInterval = 5 * time.Second
+
+// Instance 注册到发现服务的实例
+type Instance struct {
+	Name     string            // 服务名称
+	Addr     string            // 客户端访问本实例使用的地址 host:port
+	Weight   int               // 权重，小于等于0时为1
+	Load     func() int64      // 当前负载，注册和续约时上报，为nil时不上报
+	Metadata map[string]string // 实例元数据 见service.MetadataZone等
+}
+
+// Registrar 将服务实例注册到发现服务，定期续约，退出时注销
+type Registrar struct {
+	client   service.DiscoveryClient
+	logger   *slog.Logger
+	name     string
+	host     string
+	port     string
+	weight   int32
+	load     func() int64
+	metadata map[string]string
+	ttl      int64              // 租约有效期 秒，0表示用服务端默认值
+	leaseId  string             // 当前租约ID，为空表示未注册
+	interval time.Duration      // 续约间隔，取租约有效期的三分之一
+	cancel   context.CancelFunc // 停止续约
+	done     chan struct{}      // 续约已停止
+}
+
+func NewRegistrar(client service.DiscoveryClient, logger *slog.Logger, instance Instance) (*Registrar, error) {
+	host, port, err := net.SplitHostPort(instance.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid advertise address %s: %w", instance.Addr, err)
+	}
+	return &Registrar{
+		client:   client,
+		logger:   logger.With("service_name", instance.Name, "address", instance.Addr),
+		name:     instance.Name,
+		host:     host,
+		port:     port,
+		weight:   int32(instance.Weight),
+		load:     instance.Load,
+		metadata: instance.Metadata,
+		interval: retryInterval,
+	}, nil
+}
+
+// Dial 创建发现服务客户端，连接在首次请求时建立
+func Dial(endpoint string, dialOption grpc.DialOption) (service.DiscoveryClient, error) {
+	conn, err := grpc.NewClient(endpoint, dialOption)
+	if err != nil {
+		return nil, err
+	}
+	return service.NewDiscoveryClient(conn), nil
+}
+
+// Start 注册实例，在后台续约直到ctx结束
+// 注册失败时不返回错误，由续约重试，发现服务晚于本服务启动时也能完成注册
+func Start(ctx context.Context, client service.DiscoveryClient, logger *slog.Logger, instance Instance) (*Registrar, error) {
+	r, err := NewRegistrar(client, logger, instance)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.Register(ctx); err != nil {
+		r.logger.Error("failed to register", "error", err)
+	} else {
+		r.logger.Info("service registered", "lease_id", r.leaseId)
+	}
+	keepAliveCtx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		r.KeepAlive(keepAliveCtx)
+	}()
+	return r, nil
+}
+
+// Register 注册实例并取得租约，重复注册时替换原有租约
+func (r *Registrar) Register(ctx context.Context) error {
+	resp, err := r.client.Register(ctx, &service.RegisterRequest{
+		ServiceName:    r.name,
+		ServiceAddress: r.host,
+		ServicePort:    r.port,
+		Ttl:            r.ttl,
+		Weight:         r.weight,
+		Load:           r.currentLoad(),
+		Metadata:       r.metadata,
+	})
+	if err != nil {
+		return err
+	}
+	r.leaseId = resp.LeaseId
+	r.interval = max(time.Duration(resp.Ttl)*time.Second/3, time.Second)
+	return nil
+}
+
+// KeepAlive 定期续约，租约过期或未注册时重新注册，直到ctx结束
+func (r *Registrar) KeepAlive(ctx context.Context) {
+	timer := time.NewTimer(r.interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			if err := r.renew(ctx); err != nil {
+				r.logger.Error("failed to keep alive registration", "error", err)
+			}
+			timer.Reset(r.interval)
+		}
+	}
+}
+
+func (r *Registrar) renew(ctx context.Context) error {
+	if len(r.leaseId) > 0 {
+		_, err := r.client.KeepAlive(ctx, &service.KeepAliveRequest{LeaseId: r.leaseId, Load: r.currentLoad()})
+		if status.Code(err) != codes.NotFound {
+			return err
+		}
+		// 租约已过期，实例已被清除，重新注册
+		r.logger.Warn("lease expired, register again", "lease_id", r.leaseId)
+		r.leaseId = ""
+	}
+	if err := r.Register(ctx); err != nil {
+		return err
+	}
+	r.logger.Info("service registered", "lease_id", r.leaseId)
+	return nil
+}
+
+func (r *Registrar) currentLoad() int64 {
+	if r.load == nil {
+		return 0
+	}
+	return r.load()
+}
+
+// Deregister 停止续约并注销实例，未注册时视为成功
+func (r *Registrar) Deregister(ctx context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+		<-r.done
+	}
+	_, err := r.client.Deregister(ctx, &service.DeregisterRequest{
+		ServiceName:    r.name,
+		ServiceAddress: r.host,
+		ServicePort:    r.port,
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil
+	}
+	return err
+}
+
+// Metadata 返回实例的可用区和版本元数据，未配置的项不设置
+func Metadata(zone string, version string) map[string]string {
+	metadata := make(map[string]string)
+	if len(zone) > 0 {
+		metadata[service.MetadataZone] = zone
+	}
+	if len(version) > 0 {
+		metadata[service.MetadataVersion] = version
+	}
+	return metadata
+}
+
+// AdvertiseAddr 返回注册到发现服务的地址
+// 未配置advertise时使用监听端口，监听地址未指定主机时使用本机第一个非回环IPv4地址
+func AdvertiseAddr(advertise string, listen string) (string, error) {
+	if len(advertise) > 0 {
+		return advertise, nil
+	}
+	host, port, err := net.SplitHostPort(listen)
+	if err != nil {
+		return "", err
+	}
+	if ip := net.ParseIP(host); len(host) > 0 && (ip == nil || !ip.IsUnspecified()) {
+		return listen, nil
+	}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "", err
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			return net.JoinHostPort(ipNet.IP.String(), port), nil
+		}
+	}
+	return net.JoinHostPort("127.0.0.1", port), nil
+}
@@ -0,0 +1,185 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"im/server/discovery/rpc/service"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 记录注册信息和租约的发现服务客户端
+type fakeDiscoveryClient struct {
+	service.DiscoveryClient
+	mu         sync.Mutex
+	instances  map[string]string // 实例 -> 租约ID
+	leases     map[string]string // 租约ID -> 实例
+	registers  int
+	keepAlives int
+	weight     int32 // 最近一次注册的权重
+	load       int64 // 最近一次上报的负载
+}
+
+func newFakeDiscoveryClient() *fakeDiscoveryClient {
+	return &fakeDiscoveryClient{instances: make(map[string]string), leases: make(map[string]string)}
+}
+
+func (c *fakeDiscoveryClient) Register(ctx context.Context, in *service.RegisterRequest, opts ...grpc.CallOption) (*service.RegisterResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.registers++
+	c.weight = in.Weight
+	c.load = in.Load
+	key := in.ServiceName + "/" + net.JoinHostPort(in.ServiceAddress, in.ServicePort)
+	if old, ok := c.instances[key]; ok {
+		delete(c.leases, old)
+	}
+	leaseId := fmt.Sprintf("lease-%d", c.registers)
+	c.instances[key] = leaseId
+	c.leases[leaseId] = key
+	return &service.RegisterResponse{LeaseId: leaseId, Ttl: 3}, nil
+}
+
+func (c *fakeDiscoveryClient) KeepAlive(ctx context.Context, in *service.KeepAliveRequest, opts ...grpc.CallOption) (*service.KeepAliveResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.leases[in.LeaseId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "lease not found")
+	}
+	c.keepAlives++
+	c.load = in.Load
+	return &service.KeepAliveResponse{Ttl: 3}, nil
+}
+
+func (c *fakeDiscoveryClient) Deregister(ctx context.Context, in *service.DeregisterRequest, opts ...grpc.CallOption) (*service.DeregisterResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := in.ServiceName + "/" + net.JoinHostPort(in.ServiceAddress, in.ServicePort)
+	leaseId, ok := c.instances[key]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "address not found in service")
+	}
+	delete(c.instances, key)
+	delete(c.leases, leaseId)
+	return &service.DeregisterResponse{}, nil
+}
+
+// 租约过期，发现服务清除实例
+func (c *fakeDiscoveryClient) expire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.instances = make(map[string]string)
+	c.leases = make(map[string]string)
+}
+
+func (c *fakeDiscoveryClient) state() (instances int, registers int, keepAlives int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.instances), c.registers, c.keepAlives
+}
+
+func waitFor(t *testing.T, msg string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal(msg)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRegistrar(t *testing.T) {
+	client := newFakeDiscoveryClient()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	var load atomic.Int64
+	load.Store(3)
+	registrar, err := NewRegistrar(client, logger, Instance{Name: ServiceIMGateway, Addr: "10.0.0.1:8086", Weight: 2, Load: load.Load})
+	if err != nil {
+		t.Fatalf("failed to create registrar: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := registrar.Register(ctx); err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	if registrar.leaseId != "lease-1" || registrar.interval != time.Second {
+		t.Fatalf("unexpected lease %s interval %s", registrar.leaseId, registrar.interval)
+	}
+	// 重复注册替换原有租约
+	if err := registrar.Register(ctx); err != nil {
+		t.Fatalf("failed to register again: %v", err)
+	}
+	if instances, _, _ := client.state(); instances != 1 {
+		t.Fatalf("expected 1 instance, got %d", instances)
+	}
+	client.mu.Lock()
+	if client.weight != 2 || client.load != 3 {
+		t.Fatalf("unexpected weight %d load %d", client.weight, client.load)
+	}
+	client.mu.Unlock()
+
+	keepAliveCtx, stop := context.WithCancel(ctx)
+	registrar.cancel = stop
+	registrar.done = make(chan struct{})
+	go func() {
+		defer close(registrar.done)
+		registrar.KeepAlive(keepAliveCtx)
+	}()
+	// 续约时上报最新负载
+	load.Store(5)
+	waitFor(t, "lease not renewed", func() bool {
+		client.mu.Lock()
+		defer client.mu.Unlock()
+		return client.keepAlives > 0 && client.load == 5
+	})
+
+	// 租约过期后重新注册
+	client.expire()
+	waitFor(t, "registration not recovered", func() bool {
+		instances, registers, _ := client.state()
+		return instances == 1 && registers == 3
+	})
+
+	if err := registrar.Deregister(context.Background()); err != nil {
+		t.Fatalf("failed to deregister: %v", err)
+	}
+	if err := registrar.Deregister(context.Background()); err != nil {
+		t.Fatalf("failed to deregister again: %v", err)
+	}
+	if instances, _, _ := client.state(); instances != 0 {
+		t.Fatalf("expected no instances, got %d", instances)
+	}
+}
+
+func TestAdvertiseAddr(t *testing.T) {
+	if addr, _ := AdvertiseAddr("im.example.com:8086", ":8086"); addr != "im.example.com:8086" {
+		t.Fatalf("expected configured address, got %s", addr)
+	}
+	if addr, _ := AdvertiseAddr("", "10.0.0.1:8086"); addr != "10.0.0.1:8086" {
+		t.Fatalf("expected listen address, got %s", addr)
+	}
+	for _, listen := range []string{":8086", "0.0.0.0:8086"} {
+		addr, err := AdvertiseAddr("", listen)
+		if err != nil {
+			t.Fatalf("failed to get advertise address: %v", err)
+		}
+		host, port, _ := net.SplitHostPort(addr)
+		if port != "8086" || net.ParseIP(host) == nil || net.ParseIP(host).IsUnspecified() {
+			t.Fatalf("unexpected advertise address %s", addr)
+		}
+	}
+	if _, err := AdvertiseAddr("", "invalid"); err == nil {
+		t.Fatalf("expected error for invalid address")
+	}
+}
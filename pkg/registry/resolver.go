@@ -0,0 +1,140 @@
+package registry
+
+import (
+	"context"
+	"im/server/discovery/rpc/service"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/resolver"
+)
+
+// Scheme 通过发现服务解析地址，目标格式为 discovery:///<service_name>
+const Scheme = "discovery"
+
+// 订阅断开后的重试间隔
+const (
+	minWatchBackoff = 100 * time.Millisecond
+	maxWatchBackoff = 10 * time.Second
+)
+
+// Target 返回服务在grpc.NewClient中使用的目标地址
+func Target(name string) string {
+	return Scheme + ":///" + name
+}
+
+type resolverBuilder struct {
+	client service.DiscoveryClient
+	logger *slog.Logger
+}
+
+// NewResolverBuilder 通过grpc.WithResolvers使用，订阅发现服务的实例变更并更新连接地址
+func NewResolverBuilder(client service.DiscoveryClient, logger *slog.Logger) resolver.Builder {
+	return &resolverBuilder{client: client, logger: logger}
+}
+
+func (b *resolverBuilder) Scheme() string {
+	return Scheme
+}
+
+func (b *resolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &discoveryResolver{
+		client:    b.client,
+		logger:    b.logger.With("target", target.String()),
+		name:      target.Endpoint(),
+		cc:        cc,
+		cancel:    cancel,
+		instances: make(map[string]struct{}),
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.watch(ctx)
+	}()
+	return r, nil
+}
+
+type discoveryResolver struct {
+	client    service.DiscoveryClient
+	logger    *slog.Logger
+	name      string
+	cc        resolver.ClientConn
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	instances map[string]struct{} // 当前实例 host:port，只在watch协程中访问
+}
+
+// ResolveNow 地址由订阅推送，不需要主动解析
+func (r *discoveryResolver) ResolveNow(resolver.ResolveNowOptions) {}
+
+func (r *discoveryResolver) Close() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// 订阅实例变更，断开后退避重试，重新订阅时以快照为准
+func (r *discoveryResolver) watch(ctx context.Context) {
+	backoff := minWatchBackoff
+	for {
+		received, err := r.watchOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if received {
+			backoff = minWatchBackoff
+		}
+		r.logger.Warn("watch discovery failed, retry later", "error", err, "backoff", backoff)
+		if !received {
+			r.cc.ReportError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxWatchBackoff)
+	}
+}
+
+// 返回是否收到过快照
+func (r *discoveryResolver) watchOnce(ctx context.Context) (bool, error) {
+	stream, err := r.client.Watch(ctx, &service.WatchRequest{ServiceName: r.name})
+	if err != nil {
+		return false, err
+	}
+	received := false
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return received, err
+		}
+		if event.Type == service.EventType_EVENT_TYPE_SNAPSHOT {
+			received = true
+			r.instances = make(map[string]struct{}, len(event.ServiceInfo))
+		}
+		for _, info := range event.ServiceInfo {
+			addr := net.JoinHostPort(info.ServiceAddress, info.ServicePort)
+			// 健康检查失败的实例不再连接，恢复后收到UPDATE重新加入
+			if event.Type == service.EventType_EVENT_TYPE_REMOVE || info.Health == service.HealthStatus_HEALTH_STATUS_UNHEALTHY {
+				delete(r.instances, addr)
+			} else {
+				r.instances[addr] = struct{}{}
+			}
+		}
+		if err := r.update(); err != nil {
+			r.logger.Warn("failed to update resolver state", "error", err)
+		}
+	}
+}
+
+func (r *discoveryResolver) update() error {
+	addresses := make([]resolver.Address, 0, len(r.instances))
+	for addr := range r.instances {
+		addresses = append(addresses, resolver.Address{Addr: addr})
+	}
+	r.logger.Debug("resolver state updated", "instances", len(addresses))
+	return r.cc.UpdateState(resolver.State{Addresses: addresses})
+}
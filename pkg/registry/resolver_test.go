@@ -0,0 +1,199 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"im/server/discovery/rpc/service"
+	"log/slog"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+)
+
+// 由测试推送事件的订阅流
+type fakeWatchStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	events chan *service.WatchEvent
+}
+
+func (s *fakeWatchStream) Recv() (*service.WatchEvent, error) {
+	select {
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	case event, ok := <-s.events:
+		if !ok {
+			return nil, errors.New("stream closed")
+		}
+		return event, nil
+	}
+}
+
+type fakeWatchClient struct {
+	service.DiscoveryClient
+	streams chan *fakeWatchStream
+}
+
+func (c *fakeWatchClient) Watch(ctx context.Context, in *service.WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[service.WatchEvent], error) {
+	stream := &fakeWatchStream{ctx: ctx, events: make(chan *service.WatchEvent, 16)}
+	c.streams <- stream
+	return stream, nil
+}
+
+// 启动只提供健康检查的后端
+func startBackend(t *testing.T) *service.ServiceInfo {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	return &service.ServiceInfo{ServiceAddress: host, ServicePort: port}
+}
+
+func dialDiscovery(t *testing.T, balancerName string) (healthpb.HealthClient, *fakeWatchClient) {
+	client := &fakeWatchClient{streams: make(chan *fakeWatchStream, 4)}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	conn, err := grpc.NewClient(Target(ServiceAPIGateway),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithResolvers(NewResolverBuilder(client, logger)),
+		grpc.WithDefaultServiceConfig(ServiceConfig(balancerName)),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.Connect()
+	return healthpb.NewHealthClient(conn), client
+}
+
+func nextStream(t *testing.T, client *fakeWatchClient) *fakeWatchStream {
+	t.Helper()
+	select {
+	case stream := <-client.streams:
+		return stream
+	case <-time.After(5 * time.Second):
+		t.Fatalf("resolver did not watch")
+		return nil
+	}
+}
+
+// 返回处理请求的后端地址
+func check(t *testing.T, ctx context.Context, client healthpb.HealthClient) string {
+	t.Helper()
+	var p peer.Peer
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Peer(&p), grpc.WaitForReady(true)); err != nil {
+		t.Fatalf("failed to check: %v", err)
+	}
+	return p.Addr.String()
+}
+
+func addrOf(info *service.ServiceInfo) string {
+	return net.JoinHostPort(info.ServiceAddress, info.ServicePort)
+}
+
+func TestResolverRoundRobin(t *testing.T) {
+	first, second := startBackend(t), startBackend(t)
+	client, discovery := dialDiscovery(t, BalancerRoundRobin)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream := nextStream(t, discovery)
+	stream.events <- &service.WatchEvent{Type: service.EventType_EVENT_TYPE_SNAPSHOT, ServiceInfo: []*service.ServiceInfo{first}}
+	if addr := check(t, ctx, client); addr != addrOf(first) {
+		t.Fatalf("expected %s, got %s", addrOf(first), addr)
+	}
+
+	// 新增实例后请求分布到两个实例
+	stream.events <- &service.WatchEvent{Type: service.EventType_EVENT_TYPE_ADD, ServiceInfo: []*service.ServiceInfo{second}}
+	waitFor(t, "new instance not used", func() bool {
+		return check(t, ctx, client) == addrOf(second)
+	})
+	seen := make(map[string]int)
+	for range 10 {
+		seen[check(t, ctx, client)]++
+	}
+	if seen[addrOf(first)] != 5 || seen[addrOf(second)] != 5 {
+		t.Fatalf("expected requests evenly distributed, got %v", seen)
+	}
+
+	// 健康检查失败的实例不再使用，恢复后重新加入
+	unhealthy := &service.ServiceInfo{ServiceAddress: second.ServiceAddress, ServicePort: second.ServicePort, Health: service.HealthStatus_HEALTH_STATUS_UNHEALTHY}
+	stream.events <- &service.WatchEvent{Type: service.EventType_EVENT_TYPE_UPDATE, ServiceInfo: []*service.ServiceInfo{unhealthy}}
+	waitFor(t, "unhealthy instance still used", func() bool {
+		for range 4 {
+			if check(t, ctx, client) == addrOf(second) {
+				return false
+			}
+		}
+		return true
+	})
+	recovered := &service.ServiceInfo{ServiceAddress: second.ServiceAddress, ServicePort: second.ServicePort, Health: service.HealthStatus_HEALTH_STATUS_HEALTHY}
+	stream.events <- &service.WatchEvent{Type: service.EventType_EVENT_TYPE_UPDATE, ServiceInfo: []*service.ServiceInfo{recovered}}
+	waitFor(t, "recovered instance not used", func() bool {
+		return check(t, ctx, client) == addrOf(second)
+	})
+
+	// 实例移除后不再使用
+	stream.events <- &service.WatchEvent{Type: service.EventType_EVENT_TYPE_REMOVE, ServiceInfo: []*service.ServiceInfo{first}}
+	waitFor(t, "removed instance still used", func() bool {
+		for range 4 {
+			if check(t, ctx, client) == addrOf(first) {
+				return false
+			}
+		}
+		return true
+	})
+
+	// 订阅断开后重新订阅，以快照为准
+	close(stream.events)
+	stream = nextStream(t, discovery)
+	stream.events <- &service.WatchEvent{Type: service.EventType_EVENT_TYPE_SNAPSHOT, ServiceInfo: []*service.ServiceInfo{first}}
+	waitFor(t, "snapshot not applied", func() bool {
+		for range 4 {
+			if check(t, ctx, client) != addrOf(first) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func TestResolverConsistentHash(t *testing.T) {
+	first, second := startBackend(t), startBackend(t)
+	client, discovery := dialDiscovery(t, BalancerConsistentHash)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream := nextStream(t, discovery)
+	stream.events <- &service.WatchEvent{Type: service.EventType_EVENT_TYPE_SNAPSHOT, ServiceInfo: []*service.ServiceInfo{first, second}}
+	// 等待两个实例都就绪
+	waitFor(t, "instances not ready", func() bool {
+		seen := make(map[string]struct{})
+		for range 4 {
+			seen[check(t, ctx, client)] = struct{}{}
+		}
+		return len(seen) == 2
+	})
+
+	// 相同key的请求发往同一实例
+	for _, key := range []string{"user-1", "user-2", "user-3"} {
+		keyCtx := WithBalanceKey(ctx, key)
+		addr := check(t, keyCtx, client)
+		for range 5 {
+			if got := check(t, keyCtx, client); got != addr {
+				t.Fatalf("key %s moved from %s to %s", key, addr, got)
+			}
+		}
+	}
+}
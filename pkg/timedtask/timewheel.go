@@ -1,17 +1,20 @@
 package timedtask
 
 import (
-	"fmt"
 	"math"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type TimeWheel struct {
+	mu       sync.Mutex
 	interval time.Duration
 	slots    []*Slot
 	currSlot int
+	stop     chan struct{}
+	stopOnce sync.Once
 }
 
 type Slot struct {
@@ -29,29 +32,38 @@ type Task struct {
 }
 
 func (s *Slot) AddTask(task *Task) {
+	task.next = nil
 	if s.head == nil {
 		s.head = task
-		s.tail = task
 	} else {
 		s.tail.next = task
 	}
+	s.tail = task
 }
 
-func (s *Slot) RemoveTask(uuid string) {
-	for preTask, task := s.head, s.head; task != nil; {
-		if task.uuid == uuid {
-			next := task.next
-			task.next = nil
-			if preTask != task {
-				preTask.next = next
-			}
-			task = next
-			break
-		} else {
-			preTask = task
-			task = task.next
+// RemoveTask 移除任务，返回是否找到
+func (s *Slot) RemoveTask(uuid string) bool {
+	var preTask *Task
+	for task := s.head; task != nil; preTask, task = task, task.next {
+		if task.uuid != uuid {
+			continue
 		}
+		s.unlink(preTask, task)
+		return true
+	}
+	return false
+}
+
+func (s *Slot) unlink(preTask *Task, task *Task) {
+	if preTask == nil {
+		s.head = task.next
+	} else {
+		preTask.next = task.next
 	}
+	if s.tail == task {
+		s.tail = preTask
+	}
+	task.next = nil
 }
 
 func NewTimeWheel(interval time.Duration, slotnum int) *TimeWheel {
@@ -65,6 +77,7 @@ func NewTimeWheel(interval time.Duration, slotnum int) *TimeWheel {
 	t := &TimeWheel{
 		interval: interval,
 		slots:    slots,
+		stop:     make(chan struct{}),
 	}
 	go t.Start()
 	return t
@@ -72,81 +85,94 @@ func NewTimeWheel(interval time.Duration, slotnum int) *TimeWheel {
 
 func (t *TimeWheel) Start() {
 	ticker := time.NewTicker(t.interval)
-	for range ticker.C {
-		currSlot := t.currSlot
-
-		fmt.Println("currSlot", currSlot)
-		slot := t.slots[currSlot]
-		for preTask, task := slot.head, slot.head; task != nil; {
-			if task.circle > 0 {
-				task.circle--
-				preTask = task
-				task = task.next
-				continue
-			}
-			go task.function()
-
-			switch task.tType {
-			case 2:
-				t.addIntervalTask(task.uuid, task.function, task.period)
-			}
-
-			next := task.next
-			task.next = nil
-			if preTask == task {
-				slot.head = next
-				preTask = next
-			} else {
-				preTask.next = next
-			}
-
-			task = next
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-ticker.C:
+			t.tick()
 		}
+	}
+}
 
-		t.currSlot = (currSlot + 1) % len(t.slots)
+// Stop 停止时间轮，未执行的任务将被丢弃
+func (t *TimeWheel) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.stop)
+	})
+}
+
+func (t *TimeWheel) tick() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	slot := t.slots[t.currSlot]
+	expired := make([]*Task, 0)
+	var preTask *Task
+	for task := slot.head; task != nil; {
+		next := task.next
+		if task.circle > 0 {
+			task.circle--
+			preTask = task
+		} else {
+			slot.unlink(preTask, task)
+			expired = append(expired, task)
+		}
+		task = next
+	}
+	t.currSlot = (t.currSlot + 1) % len(t.slots)
+
+	// 间隔任务在当前槽位遍历结束后再重新入轮，避免落入同一槽位被重复处理
+	for _, task := range expired {
+		go task.function()
+		if task.tType == 2 {
+			t.addTask(task.uuid, 2, task.function, task.period)
+		}
 	}
 }
 
 // 延迟任务，在delay时间后执行
 func (t *TimeWheel) AddDelayTask(function func(), delay time.Duration) string {
 	uuid := uuid.New().String()
-	circle := int(float64(delay) / float64(t.interval*time.Duration(len(t.slots))))
-	pos := (t.currSlot + int(math.Ceil(float64(delay)/float64(t.interval)))) % len(t.slots)
-	t.slots[pos].AddTask(&Task{
-		uuid:     uuid,
-		tType:    1,
-		function: function,
-		circle:   int(circle),
-	})
-	fmt.Println("addDelayTask", uuid, t.currSlot, pos, circle)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.addTask(uuid, 1, function, delay)
 	return uuid
 }
 
 // 间隔任务，每interval时间执行一次
 func (t *TimeWheel) AddIntervalTask(function func(), period time.Duration) string {
 	uuid := uuid.New().String()
-	t.addIntervalTask(uuid, function, period)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.addTask(uuid, 2, function, period)
 	return uuid
 }
 
-func (t *TimeWheel) addIntervalTask(uuid string, function func(), period time.Duration) string {
-	circle := int(float64(period) / float64(t.interval*time.Duration(len(t.slots))))
-	pos := (t.currSlot + int(math.Ceil(float64(period)/float64(t.interval)))) % len(t.slots)
-	fmt.Println("addIntervalTask", uuid, t.currSlot, pos, circle)
+// 调用方需持有锁；下一次tick处理currSlot，因此需要ticks-1个槽位的偏移
+func (t *TimeWheel) addTask(uuid string, tType int, function func(), period time.Duration) {
+	ticks := int(math.Ceil(float64(period) / float64(t.interval)))
+	if ticks < 1 {
+		ticks = 1
+	}
+	pos := (t.currSlot + ticks - 1) % len(t.slots)
 	t.slots[pos].AddTask(&Task{
 		uuid:     uuid,
-		tType:    2,
+		tType:    tType,
 		function: function,
-		circle:   int(circle),
+		circle:   (ticks - 1) / len(t.slots),
 		period:   period,
 	})
-	return uuid
 }
 
 // 移除任务
 func (t *TimeWheel) RemoveTask(uuid string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	for _, slot := range t.slots {
-		slot.RemoveTask(uuid)
-		break
+		if slot.RemoveTask(uuid) {
+			break
+		}
 	}
 }
@@ -24,3 +24,35 @@ func TestTimeWheel(t *testing.T) {
 
 	select {}
 }
+
+func TestTimeWheelRemoveTask(t *testing.T) {
+	timeWheel := NewTimeWheel(10*time.Millisecond, 4)
+	defer timeWheel.Stop()
+
+	fired := make(chan int, 4)
+	// 同一槽位多个任务，并跨越多圈
+	for i := 0; i < 3; i++ {
+		timeWheel.AddDelayTask(func() { fired <- i }, 60*time.Millisecond)
+	}
+	removed := timeWheel.AddDelayTask(func() { fired <- -1 }, 60*time.Millisecond)
+	timeWheel.RemoveTask(removed)
+
+	got := make(map[int]bool)
+	timeout := time.After(time.Second)
+	for len(got) < 3 {
+		select {
+		case i := <-fired:
+			if i < 0 {
+				t.Fatal("removed task fired")
+			}
+			got[i] = true
+		case <-timeout:
+			t.Fatalf("tasks not fired: %v", got)
+		}
+	}
+	select {
+	case i := <-fired:
+		t.Fatalf("unexpected task fired: %d", i)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
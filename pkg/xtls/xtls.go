@@ -0,0 +1,232 @@
+package xtls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"im/pkg/config"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Reloader 持有当前的证书和CA，文件变更后自动重新加载，已建立的连接不受影响
+type Reloader struct {
+	conf   config.TLSConfig
+	logger *slog.Logger
+
+	locker  sync.RWMutex
+	cert    *tls.Certificate
+	pool    *x509.CertPool
+	modTime time.Time // 证书文件的最近修改时间，用于检测变更
+}
+
+// NewReloader 加载证书并按conf.ReloadInterval检测文件变更，ctx结束后停止检测
+func NewReloader(ctx context.Context, conf config.TLSConfig, logger *slog.Logger) (*Reloader, error) {
+	r := &Reloader{conf: conf, logger: logger}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	if conf.ReloadInterval > 0 {
+		go r.watch(ctx, time.Duration(conf.ReloadInterval)*time.Second)
+	}
+	return r, nil
+}
+
+// Reload 重新读取证书、私钥和CA文件
+func (r *Reloader) Reload() error {
+	modTime := r.latestModTime()
+	var cert *tls.Certificate
+	if len(r.conf.CertFile) > 0 || len(r.conf.KeyFile) > 0 {
+		c, err := tls.LoadX509KeyPair(r.conf.CertFile, r.conf.KeyFile)
+		if err != nil {
+			return fmt.Errorf("load key pair: %w", err)
+		}
+		cert = &c
+	}
+	var pool *x509.CertPool
+	if len(r.conf.CAFile) > 0 {
+		data, err := os.ReadFile(r.conf.CAFile)
+		if err != nil {
+			return fmt.Errorf("read ca: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return errors.New("no certificate found in ca file")
+		}
+	}
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	r.cert = cert
+	r.pool = pool
+	r.modTime = modTime
+	return nil
+}
+
+func (r *Reloader) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.locker.RLock()
+			modTime := r.modTime
+			r.locker.RUnlock()
+			if !r.latestModTime().After(modTime) {
+				continue
+			}
+			// 证书和私钥可能没有同时写完，加载失败时保留旧证书等待下次检测
+			if err := r.Reload(); err != nil {
+				r.logger.Error("failed to reload tls certificate", "error", err)
+				continue
+			}
+			r.logger.Info("tls certificate reloaded", "cert_file", r.conf.CertFile, "ca_file", r.conf.CAFile)
+		}
+	}
+}
+
+func (r *Reloader) latestModTime() time.Time {
+	var latest time.Time
+	for _, file := range []string{r.conf.CertFile, r.conf.KeyFile, r.conf.CAFile} {
+		if len(file) == 0 {
+			continue
+		}
+		if info, err := os.Stat(file); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
+
+func (r *Reloader) certificate() (*tls.Certificate, *x509.CertPool) {
+	r.locker.RLock()
+	defer r.locker.RUnlock()
+	return r.cert, r.pool
+}
+
+// ServerConfig 服务端配置，配置了CA时要求并校验客户端证书（mTLS）
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := r.certificate()
+			if cert == nil {
+				return nil, errors.New("no server certificate")
+			}
+			conf := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+			}
+			if pool != nil {
+				conf.ClientCAs = pool
+				conf.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+			return conf, nil
+		},
+	}
+}
+
+// ClientConfig 客户端配置，配置了证书时向服务端出示（mTLS）
+// 为了支持CA热更新，证书链在VerifyConnection中使用当前的CA校验
+func (r *Reloader) ClientConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: r.conf.ServerName,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := r.certificate()
+			if cert == nil {
+				return &tls.Certificate{}, nil
+			}
+			return cert, nil
+		},
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			_, pool := r.certificate()
+			if len(cs.PeerCertificates) == 0 {
+				return errors.New("no server certificate")
+			}
+			opts := x509.VerifyOptions{
+				DNSName:       cs.ServerName,
+				Roots:         pool,
+				Intermediates: x509.NewCertPool(),
+			}
+			for _, cert := range cs.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(cert)
+			}
+			_, err := cs.PeerCertificates[0].Verify(opts)
+			return err
+		},
+	}
+}
+
+// ServerOption gRPC服务端的传输凭证，未启用TLS时使用明文
+func ServerOption(ctx context.Context, conf config.TLSConfig, logger *slog.Logger) (grpc.ServerOption, error) {
+	if !conf.Enabled {
+		return grpc.Creds(insecure.NewCredentials()), nil
+	}
+	r, err := NewReloader(ctx, conf, logger)
+	if err != nil {
+		return nil, err
+	}
+	return grpc.Creds(credentials.NewTLS(r.ServerConfig())), nil
+}
+
+// DialOption gRPC客户端的传输凭证，未启用TLS时使用明文
+func DialOption(ctx context.Context, conf config.TLSConfig, logger *slog.Logger) (grpc.DialOption, error) {
+	if !conf.Enabled {
+		return grpc.WithTransportCredentials(insecure.NewCredentials()), nil
+	}
+	r, err := NewReloader(ctx, conf, logger)
+	if err != nil {
+		return nil, err
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(r.ClientConfig())), nil
+}
+
+// Listen 监听TCP地址，启用TLS时返回TLS listener
+func Listen(ctx context.Context, addr string, conf config.TLSConfig, logger *slog.Logger) (net.Listener, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if !conf.Enabled {
+		return listener, nil
+	}
+	r, err := NewReloader(ctx, conf, logger)
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return tls.NewListener(listener, r.ServerConfig()), nil
+}
+
+// Dial 连接TCP地址，启用TLS时完成握手后返回
+func Dial(ctx context.Context, addr string, conf config.TLSConfig, logger *slog.Logger) (net.Conn, error) {
+	if !conf.Enabled {
+		return net.Dial("tcp", addr)
+	}
+	// 单次连接只在握手时使用证书，不需要检测文件变更
+	r := &Reloader{conf: conf, logger: logger}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	clientConf := r.ClientConfig()
+	if len(clientConf.ServerName) == 0 {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		clientConf.ServerName = host
+	}
+	return tls.Dial("tcp", addr, clientConf)
+}
@@ -0,0 +1,261 @@
+package xtls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"im/pkg/config"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	dir  string
+}
+
+// 生成自签名CA，证书文件写入临时目录
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "im test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create ca: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	ca := &testCA{cert: cert, key: key, dir: t.TempDir()}
+	writePEM(t, filepath.Join(ca.dir, "ca.pem"), "CERTIFICATE", der)
+	return ca
+}
+
+// 签发证书并写入name.pem和name-key.pem，返回TLS配置
+func (ca *testCA) issue(t *testing.T, name string, serial int64) config.TLSConfig {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	conf := config.TLSConfig{
+		Enabled:  true,
+		CertFile: filepath.Join(ca.dir, name+".pem"),
+		KeyFile:  filepath.Join(ca.dir, name+"-key.pem"),
+		CAFile:   filepath.Join(ca.dir, "ca.pem"),
+	}
+	writePEM(t, conf.CertFile, "CERTIFICATE", der)
+	writePEM(t, conf.KeyFile, "EC PRIVATE KEY", keyDer)
+	return conf
+}
+
+func writePEM(t *testing.T, file string, blockType string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatalf("failed to write %s: %v", file, err)
+	}
+}
+
+func newTestLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stdout, nil))
+}
+
+// 启动回显服务，返回监听地址
+func startEchoServer(t *testing.T, conf config.TLSConfig) string {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	listener, err := Listen(ctx, "127.0.0.1:0", conf, newTestLogger())
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() {
+		listener.Close()
+		cancel()
+	})
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// 发送并读取回显，返回服务端证书序列号
+func echo(conn net.Conn) (int64, error) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		return 0, err
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		return 0, err
+	}
+	return conn.(*tls.Conn).ConnectionState().PeerCertificates[0].SerialNumber.Int64(), nil
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	addr := startEchoServer(t, ca.issue(t, "server", 2))
+	ctx := context.Background()
+
+	conn, err := Dial(ctx, addr, ca.issue(t, "client", 3), newTestLogger())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	if _, err := echo(conn); err != nil {
+		t.Fatalf("failed to echo: %v", err)
+	}
+
+	// 未出示客户端证书时握手失败
+	conn, err = Dial(ctx, addr, config.TLSConfig{Enabled: true, CAFile: filepath.Join(ca.dir, "ca.pem")}, newTestLogger())
+	if err == nil {
+		_, err = echo(conn)
+	}
+	if err == nil {
+		t.Fatalf("expected handshake failure without client certificate")
+	}
+
+	// 不信任的CA签发的服务端证书校验失败
+	other := newTestCA(t)
+	if _, err := Dial(ctx, addr, other.issue(t, "client", 4), newTestLogger()); err == nil {
+		t.Fatalf("expected verification failure with untrusted ca")
+	}
+}
+
+func TestReload(t *testing.T) {
+	ca := newTestCA(t)
+	serverConf := ca.issue(t, "server", 2)
+	serverConf.ReloadInterval = 1
+	addr := startEchoServer(t, serverConf)
+	clientConf := ca.issue(t, "client", 3)
+
+	dial := func() int64 {
+		conn, err := Dial(context.Background(), addr, clientConf, newTestLogger())
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		serial, err := echo(conn)
+		if err != nil {
+			t.Fatalf("failed to echo: %v", err)
+		}
+		return serial
+	}
+	if serial := dial(); serial != 2 {
+		t.Fatalf("expected serial 2, got %d", serial)
+	}
+
+	// 替换证书文件后新连接使用新证书
+	ca.issue(t, "server", 5)
+	future := time.Now().Add(time.Minute)
+	os.Chtimes(serverConf.CertFile, future, future)
+	deadline := time.Now().Add(5 * time.Second)
+	for dial() != 5 {
+		if time.Now().After(deadline) {
+			t.Fatalf("certificate not reloaded")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func TestGRPC(t *testing.T) {
+	ca := newTestCA(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	creds, err := ServerOption(ctx, ca.issue(t, "server", 2), newTestLogger())
+	if err != nil {
+		t.Fatalf("failed to load server tls: %v", err)
+	}
+	server := grpc.NewServer(creds)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go server.Serve(listener)
+	defer server.Stop()
+
+	dialOption, err := DialOption(ctx, ca.issue(t, "client", 3), newTestLogger())
+	if err != nil {
+		t.Fatalf("failed to load client tls: %v", err)
+	}
+	conn, err := grpc.NewClient(listener.Addr().String(), dialOption)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer conn.Close()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("failed to check health: %v", err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("unexpected status: %v", resp.GetStatus())
+	}
+
+	// 明文客户端无法访问TLS服务
+	plain, err := DialOption(ctx, config.TLSConfig{}, newTestLogger())
+	if err != nil {
+		t.Fatalf("failed to load plain option: %v", err)
+	}
+	plainConn, err := grpc.NewClient(listener.Addr().String(), plain)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer plainConn.Close()
+	timeoutCtx, timeoutCancel := context.WithTimeout(ctx, time.Second)
+	defer timeoutCancel()
+	if _, err := healthpb.NewHealthClient(plainConn).Check(timeoutCtx, &healthpb.HealthCheckRequest{}); err == nil {
+		t.Fatalf("expected plaintext client rejected")
+	}
+}
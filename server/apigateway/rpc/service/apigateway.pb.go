@@ -24,7 +24,8 @@ const (
 type HistoryMessageRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	SessionUuid   string                 `protobuf:"bytes,1,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"` // 会话UUID
-	StartSeqid    int64                  `protobuf:"varint,2,opt,name=start_seqid,json=startSeqid,proto3" json:"start_seqid,omitempty"`   // 开始序列号
+	StartSeqid    int64                  `protobuf:"varint,2,opt,name=start_seqid,json=startSeqid,proto3" json:"start_seqid,omitempty"`   // 开始序列号（不包含）
+	EndSeqid      int64                  `protobuf:"varint,3,opt,name=end_seqid,json=endSeqid,proto3" json:"end_seqid,omitempty"`         // 结束序列号（包含），0表示不限制
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -73,6 +74,13 @@ func (x *HistoryMessageRequest) GetStartSeqid() int64 {
 	return 0
 }
 
+func (x *HistoryMessageRequest) GetEndSeqid() int64 {
+	if x != nil {
+		return x.EndSeqid
+	}
+	return 0
+}
+
 type HistoryMessageResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Messages      []*Message             `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"` // 消息列表
@@ -763,12 +771,12 @@ func (x *RegisterResponse) GetRefreshToken() string {
 
 type SendMessageRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	SessionUuid   string                 `protobuf:"bytes,1,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"`  // 会话UUID
-	Payload       string                 `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`                             // 消息
-	SenderUuid    string                 `protobuf:"bytes,3,opt,name=sender_uuid,json=senderUuid,proto3" json:"sender_uuid,omitempty"`     // 消息发送者UUID
-	MessageType   int64                  `protobuf:"varint,4,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"` // 消息类型
-	SeqId         int64                  `protobuf:"varint,5,opt,name=seq_id,json=seqId,proto3" json:"seq_id,omitempty"`                   // 消息序列号ID
-	Timestamp     int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                        // 消息时间戳
+	SessionUuid   string                 `protobuf:"bytes,1,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"`   // 会话UUID
+	Payload       string                 `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`                              // 消息
+	SenderUuid    string                 `protobuf:"bytes,3,opt,name=sender_uuid,json=senderUuid,proto3" json:"sender_uuid,omitempty"`      // 消息发送者UUID
+	MessageType   int64                  `protobuf:"varint,4,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`  // 消息类型
+	Timestamp     int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                         // 消息时间戳
+	ClientMsgId   string                 `protobuf:"bytes,7,opt,name=client_msg_id,json=clientMsgId,proto3" json:"client_msg_id,omitempty"` // 客户端消息ID 同一发送者内唯一，用于幂等
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -831,23 +839,25 @@ func (x *SendMessageRequest) GetMessageType() int64 {
 	return 0
 }
 
-func (x *SendMessageRequest) GetSeqId() int64 {
+func (x *SendMessageRequest) GetTimestamp() int64 {
 	if x != nil {
-		return x.SeqId
+		return x.Timestamp
 	}
 	return 0
 }
 
-func (x *SendMessageRequest) GetTimestamp() int64 {
+func (x *SendMessageRequest) GetClientMsgId() string {
 	if x != nil {
-		return x.Timestamp
+		return x.ClientMsgId
 	}
-	return 0
+	return ""
 }
 
 type SendMessageResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	MessageUuid   string                 `protobuf:"bytes,1,opt,name=message_uuid,json=messageUuid,proto3" json:"message_uuid,omitempty"` // 消息UUID
+	SeqId         int64                  `protobuf:"varint,2,opt,name=seq_id,json=seqId,proto3" json:"seq_id,omitempty"`                  // 消息序列号ID
+	Duplicate     bool                   `protobuf:"varint,3,opt,name=duplicate,proto3" json:"duplicate,omitempty"`                       // 是否为重复请求，重复时返回首次写入的消息
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -889,6 +899,20 @@ func (x *SendMessageResponse) GetMessageUuid() string {
 	return ""
 }
 
+func (x *SendMessageResponse) GetSeqId() int64 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *SendMessageResponse) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
 type GetUserInfoRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -1001,97 +1025,2530 @@ func (x *GetUserInfoResponse) GetMobile() string {
 	return ""
 }
 
-var File_rpc_service_apigateway_proto protoreflect.FileDescriptor
+// 创建群聊会话，由长连接网关携带用户令牌调用
+type CreateSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                  // 会话名称
+	MemberUuids   []string               `protobuf:"bytes,3,rep,name=member_uuids,json=memberUuids,proto3" json:"member_uuids,omitempty"` // 初始成员UUID列表，创建者自动加入
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_rpc_service_apigateway_proto_rawDesc = "" +
-	"\n" +
-	"\x1crpc/service/apigateway.proto\x12\n" +
-	"apigateway\"[\n" +
-	"\x15HistoryMessageRequest\x12!\n" +
-	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuid\x12\x1f\n" +
-	"\vstart_seqid\x18\x02 \x01(\x03R\n" +
-	"startSeqid\"I\n" +
-	"\x16HistoryMessageResponse\x12/\n" +
-	"\bmessages\x18\x01 \x03(\v2\x13.apigateway.MessageR\bmessages\">\n" +
-	"\x19GetSessionUserListRequest\x12!\n" +
-	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuid\"S\n" +
-	"\x1aGetSessionUserListResponse\x125\n" +
-	"\x05users\x18\x01 \x03(\v2\x1f.apigateway.SessionUserListItemR\x05users\"p\n" +
-	"\x13SessionUserListItem\x12\x1b\n" +
-	"\tuser_uuid\x18\x01 \x01(\tR\buserUuid\x12\x1b\n" +
-	"\tuser_name\x18\x02 \x01(\tR\buserName\x12\x1f\n" +
-	"\vuser_avatar\x18\x03 \x01(\tR\n" +
-	"userAvatar\"\xa7\x02\n" +
-	"\aMessage\x12!\n" +
-	"\fmessage_uuid\x18\x01 \x01(\tR\vmessageUuid\x12!\n" +
-	"\fsession_uuid\x18\x02 \x01(\tR\vsessionUuid\x12\x15\n" +
-	"\x06seq_id\x18\x03 \x01(\x03R\x05seqId\x12!\n" +
-	"\fmessage_type\x18\x04 \x01(\x03R\vmessageType\x12\x18\n" +
-	"\acontent\x18\x05 \x01(\tR\acontent\x12\x1f\n" +
-	"\vsender_uuid\x18\x06 \x01(\tR\n" +
-	"senderUuid\x12\x1f\n" +
-	"\vsender_name\x18\a \x01(\tR\n" +
-	"senderName\x12#\n" +
-	"\rsender_avatar\x18\b \x01(\tR\fsenderAvatar\x12\x1b\n" +
-	"\tsend_time\x18\t \x01(\tR\bsendTime\"\x14\n" +
-	"\x12SessionListRequest\"F\n" +
-	"\x13SessionListResponse\x12/\n" +
-	"\bsessions\x18\x01 \x03(\v2\x13.apigateway.SessionR\bsessions\"\xac\x01\n" +
-	"\aSession\x12\x12\n" +
-	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
-	"\x06avatar\x18\x03 \x01(\tR\x06avatar\x12!\n" +
-	"\flast_message\x18\x04 \x01(\tR\vlastMessage\x12\x1b\n" +
-	"\tlast_time\x18\x05 \x01(\tR\blastTime\x12!\n" +
-	"\funread_count\x18\x06 \x01(\x03R\vunreadCount\"s\n" +
-	"\fLoginRequest\x12\x1e\n" +
-	"\n" +
-	"identifier\x18\x01 \x01(\tR\n" +
-	"identifier\x12\x1e\n" +
-	"\n" +
-	"credential\x18\x02 \x01(\tR\n" +
-	"credential\x12#\n" +
-	"\ridentity_type\x18\x03 \x01(\x03R\fidentityType\"J\n" +
-	"\rLoginResponse\x12\x14\n" +
-	"\x05token\x18\x01 \x01(\tR\x05token\x12#\n" +
-	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\"v\n" +
-	"\x0fRegisterRequest\x12\x1e\n" +
-	"\n" +
-	"identifier\x18\x01 \x01(\tR\n" +
-	"identifier\x12\x1e\n" +
-	"\n" +
-	"credential\x18\x02 \x01(\tR\n" +
-	"credential\x12#\n" +
-	"\ridentity_type\x18\x03 \x01(\x03R\fidentityType\"M\n" +
-	"\x10RegisterResponse\x12\x14\n" +
-	"\x05token\x18\x01 \x01(\tR\x05token\x12#\n" +
-	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\"\xca\x01\n" +
-	"\x12SendMessageRequest\x12!\n" +
-	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuid\x12\x18\n" +
-	"\apayload\x18\x02 \x01(\tR\apayload\x12\x1f\n" +
-	"\vsender_uuid\x18\x03 \x01(\tR\n" +
-	"senderUuid\x12!\n" +
-	"\fmessage_type\x18\x04 \x01(\x03R\vmessageType\x12\x15\n" +
-	"\x06seq_id\x18\x05 \x01(\x03R\x05seqId\x12\x1c\n" +
-	"\ttimestamp\x18\x06 \x01(\x03R\ttimestamp\"8\n" +
-	"\x13SendMessageResponse\x12!\n" +
-	"\fmessage_uuid\x18\x01 \x01(\tR\vmessageUuid\"\x14\n" +
-	"\x12GetUserInfoRequest\"\x83\x01\n" +
-	"\x13GetUserInfoResponse\x12\x12\n" +
-	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
-	"\x06avatar\x18\x03 \x01(\tR\x06avatar\x12\x14\n" +
-	"\x05email\x18\x04 \x01(\tR\x05email\x12\x16\n" +
-	"\x06mobile\x18\x05 \x01(\tR\x06mobile2\xbf\x04\n" +
-	"\n" +
-	"APIGateway\x12N\n" +
-	"\vSessionList\x12\x1e.apigateway.SessionListRequest\x1a\x1f.apigateway.SessionListResponse\x12c\n" +
-	"\x12GetSessionUserList\x12%.apigateway.GetSessionUserListRequest\x1a&.apigateway.GetSessionUserListResponse\x12W\n" +
-	"\x0eHistoryMessage\x12!.apigateway.HistoryMessageRequest\x1a\".apigateway.HistoryMessageResponse\x12<\n" +
-	"\x05Login\x12\x18.apigateway.LoginRequest\x1a\x19.apigateway.LoginResponse\x12E\n" +
-	"\bRegister\x12\x1b.apigateway.RegisterRequest\x1a\x1c.apigateway.RegisterResponse\x12N\n" +
-	"\vSendMessage\x12\x1e.apigateway.SendMessageRequest\x1a\x1f.apigateway.SendMessageResponse\x12N\n" +
-	"\vGetUserInfo\x12\x1e.apigateway.GetUserInfoRequest\x1a\x1f.apigateway.GetUserInfoResponseB\fZ\n" +
+func (x *CreateSessionRequest) Reset() {
+	*x = CreateSessionRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSessionRequest) ProtoMessage() {}
+
+func (x *CreateSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSessionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSessionRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CreateSessionRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateSessionRequest) GetMemberUuids() []string {
+	if x != nil {
+		return x.MemberUuids
+	}
+	return nil
+}
+
+type CreateSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionUuid   string                 `protobuf:"bytes,1,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"` // 会话UUID
+	MemberUuids   []string               `protobuf:"bytes,2,rep,name=member_uuids,json=memberUuids,proto3" json:"member_uuids,omitempty"` // 当前成员UUID列表
+	Message       *Message               `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`                            // 成员变更系统消息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSessionResponse) Reset() {
+	*x = CreateSessionResponse{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSessionResponse) ProtoMessage() {}
+
+func (x *CreateSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSessionResponse.ProtoReflect.Descriptor instead.
+func (*CreateSessionResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CreateSessionResponse) GetSessionUuid() string {
+	if x != nil {
+		return x.SessionUuid
+	}
+	return ""
+}
+
+func (x *CreateSessionResponse) GetMemberUuids() []string {
+	if x != nil {
+		return x.MemberUuids
+	}
+	return nil
+}
+
+func (x *CreateSessionResponse) GetMessage() *Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+// 邀请用户加入会话，由长连接网关携带用户令牌调用
+type JoinSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionUuid   string                 `protobuf:"bytes,1,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"` // 会话UUID
+	UserUuids     []string               `protobuf:"bytes,3,rep,name=user_uuids,json=userUuids,proto3" json:"user_uuids,omitempty"`       // 被邀请的用户UUID列表
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinSessionRequest) Reset() {
+	*x = JoinSessionRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinSessionRequest) ProtoMessage() {}
+
+func (x *JoinSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinSessionRequest.ProtoReflect.Descriptor instead.
+func (*JoinSessionRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *JoinSessionRequest) GetSessionUuid() string {
+	if x != nil {
+		return x.SessionUuid
+	}
+	return ""
+}
+
+func (x *JoinSessionRequest) GetUserUuids() []string {
+	if x != nil {
+		return x.UserUuids
+	}
+	return nil
+}
+
+type JoinSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JoinedUuids   []string               `protobuf:"bytes,1,rep,name=joined_uuids,json=joinedUuids,proto3" json:"joined_uuids,omitempty"` // 实际加入的用户UUID列表，已是成员的会被忽略
+	MemberUuids   []string               `protobuf:"bytes,2,rep,name=member_uuids,json=memberUuids,proto3" json:"member_uuids,omitempty"` // 当前成员UUID列表
+	Message       *Message               `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`                            // 成员变更系统消息，没有用户加入时为空
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinSessionResponse) Reset() {
+	*x = JoinSessionResponse{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinSessionResponse) ProtoMessage() {}
+
+func (x *JoinSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinSessionResponse.ProtoReflect.Descriptor instead.
+func (*JoinSessionResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *JoinSessionResponse) GetJoinedUuids() []string {
+	if x != nil {
+		return x.JoinedUuids
+	}
+	return nil
+}
+
+func (x *JoinSessionResponse) GetMemberUuids() []string {
+	if x != nil {
+		return x.MemberUuids
+	}
+	return nil
+}
+
+func (x *JoinSessionResponse) GetMessage() *Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+// 退出会话，由长连接网关携带用户令牌调用
+type LeaveSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionUuid   string                 `protobuf:"bytes,1,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"` // 会话UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaveSessionRequest) Reset() {
+	*x = LeaveSessionRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveSessionRequest) ProtoMessage() {}
+
+func (x *LeaveSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveSessionRequest.ProtoReflect.Descriptor instead.
+func (*LeaveSessionRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *LeaveSessionRequest) GetSessionUuid() string {
+	if x != nil {
+		return x.SessionUuid
+	}
+	return ""
+}
+
+type LeaveSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MemberUuids   []string               `protobuf:"bytes,1,rep,name=member_uuids,json=memberUuids,proto3" json:"member_uuids,omitempty"` // 当前成员UUID列表
+	Message       *Message               `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`                            // 成员变更系统消息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaveSessionResponse) Reset() {
+	*x = LeaveSessionResponse{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveSessionResponse) ProtoMessage() {}
+
+func (x *LeaveSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveSessionResponse.ProtoReflect.Descriptor instead.
+func (*LeaveSessionResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *LeaveSessionResponse) GetMemberUuids() []string {
+	if x != nil {
+		return x.MemberUuids
+	}
+	return nil
+}
+
+func (x *LeaveSessionResponse) GetMessage() *Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+// 创建群聊，创建者成为群主
+type CreateGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                  // 群名称，为空时使用默认名称
+	Avatar        string                 `protobuf:"bytes,2,opt,name=avatar,proto3" json:"avatar,omitempty"`                              // 群头像
+	MemberUuids   []string               `protobuf:"bytes,3,rep,name=member_uuids,json=memberUuids,proto3" json:"member_uuids,omitempty"` // 初始成员UUID列表
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateGroupRequest) Reset() {
+	*x = CreateGroupRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateGroupRequest) ProtoMessage() {}
+
+func (x *CreateGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateGroupRequest.ProtoReflect.Descriptor instead.
+func (*CreateGroupRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CreateGroupRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateGroupRequest) GetAvatar() string {
+	if x != nil {
+		return x.Avatar
+	}
+	return ""
+}
+
+func (x *CreateGroupRequest) GetMemberUuids() []string {
+	if x != nil {
+		return x.MemberUuids
+	}
+	return nil
+}
+
+type CreateGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionUuid   string                 `protobuf:"bytes,1,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"` // 会话UUID
+	MemberUuids   []string               `protobuf:"bytes,2,rep,name=member_uuids,json=memberUuids,proto3" json:"member_uuids,omitempty"` // 当前成员UUID列表
+	Message       *Message               `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`                            // 成员变更系统消息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateGroupResponse) Reset() {
+	*x = CreateGroupResponse{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateGroupResponse) ProtoMessage() {}
+
+func (x *CreateGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateGroupResponse.ProtoReflect.Descriptor instead.
+func (*CreateGroupResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CreateGroupResponse) GetSessionUuid() string {
+	if x != nil {
+		return x.SessionUuid
+	}
+	return ""
+}
+
+func (x *CreateGroupResponse) GetMemberUuids() []string {
+	if x != nil {
+		return x.MemberUuids
+	}
+	return nil
+}
+
+func (x *CreateGroupResponse) GetMessage() *Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+// 修改群名称和头像，群主和管理员可用
+type UpdateGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionUuid   string                 `protobuf:"bytes,1,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"` // 会话UUID
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                  // 新群名称，为空时不修改
+	Avatar        string                 `protobuf:"bytes,3,opt,name=avatar,proto3" json:"avatar,omitempty"`                              // 新群头像，为空时不修改
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateGroupRequest) Reset() {
+	*x = UpdateGroupRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateGroupRequest) ProtoMessage() {}
+
+func (x *UpdateGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateGroupRequest.ProtoReflect.Descriptor instead.
+func (*UpdateGroupRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *UpdateGroupRequest) GetSessionUuid() string {
+	if x != nil {
+		return x.SessionUuid
+	}
+	return ""
+}
+
+func (x *UpdateGroupRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateGroupRequest) GetAvatar() string {
+	if x != nil {
+		return x.Avatar
+	}
+	return ""
+}
+
+type UpdateGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       *Message               `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"` // 群资料变更系统消息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateGroupResponse) Reset() {
+	*x = UpdateGroupResponse{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateGroupResponse) ProtoMessage() {}
+
+func (x *UpdateGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateGroupResponse.ProtoReflect.Descriptor instead.
+func (*UpdateGroupResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *UpdateGroupResponse) GetMessage() *Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+// 邀请用户加入群聊，所有成员可用
+type AddGroupMembersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionUuid   string                 `protobuf:"bytes,1,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"` // 会话UUID
+	UserUuids     []string               `protobuf:"bytes,2,rep,name=user_uuids,json=userUuids,proto3" json:"user_uuids,omitempty"`       // 被邀请的用户UUID列表
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddGroupMembersRequest) Reset() {
+	*x = AddGroupMembersRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddGroupMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddGroupMembersRequest) ProtoMessage() {}
+
+func (x *AddGroupMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddGroupMembersRequest.ProtoReflect.Descriptor instead.
+func (*AddGroupMembersRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *AddGroupMembersRequest) GetSessionUuid() string {
+	if x != nil {
+		return x.SessionUuid
+	}
+	return ""
+}
+
+func (x *AddGroupMembersRequest) GetUserUuids() []string {
+	if x != nil {
+		return x.UserUuids
+	}
+	return nil
+}
+
+type AddGroupMembersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JoinedUuids   []string               `protobuf:"bytes,1,rep,name=joined_uuids,json=joinedUuids,proto3" json:"joined_uuids,omitempty"` // 实际加入的用户UUID列表，已是成员的会被忽略
+	MemberUuids   []string               `protobuf:"bytes,2,rep,name=member_uuids,json=memberUuids,proto3" json:"member_uuids,omitempty"` // 当前成员UUID列表
+	Message       *Message               `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`                            // 成员变更系统消息，没有用户加入时为空
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddGroupMembersResponse) Reset() {
+	*x = AddGroupMembersResponse{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddGroupMembersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddGroupMembersResponse) ProtoMessage() {}
+
+func (x *AddGroupMembersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddGroupMembersResponse.ProtoReflect.Descriptor instead.
+func (*AddGroupMembersResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *AddGroupMembersResponse) GetJoinedUuids() []string {
+	if x != nil {
+		return x.JoinedUuids
+	}
+	return nil
+}
+
+func (x *AddGroupMembersResponse) GetMemberUuids() []string {
+	if x != nil {
+		return x.MemberUuids
+	}
+	return nil
+}
+
+func (x *AddGroupMembersResponse) GetMessage() *Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+// 移除成员，群主可移除管理员和普通成员，管理员只能移除普通成员
+type RemoveGroupMemberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionUuid   string                 `protobuf:"bytes,1,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"` // 会话UUID
+	UserUuid      string                 `protobuf:"bytes,2,opt,name=user_uuid,json=userUuid,proto3" json:"user_uuid,omitempty"`          // 被移除的用户UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveGroupMemberRequest) Reset() {
+	*x = RemoveGroupMemberRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveGroupMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveGroupMemberRequest) ProtoMessage() {}
+
+func (x *RemoveGroupMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveGroupMemberRequest.ProtoReflect.Descriptor instead.
+func (*RemoveGroupMemberRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RemoveGroupMemberRequest) GetSessionUuid() string {
+	if x != nil {
+		return x.SessionUuid
+	}
+	return ""
+}
+
+func (x *RemoveGroupMemberRequest) GetUserUuid() string {
+	if x != nil {
+		return x.UserUuid
+	}
+	return ""
+}
+
+type RemoveGroupMemberResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MemberUuids   []string               `protobuf:"bytes,1,rep,name=member_uuids,json=memberUuids,proto3" json:"member_uuids,omitempty"` // 当前成员UUID列表
+	Message       *Message               `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`                            // 成员变更系统消息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveGroupMemberResponse) Reset() {
+	*x = RemoveGroupMemberResponse{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveGroupMemberResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveGroupMemberResponse) ProtoMessage() {}
+
+func (x *RemoveGroupMemberResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveGroupMemberResponse.ProtoReflect.Descriptor instead.
+func (*RemoveGroupMemberResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *RemoveGroupMemberResponse) GetMemberUuids() []string {
+	if x != nil {
+		return x.MemberUuids
+	}
+	return nil
+}
+
+func (x *RemoveGroupMemberResponse) GetMessage() *Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+// 设置或取消管理员，仅群主可用
+type SetGroupAdminRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionUuid   string                 `protobuf:"bytes,1,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"` // 会话UUID
+	UserUuid      string                 `protobuf:"bytes,2,opt,name=user_uuid,json=userUuid,proto3" json:"user_uuid,omitempty"`          // 目标用户UUID
+	Admin         bool                   `protobuf:"varint,3,opt,name=admin,proto3" json:"admin,omitempty"`                               // true: 设为管理员 false: 取消管理员
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetGroupAdminRequest) Reset() {
+	*x = SetGroupAdminRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetGroupAdminRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetGroupAdminRequest) ProtoMessage() {}
+
+func (x *SetGroupAdminRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetGroupAdminRequest.ProtoReflect.Descriptor instead.
+func (*SetGroupAdminRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *SetGroupAdminRequest) GetSessionUuid() string {
+	if x != nil {
+		return x.SessionUuid
+	}
+	return ""
+}
+
+func (x *SetGroupAdminRequest) GetUserUuid() string {
+	if x != nil {
+		return x.UserUuid
+	}
+	return ""
+}
+
+func (x *SetGroupAdminRequest) GetAdmin() bool {
+	if x != nil {
+		return x.Admin
+	}
+	return false
+}
+
+type SetGroupAdminResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       *Message               `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"` // 成员变更系统消息，角色未变化时为空
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetGroupAdminResponse) Reset() {
+	*x = SetGroupAdminResponse{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetGroupAdminResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetGroupAdminResponse) ProtoMessage() {}
+
+func (x *SetGroupAdminResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetGroupAdminResponse.ProtoReflect.Descriptor instead.
+func (*SetGroupAdminResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *SetGroupAdminResponse) GetMessage() *Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+// 退出群聊，群主退出时转让给最早加入的管理员，没有管理员时转让给最早加入的成员
+type LeaveGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionUuid   string                 `protobuf:"bytes,1,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"` // 会话UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaveGroupRequest) Reset() {
+	*x = LeaveGroupRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveGroupRequest) ProtoMessage() {}
+
+func (x *LeaveGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveGroupRequest.ProtoReflect.Descriptor instead.
+func (*LeaveGroupRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *LeaveGroupRequest) GetSessionUuid() string {
+	if x != nil {
+		return x.SessionUuid
+	}
+	return ""
+}
+
+type LeaveGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MemberUuids   []string               `protobuf:"bytes,1,rep,name=member_uuids,json=memberUuids,proto3" json:"member_uuids,omitempty"` // 当前成员UUID列表
+	Message       *Message               `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`                            // 成员变更系统消息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaveGroupResponse) Reset() {
+	*x = LeaveGroupResponse{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveGroupResponse) ProtoMessage() {}
+
+func (x *LeaveGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveGroupResponse.ProtoReflect.Descriptor instead.
+func (*LeaveGroupResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *LeaveGroupResponse) GetMemberUuids() []string {
+	if x != nil {
+		return x.MemberUuids
+	}
+	return nil
+}
+
+func (x *LeaveGroupResponse) GetMessage() *Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+// 解散群聊，仅群主可用
+type DissolveGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionUuid   string                 `protobuf:"bytes,1,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"` // 会话UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DissolveGroupRequest) Reset() {
+	*x = DissolveGroupRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DissolveGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DissolveGroupRequest) ProtoMessage() {}
+
+func (x *DissolveGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DissolveGroupRequest.ProtoReflect.Descriptor instead.
+func (*DissolveGroupRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *DissolveGroupRequest) GetSessionUuid() string {
+	if x != nil {
+		return x.SessionUuid
+	}
+	return ""
+}
+
+type DissolveGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MemberUuids   []string               `protobuf:"bytes,1,rep,name=member_uuids,json=memberUuids,proto3" json:"member_uuids,omitempty"` // 解散前的成员UUID列表
+	Message       *Message               `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`                            // 解散系统消息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DissolveGroupResponse) Reset() {
+	*x = DissolveGroupResponse{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DissolveGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DissolveGroupResponse) ProtoMessage() {}
+
+func (x *DissolveGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DissolveGroupResponse.ProtoReflect.Descriptor instead.
+func (*DissolveGroupResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *DissolveGroupResponse) GetMemberUuids() []string {
+	if x != nil {
+		return x.MemberUuids
+	}
+	return nil
+}
+
+func (x *DissolveGroupResponse) GetMessage() *Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+type ListGroupMembersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionUuid   string                 `protobuf:"bytes,1,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"` // 会话UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGroupMembersRequest) Reset() {
+	*x = ListGroupMembersRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGroupMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGroupMembersRequest) ProtoMessage() {}
+
+func (x *ListGroupMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGroupMembersRequest.ProtoReflect.Descriptor instead.
+func (*ListGroupMembersRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ListGroupMembersRequest) GetSessionUuid() string {
+	if x != nil {
+		return x.SessionUuid
+	}
+	return ""
+}
+
+type ListGroupMembersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Members       []*GroupMember         `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"` // 成员列表，按加入顺序排列
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGroupMembersResponse) Reset() {
+	*x = ListGroupMembersResponse{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGroupMembersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGroupMembersResponse) ProtoMessage() {}
+
+func (x *ListGroupMembersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGroupMembersResponse.ProtoReflect.Descriptor instead.
+func (*ListGroupMembersResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ListGroupMembersResponse) GetMembers() []*GroupMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type GroupMember struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserUuid      string                 `protobuf:"bytes,1,opt,name=user_uuid,json=userUuid,proto3" json:"user_uuid,omitempty"`       // 用户UUID
+	UserName      string                 `protobuf:"bytes,2,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`       // 用户名称
+	UserAvatar    string                 `protobuf:"bytes,3,opt,name=user_avatar,json=userAvatar,proto3" json:"user_avatar,omitempty"` // 用户头像
+	Role          int64                  `protobuf:"varint,4,opt,name=role,proto3" json:"role,omitempty"`                              // 成员角色
+	JoinTime      string                 `protobuf:"bytes,5,opt,name=join_time,json=joinTime,proto3" json:"join_time,omitempty"`       // 加入时间
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupMember) Reset() {
+	*x = GroupMember{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupMember) ProtoMessage() {}
+
+func (x *GroupMember) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupMember.ProtoReflect.Descriptor instead.
+func (*GroupMember) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *GroupMember) GetUserUuid() string {
+	if x != nil {
+		return x.UserUuid
+	}
+	return ""
+}
+
+func (x *GroupMember) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *GroupMember) GetUserAvatar() string {
+	if x != nil {
+		return x.UserAvatar
+	}
+	return ""
+}
+
+func (x *GroupMember) GetRole() int64 {
+	if x != nil {
+		return x.Role
+	}
+	return 0
+}
+
+func (x *GroupMember) GetJoinTime() string {
+	if x != nil {
+		return x.JoinTime
+	}
+	return ""
+}
+
+// 按用户名前缀或手机号/邮箱/账号精确搜索用户
+type SearchUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keyword       string                 `protobuf:"bytes,1,opt,name=keyword,proto3" json:"keyword,omitempty"` // 搜索关键字
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchUsersRequest) Reset() {
+	*x = SearchUsersRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchUsersRequest) ProtoMessage() {}
+
+func (x *SearchUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchUsersRequest.ProtoReflect.Descriptor instead.
+func (*SearchUsersRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *SearchUsersRequest) GetKeyword() string {
+	if x != nil {
+		return x.Keyword
+	}
+	return ""
+}
+
+type SearchUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*UserSummary         `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"` // 用户列表，不包含自己
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchUsersResponse) Reset() {
+	*x = SearchUsersResponse{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchUsersResponse) ProtoMessage() {}
+
+func (x *SearchUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchUsersResponse.ProtoReflect.Descriptor instead.
+func (*SearchUsersResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *SearchUsersResponse) GetUsers() []*UserSummary {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+type UserSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserUuid      string                 `protobuf:"bytes,1,opt,name=user_uuid,json=userUuid,proto3" json:"user_uuid,omitempty"`       // 用户UUID
+	UserName      string                 `protobuf:"bytes,2,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`       // 用户名称
+	UserAvatar    string                 `protobuf:"bytes,3,opt,name=user_avatar,json=userAvatar,proto3" json:"user_avatar,omitempty"` // 用户头像
+	IsContact     bool                   `protobuf:"varint,4,opt,name=is_contact,json=isContact,proto3" json:"is_contact,omitempty"`   // 是否已是联系人
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserSummary) Reset() {
+	*x = UserSummary{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserSummary) ProtoMessage() {}
+
+func (x *UserSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserSummary.ProtoReflect.Descriptor instead.
+func (*UserSummary) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *UserSummary) GetUserUuid() string {
+	if x != nil {
+		return x.UserUuid
+	}
+	return ""
+}
+
+func (x *UserSummary) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *UserSummary) GetUserAvatar() string {
+	if x != nil {
+		return x.UserAvatar
+	}
+	return ""
+}
+
+func (x *UserSummary) GetIsContact() bool {
+	if x != nil {
+		return x.IsContact
+	}
+	return false
+}
+
+// 发送好友申请，对方已向自己发送申请时直接成为联系人
+type SendFriendRequestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ToUuid        string                 `protobuf:"bytes,1,opt,name=to_uuid,json=toUuid,proto3" json:"to_uuid,omitempty"` // 接收者UUID
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`             // 验证消息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendFriendRequestRequest) Reset() {
+	*x = SendFriendRequestRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendFriendRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendFriendRequestRequest) ProtoMessage() {}
+
+func (x *SendFriendRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendFriendRequestRequest.ProtoReflect.Descriptor instead.
+func (*SendFriendRequestRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *SendFriendRequestRequest) GetToUuid() string {
+	if x != nil {
+		return x.ToUuid
+	}
+	return ""
+}
+
+func (x *SendFriendRequestRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type SendFriendRequestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestUuid   string                 `protobuf:"bytes,1,opt,name=request_uuid,json=requestUuid,proto3" json:"request_uuid,omitempty"` // 申请UUID，已有待处理的申请时返回该申请
+	Accepted      bool                   `protobuf:"varint,2,opt,name=accepted,proto3" json:"accepted,omitempty"`                         // 是否已成为联系人
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendFriendRequestResponse) Reset() {
+	*x = SendFriendRequestResponse{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendFriendRequestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendFriendRequestResponse) ProtoMessage() {}
+
+func (x *SendFriendRequestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendFriendRequestResponse.ProtoReflect.Descriptor instead.
+func (*SendFriendRequestResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *SendFriendRequestResponse) GetRequestUuid() string {
+	if x != nil {
+		return x.RequestUuid
+	}
+	return ""
+}
+
+func (x *SendFriendRequestResponse) GetAccepted() bool {
+	if x != nil {
+		return x.Accepted
+	}
+	return false
+}
+
+type ListFriendRequestsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFriendRequestsRequest) Reset() {
+	*x = ListFriendRequestsRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFriendRequestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFriendRequestsRequest) ProtoMessage() {}
+
+func (x *ListFriendRequestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFriendRequestsRequest.ProtoReflect.Descriptor instead.
+func (*ListFriendRequestsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{45}
+}
+
+type ListFriendRequestsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Requests      []*FriendRequest       `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"` // 最近发出和收到的好友申请，按时间倒序
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFriendRequestsResponse) Reset() {
+	*x = ListFriendRequestsResponse{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFriendRequestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFriendRequestsResponse) ProtoMessage() {}
+
+func (x *ListFriendRequestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFriendRequestsResponse.ProtoReflect.Descriptor instead.
+func (*ListFriendRequestsResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ListFriendRequestsResponse) GetRequests() []*FriendRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+type FriendRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestUuid   string                 `protobuf:"bytes,1,opt,name=request_uuid,json=requestUuid,proto3" json:"request_uuid,omitempty"` // 申请UUID
+	FromUuid      string                 `protobuf:"bytes,2,opt,name=from_uuid,json=fromUuid,proto3" json:"from_uuid,omitempty"`          // 申请者UUID
+	FromName      string                 `protobuf:"bytes,3,opt,name=from_name,json=fromName,proto3" json:"from_name,omitempty"`          // 申请者名称
+	FromAvatar    string                 `protobuf:"bytes,4,opt,name=from_avatar,json=fromAvatar,proto3" json:"from_avatar,omitempty"`    // 申请者头像
+	ToUuid        string                 `protobuf:"bytes,5,opt,name=to_uuid,json=toUuid,proto3" json:"to_uuid,omitempty"`                // 接收者UUID
+	ToName        string                 `protobuf:"bytes,6,opt,name=to_name,json=toName,proto3" json:"to_name,omitempty"`                // 接收者名称
+	ToAvatar      string                 `protobuf:"bytes,7,opt,name=to_avatar,json=toAvatar,proto3" json:"to_avatar,omitempty"`          // 接收者头像
+	Message       string                 `protobuf:"bytes,8,opt,name=message,proto3" json:"message,omitempty"`                            // 验证消息
+	Status        int64                  `protobuf:"varint,9,opt,name=status,proto3" json:"status,omitempty"`                             // 状态 1: 待处理 2: 已同意 3: 已拒绝
+	CreateTime    string                 `protobuf:"bytes,10,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`   // 申请时间
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FriendRequest) Reset() {
+	*x = FriendRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FriendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FriendRequest) ProtoMessage() {}
+
+func (x *FriendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FriendRequest.ProtoReflect.Descriptor instead.
+func (*FriendRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *FriendRequest) GetRequestUuid() string {
+	if x != nil {
+		return x.RequestUuid
+	}
+	return ""
+}
+
+func (x *FriendRequest) GetFromUuid() string {
+	if x != nil {
+		return x.FromUuid
+	}
+	return ""
+}
+
+func (x *FriendRequest) GetFromName() string {
+	if x != nil {
+		return x.FromName
+	}
+	return ""
+}
+
+func (x *FriendRequest) GetFromAvatar() string {
+	if x != nil {
+		return x.FromAvatar
+	}
+	return ""
+}
+
+func (x *FriendRequest) GetToUuid() string {
+	if x != nil {
+		return x.ToUuid
+	}
+	return ""
+}
+
+func (x *FriendRequest) GetToName() string {
+	if x != nil {
+		return x.ToName
+	}
+	return ""
+}
+
+func (x *FriendRequest) GetToAvatar() string {
+	if x != nil {
+		return x.ToAvatar
+	}
+	return ""
+}
+
+func (x *FriendRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *FriendRequest) GetStatus() int64 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *FriendRequest) GetCreateTime() string {
+	if x != nil {
+		return x.CreateTime
+	}
+	return ""
+}
+
+// 同意好友申请，仅接收者可用
+type AcceptFriendRequestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestUuid   string                 `protobuf:"bytes,1,opt,name=request_uuid,json=requestUuid,proto3" json:"request_uuid,omitempty"` // 申请UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcceptFriendRequestRequest) Reset() {
+	*x = AcceptFriendRequestRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptFriendRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptFriendRequestRequest) ProtoMessage() {}
+
+func (x *AcceptFriendRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptFriendRequestRequest.ProtoReflect.Descriptor instead.
+func (*AcceptFriendRequestRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *AcceptFriendRequestRequest) GetRequestUuid() string {
+	if x != nil {
+		return x.RequestUuid
+	}
+	return ""
+}
+
+type AcceptFriendRequestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContactUuid   string                 `protobuf:"bytes,1,opt,name=contact_uuid,json=contactUuid,proto3" json:"contact_uuid,omitempty"` // 新联系人UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcceptFriendRequestResponse) Reset() {
+	*x = AcceptFriendRequestResponse{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptFriendRequestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptFriendRequestResponse) ProtoMessage() {}
+
+func (x *AcceptFriendRequestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptFriendRequestResponse.ProtoReflect.Descriptor instead.
+func (*AcceptFriendRequestResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *AcceptFriendRequestResponse) GetContactUuid() string {
+	if x != nil {
+		return x.ContactUuid
+	}
+	return ""
+}
+
+// 拒绝好友申请，仅接收者可用
+type RejectFriendRequestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestUuid   string                 `protobuf:"bytes,1,opt,name=request_uuid,json=requestUuid,proto3" json:"request_uuid,omitempty"` // 申请UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectFriendRequestRequest) Reset() {
+	*x = RejectFriendRequestRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectFriendRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectFriendRequestRequest) ProtoMessage() {}
+
+func (x *RejectFriendRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectFriendRequestRequest.ProtoReflect.Descriptor instead.
+func (*RejectFriendRequestRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *RejectFriendRequestRequest) GetRequestUuid() string {
+	if x != nil {
+		return x.RequestUuid
+	}
+	return ""
+}
+
+type RejectFriendRequestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectFriendRequestResponse) Reset() {
+	*x = RejectFriendRequestResponse{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectFriendRequestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectFriendRequestResponse) ProtoMessage() {}
+
+func (x *RejectFriendRequestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectFriendRequestResponse.ProtoReflect.Descriptor instead.
+func (*RejectFriendRequestResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{51}
+}
+
+type ListContactsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListContactsRequest) Reset() {
+	*x = ListContactsRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListContactsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListContactsRequest) ProtoMessage() {}
+
+func (x *ListContactsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListContactsRequest.ProtoReflect.Descriptor instead.
+func (*ListContactsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{52}
+}
+
+type ListContactsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Contacts      []*Contact             `protobuf:"bytes,1,rep,name=contacts,proto3" json:"contacts,omitempty"` // 联系人列表，按添加顺序排列
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListContactsResponse) Reset() {
+	*x = ListContactsResponse{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListContactsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListContactsResponse) ProtoMessage() {}
+
+func (x *ListContactsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListContactsResponse.ProtoReflect.Descriptor instead.
+func (*ListContactsResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ListContactsResponse) GetContacts() []*Contact {
+	if x != nil {
+		return x.Contacts
+	}
+	return nil
+}
+
+type Contact struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserUuid      string                 `protobuf:"bytes,1,opt,name=user_uuid,json=userUuid,proto3" json:"user_uuid,omitempty"`          // 联系人UUID
+	UserName      string                 `protobuf:"bytes,2,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`          // 联系人名称
+	UserAvatar    string                 `protobuf:"bytes,3,opt,name=user_avatar,json=userAvatar,proto3" json:"user_avatar,omitempty"`    // 联系人头像
+	Remark        string                 `protobuf:"bytes,4,opt,name=remark,proto3" json:"remark,omitempty"`                              // 备注
+	SessionUuid   string                 `protobuf:"bytes,5,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"` // 单聊会话UUID，还未聊天时为空
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Contact) Reset() {
+	*x = Contact{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Contact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Contact) ProtoMessage() {}
+
+func (x *Contact) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Contact.ProtoReflect.Descriptor instead.
+func (*Contact) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *Contact) GetUserUuid() string {
+	if x != nil {
+		return x.UserUuid
+	}
+	return ""
+}
+
+func (x *Contact) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *Contact) GetUserAvatar() string {
+	if x != nil {
+		return x.UserAvatar
+	}
+	return ""
+}
+
+func (x *Contact) GetRemark() string {
+	if x != nil {
+		return x.Remark
+	}
+	return ""
+}
+
+func (x *Contact) GetSessionUuid() string {
+	if x != nil {
+		return x.SessionUuid
+	}
+	return ""
+}
+
+// 删除联系人，双方互相删除，已有的单聊会话保留
+type RemoveContactRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContactUuid   string                 `protobuf:"bytes,1,opt,name=contact_uuid,json=contactUuid,proto3" json:"contact_uuid,omitempty"` // 联系人UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveContactRequest) Reset() {
+	*x = RemoveContactRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveContactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveContactRequest) ProtoMessage() {}
+
+func (x *RemoveContactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveContactRequest.ProtoReflect.Descriptor instead.
+func (*RemoveContactRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *RemoveContactRequest) GetContactUuid() string {
+	if x != nil {
+		return x.ContactUuid
+	}
+	return ""
+}
+
+type RemoveContactResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveContactResponse) Reset() {
+	*x = RemoveContactResponse{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveContactResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveContactResponse) ProtoMessage() {}
+
+func (x *RemoveContactResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveContactResponse.ProtoReflect.Descriptor instead.
+func (*RemoveContactResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{56}
+}
+
+type SetContactRemarkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContactUuid   string                 `protobuf:"bytes,1,opt,name=contact_uuid,json=contactUuid,proto3" json:"contact_uuid,omitempty"` // 联系人UUID
+	Remark        string                 `protobuf:"bytes,2,opt,name=remark,proto3" json:"remark,omitempty"`                              // 备注，为空时清除
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetContactRemarkRequest) Reset() {
+	*x = SetContactRemarkRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetContactRemarkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetContactRemarkRequest) ProtoMessage() {}
+
+func (x *SetContactRemarkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetContactRemarkRequest.ProtoReflect.Descriptor instead.
+func (*SetContactRemarkRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *SetContactRemarkRequest) GetContactUuid() string {
+	if x != nil {
+		return x.ContactUuid
+	}
+	return ""
+}
+
+func (x *SetContactRemarkRequest) GetRemark() string {
+	if x != nil {
+		return x.Remark
+	}
+	return ""
+}
+
+type SetContactRemarkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetContactRemarkResponse) Reset() {
+	*x = SetContactRemarkResponse{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetContactRemarkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetContactRemarkResponse) ProtoMessage() {}
+
+func (x *SetContactRemarkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetContactRemarkResponse.ProtoReflect.Descriptor instead.
+func (*SetContactRemarkResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{58}
+}
+
+// 打开与联系人的单聊会话，首次聊天时创建
+type OpenSingleSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContactUuid   string                 `protobuf:"bytes,1,opt,name=contact_uuid,json=contactUuid,proto3" json:"contact_uuid,omitempty"` // 联系人UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpenSingleSessionRequest) Reset() {
+	*x = OpenSingleSessionRequest{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenSingleSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenSingleSessionRequest) ProtoMessage() {}
+
+func (x *OpenSingleSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenSingleSessionRequest.ProtoReflect.Descriptor instead.
+func (*OpenSingleSessionRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *OpenSingleSessionRequest) GetContactUuid() string {
+	if x != nil {
+		return x.ContactUuid
+	}
+	return ""
+}
+
+type OpenSingleSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionUuid   string                 `protobuf:"bytes,1,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"` // 会话UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpenSingleSessionResponse) Reset() {
+	*x = OpenSingleSessionResponse{}
+	mi := &file_rpc_service_apigateway_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenSingleSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenSingleSessionResponse) ProtoMessage() {}
+
+func (x *OpenSingleSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_apigateway_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenSingleSessionResponse.ProtoReflect.Descriptor instead.
+func (*OpenSingleSessionResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_apigateway_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *OpenSingleSessionResponse) GetSessionUuid() string {
+	if x != nil {
+		return x.SessionUuid
+	}
+	return ""
+}
+
+var File_rpc_service_apigateway_proto protoreflect.FileDescriptor
+
+const file_rpc_service_apigateway_proto_rawDesc = "" +
+	"\n" +
+	"\x1crpc/service/apigateway.proto\x12\n" +
+	"apigateway\"x\n" +
+	"\x15HistoryMessageRequest\x12!\n" +
+	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuid\x12\x1f\n" +
+	"\vstart_seqid\x18\x02 \x01(\x03R\n" +
+	"startSeqid\x12\x1b\n" +
+	"\tend_seqid\x18\x03 \x01(\x03R\bendSeqid\"I\n" +
+	"\x16HistoryMessageResponse\x12/\n" +
+	"\bmessages\x18\x01 \x03(\v2\x13.apigateway.MessageR\bmessages\">\n" +
+	"\x19GetSessionUserListRequest\x12!\n" +
+	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuid\"S\n" +
+	"\x1aGetSessionUserListResponse\x125\n" +
+	"\x05users\x18\x01 \x03(\v2\x1f.apigateway.SessionUserListItemR\x05users\"p\n" +
+	"\x13SessionUserListItem\x12\x1b\n" +
+	"\tuser_uuid\x18\x01 \x01(\tR\buserUuid\x12\x1b\n" +
+	"\tuser_name\x18\x02 \x01(\tR\buserName\x12\x1f\n" +
+	"\vuser_avatar\x18\x03 \x01(\tR\n" +
+	"userAvatar\"\xa7\x02\n" +
+	"\aMessage\x12!\n" +
+	"\fmessage_uuid\x18\x01 \x01(\tR\vmessageUuid\x12!\n" +
+	"\fsession_uuid\x18\x02 \x01(\tR\vsessionUuid\x12\x15\n" +
+	"\x06seq_id\x18\x03 \x01(\x03R\x05seqId\x12!\n" +
+	"\fmessage_type\x18\x04 \x01(\x03R\vmessageType\x12\x18\n" +
+	"\acontent\x18\x05 \x01(\tR\acontent\x12\x1f\n" +
+	"\vsender_uuid\x18\x06 \x01(\tR\n" +
+	"senderUuid\x12\x1f\n" +
+	"\vsender_name\x18\a \x01(\tR\n" +
+	"senderName\x12#\n" +
+	"\rsender_avatar\x18\b \x01(\tR\fsenderAvatar\x12\x1b\n" +
+	"\tsend_time\x18\t \x01(\tR\bsendTime\"\x14\n" +
+	"\x12SessionListRequest\"F\n" +
+	"\x13SessionListResponse\x12/\n" +
+	"\bsessions\x18\x01 \x03(\v2\x13.apigateway.SessionR\bsessions\"\xac\x01\n" +
+	"\aSession\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06avatar\x18\x03 \x01(\tR\x06avatar\x12!\n" +
+	"\flast_message\x18\x04 \x01(\tR\vlastMessage\x12\x1b\n" +
+	"\tlast_time\x18\x05 \x01(\tR\blastTime\x12!\n" +
+	"\funread_count\x18\x06 \x01(\x03R\vunreadCount\"s\n" +
+	"\fLoginRequest\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x12\x1e\n" +
+	"\n" +
+	"credential\x18\x02 \x01(\tR\n" +
+	"credential\x12#\n" +
+	"\ridentity_type\x18\x03 \x01(\x03R\fidentityType\"J\n" +
+	"\rLoginResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\"v\n" +
+	"\x0fRegisterRequest\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x12\x1e\n" +
+	"\n" +
+	"credential\x18\x02 \x01(\tR\n" +
+	"credential\x12#\n" +
+	"\ridentity_type\x18\x03 \x01(\x03R\fidentityType\"M\n" +
+	"\x10RegisterResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\"\xdd\x01\n" +
+	"\x12SendMessageRequest\x12!\n" +
+	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuid\x12\x18\n" +
+	"\apayload\x18\x02 \x01(\tR\apayload\x12\x1f\n" +
+	"\vsender_uuid\x18\x03 \x01(\tR\n" +
+	"senderUuid\x12!\n" +
+	"\fmessage_type\x18\x04 \x01(\x03R\vmessageType\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\x03R\ttimestamp\x12\"\n" +
+	"\rclient_msg_id\x18\a \x01(\tR\vclientMsgIdJ\x04\b\x05\x10\x06\"m\n" +
+	"\x13SendMessageResponse\x12!\n" +
+	"\fmessage_uuid\x18\x01 \x01(\tR\vmessageUuid\x12\x15\n" +
+	"\x06seq_id\x18\x02 \x01(\x03R\x05seqId\x12\x1c\n" +
+	"\tduplicate\x18\x03 \x01(\bR\tduplicate\"\x14\n" +
+	"\x12GetUserInfoRequest\"\x83\x01\n" +
+	"\x13GetUserInfoResponse\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06avatar\x18\x03 \x01(\tR\x06avatar\x12\x14\n" +
+	"\x05email\x18\x04 \x01(\tR\x05email\x12\x16\n" +
+	"\x06mobile\x18\x05 \x01(\tR\x06mobile\"S\n" +
+	"\x14CreateSessionRequest\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12!\n" +
+	"\fmember_uuids\x18\x03 \x03(\tR\vmemberUuidsJ\x04\b\x01\x10\x02\"\x8c\x01\n" +
+	"\x15CreateSessionResponse\x12!\n" +
+	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuid\x12!\n" +
+	"\fmember_uuids\x18\x02 \x03(\tR\vmemberUuids\x12-\n" +
+	"\amessage\x18\x03 \x01(\v2\x13.apigateway.MessageR\amessage\"\\\n" +
+	"\x12JoinSessionRequest\x12!\n" +
+	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuid\x12\x1d\n" +
+	"\n" +
+	"user_uuids\x18\x03 \x03(\tR\tuserUuidsJ\x04\b\x02\x10\x03\"\x8a\x01\n" +
+	"\x13JoinSessionResponse\x12!\n" +
+	"\fjoined_uuids\x18\x01 \x03(\tR\vjoinedUuids\x12!\n" +
+	"\fmember_uuids\x18\x02 \x03(\tR\vmemberUuids\x12-\n" +
+	"\amessage\x18\x03 \x01(\v2\x13.apigateway.MessageR\amessage\">\n" +
+	"\x13LeaveSessionRequest\x12!\n" +
+	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuidJ\x04\b\x02\x10\x03\"h\n" +
+	"\x14LeaveSessionResponse\x12!\n" +
+	"\fmember_uuids\x18\x01 \x03(\tR\vmemberUuids\x12-\n" +
+	"\amessage\x18\x02 \x01(\v2\x13.apigateway.MessageR\amessage\"c\n" +
+	"\x12CreateGroupRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06avatar\x18\x02 \x01(\tR\x06avatar\x12!\n" +
+	"\fmember_uuids\x18\x03 \x03(\tR\vmemberUuids\"\x8a\x01\n" +
+	"\x13CreateGroupResponse\x12!\n" +
+	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuid\x12!\n" +
+	"\fmember_uuids\x18\x02 \x03(\tR\vmemberUuids\x12-\n" +
+	"\amessage\x18\x03 \x01(\v2\x13.apigateway.MessageR\amessage\"c\n" +
+	"\x12UpdateGroupRequest\x12!\n" +
+	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuid\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06avatar\x18\x03 \x01(\tR\x06avatar\"D\n" +
+	"\x13UpdateGroupResponse\x12-\n" +
+	"\amessage\x18\x01 \x01(\v2\x13.apigateway.MessageR\amessage\"Z\n" +
+	"\x16AddGroupMembersRequest\x12!\n" +
+	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuid\x12\x1d\n" +
+	"\n" +
+	"user_uuids\x18\x02 \x03(\tR\tuserUuids\"\x8e\x01\n" +
+	"\x17AddGroupMembersResponse\x12!\n" +
+	"\fjoined_uuids\x18\x01 \x03(\tR\vjoinedUuids\x12!\n" +
+	"\fmember_uuids\x18\x02 \x03(\tR\vmemberUuids\x12-\n" +
+	"\amessage\x18\x03 \x01(\v2\x13.apigateway.MessageR\amessage\"Z\n" +
+	"\x18RemoveGroupMemberRequest\x12!\n" +
+	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuid\x12\x1b\n" +
+	"\tuser_uuid\x18\x02 \x01(\tR\buserUuid\"m\n" +
+	"\x19RemoveGroupMemberResponse\x12!\n" +
+	"\fmember_uuids\x18\x01 \x03(\tR\vmemberUuids\x12-\n" +
+	"\amessage\x18\x02 \x01(\v2\x13.apigateway.MessageR\amessage\"l\n" +
+	"\x14SetGroupAdminRequest\x12!\n" +
+	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuid\x12\x1b\n" +
+	"\tuser_uuid\x18\x02 \x01(\tR\buserUuid\x12\x14\n" +
+	"\x05admin\x18\x03 \x01(\bR\x05admin\"F\n" +
+	"\x15SetGroupAdminResponse\x12-\n" +
+	"\amessage\x18\x01 \x01(\v2\x13.apigateway.MessageR\amessage\"6\n" +
+	"\x11LeaveGroupRequest\x12!\n" +
+	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuid\"f\n" +
+	"\x12LeaveGroupResponse\x12!\n" +
+	"\fmember_uuids\x18\x01 \x03(\tR\vmemberUuids\x12-\n" +
+	"\amessage\x18\x02 \x01(\v2\x13.apigateway.MessageR\amessage\"9\n" +
+	"\x14DissolveGroupRequest\x12!\n" +
+	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuid\"i\n" +
+	"\x15DissolveGroupResponse\x12!\n" +
+	"\fmember_uuids\x18\x01 \x03(\tR\vmemberUuids\x12-\n" +
+	"\amessage\x18\x02 \x01(\v2\x13.apigateway.MessageR\amessage\"<\n" +
+	"\x17ListGroupMembersRequest\x12!\n" +
+	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuid\"M\n" +
+	"\x18ListGroupMembersResponse\x121\n" +
+	"\amembers\x18\x01 \x03(\v2\x17.apigateway.GroupMemberR\amembers\"\x99\x01\n" +
+	"\vGroupMember\x12\x1b\n" +
+	"\tuser_uuid\x18\x01 \x01(\tR\buserUuid\x12\x1b\n" +
+	"\tuser_name\x18\x02 \x01(\tR\buserName\x12\x1f\n" +
+	"\vuser_avatar\x18\x03 \x01(\tR\n" +
+	"userAvatar\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\x03R\x04role\x12\x1b\n" +
+	"\tjoin_time\x18\x05 \x01(\tR\bjoinTime\".\n" +
+	"\x12SearchUsersRequest\x12\x18\n" +
+	"\akeyword\x18\x01 \x01(\tR\akeyword\"D\n" +
+	"\x13SearchUsersResponse\x12-\n" +
+	"\x05users\x18\x01 \x03(\v2\x17.apigateway.UserSummaryR\x05users\"\x87\x01\n" +
+	"\vUserSummary\x12\x1b\n" +
+	"\tuser_uuid\x18\x01 \x01(\tR\buserUuid\x12\x1b\n" +
+	"\tuser_name\x18\x02 \x01(\tR\buserName\x12\x1f\n" +
+	"\vuser_avatar\x18\x03 \x01(\tR\n" +
+	"userAvatar\x12\x1d\n" +
+	"\n" +
+	"is_contact\x18\x04 \x01(\bR\tisContact\"M\n" +
+	"\x18SendFriendRequestRequest\x12\x17\n" +
+	"\ato_uuid\x18\x01 \x01(\tR\x06toUuid\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"Z\n" +
+	"\x19SendFriendRequestResponse\x12!\n" +
+	"\frequest_uuid\x18\x01 \x01(\tR\vrequestUuid\x12\x1a\n" +
+	"\baccepted\x18\x02 \x01(\bR\baccepted\"\x1b\n" +
+	"\x19ListFriendRequestsRequest\"S\n" +
+	"\x1aListFriendRequestsResponse\x125\n" +
+	"\brequests\x18\x01 \x03(\v2\x19.apigateway.FriendRequestR\brequests\"\xaf\x02\n" +
+	"\rFriendRequest\x12!\n" +
+	"\frequest_uuid\x18\x01 \x01(\tR\vrequestUuid\x12\x1b\n" +
+	"\tfrom_uuid\x18\x02 \x01(\tR\bfromUuid\x12\x1b\n" +
+	"\tfrom_name\x18\x03 \x01(\tR\bfromName\x12\x1f\n" +
+	"\vfrom_avatar\x18\x04 \x01(\tR\n" +
+	"fromAvatar\x12\x17\n" +
+	"\ato_uuid\x18\x05 \x01(\tR\x06toUuid\x12\x17\n" +
+	"\ato_name\x18\x06 \x01(\tR\x06toName\x12\x1b\n" +
+	"\tto_avatar\x18\a \x01(\tR\btoAvatar\x12\x18\n" +
+	"\amessage\x18\b \x01(\tR\amessage\x12\x16\n" +
+	"\x06status\x18\t \x01(\x03R\x06status\x12\x1f\n" +
+	"\vcreate_time\x18\n" +
+	" \x01(\tR\n" +
+	"createTime\"?\n" +
+	"\x1aAcceptFriendRequestRequest\x12!\n" +
+	"\frequest_uuid\x18\x01 \x01(\tR\vrequestUuid\"@\n" +
+	"\x1bAcceptFriendRequestResponse\x12!\n" +
+	"\fcontact_uuid\x18\x01 \x01(\tR\vcontactUuid\"?\n" +
+	"\x1aRejectFriendRequestRequest\x12!\n" +
+	"\frequest_uuid\x18\x01 \x01(\tR\vrequestUuid\"\x1d\n" +
+	"\x1bRejectFriendRequestResponse\"\x15\n" +
+	"\x13ListContactsRequest\"G\n" +
+	"\x14ListContactsResponse\x12/\n" +
+	"\bcontacts\x18\x01 \x03(\v2\x13.apigateway.ContactR\bcontacts\"\x9f\x01\n" +
+	"\aContact\x12\x1b\n" +
+	"\tuser_uuid\x18\x01 \x01(\tR\buserUuid\x12\x1b\n" +
+	"\tuser_name\x18\x02 \x01(\tR\buserName\x12\x1f\n" +
+	"\vuser_avatar\x18\x03 \x01(\tR\n" +
+	"userAvatar\x12\x16\n" +
+	"\x06remark\x18\x04 \x01(\tR\x06remark\x12!\n" +
+	"\fsession_uuid\x18\x05 \x01(\tR\vsessionUuid\"9\n" +
+	"\x14RemoveContactRequest\x12!\n" +
+	"\fcontact_uuid\x18\x01 \x01(\tR\vcontactUuid\"\x17\n" +
+	"\x15RemoveContactResponse\"T\n" +
+	"\x17SetContactRemarkRequest\x12!\n" +
+	"\fcontact_uuid\x18\x01 \x01(\tR\vcontactUuid\x12\x16\n" +
+	"\x06remark\x18\x02 \x01(\tR\x06remark\"\x1a\n" +
+	"\x18SetContactRemarkResponse\"=\n" +
+	"\x18OpenSingleSessionRequest\x12!\n" +
+	"\fcontact_uuid\x18\x01 \x01(\tR\vcontactUuid\">\n" +
+	"\x19OpenSingleSessionResponse\x12!\n" +
+	"\fsession_uuid\x18\x01 \x01(\tR\vsessionUuid2\xbf\x12\n" +
+	"\n" +
+	"APIGateway\x12N\n" +
+	"\vSessionList\x12\x1e.apigateway.SessionListRequest\x1a\x1f.apigateway.SessionListResponse\x12c\n" +
+	"\x12GetSessionUserList\x12%.apigateway.GetSessionUserListRequest\x1a&.apigateway.GetSessionUserListResponse\x12W\n" +
+	"\x0eHistoryMessage\x12!.apigateway.HistoryMessageRequest\x1a\".apigateway.HistoryMessageResponse\x12<\n" +
+	"\x05Login\x12\x18.apigateway.LoginRequest\x1a\x19.apigateway.LoginResponse\x12E\n" +
+	"\bRegister\x12\x1b.apigateway.RegisterRequest\x1a\x1c.apigateway.RegisterResponse\x12N\n" +
+	"\vSendMessage\x12\x1e.apigateway.SendMessageRequest\x1a\x1f.apigateway.SendMessageResponse\x12N\n" +
+	"\vGetUserInfo\x12\x1e.apigateway.GetUserInfoRequest\x1a\x1f.apigateway.GetUserInfoResponse\x12T\n" +
+	"\rCreateSession\x12 .apigateway.CreateSessionRequest\x1a!.apigateway.CreateSessionResponse\x12N\n" +
+	"\vJoinSession\x12\x1e.apigateway.JoinSessionRequest\x1a\x1f.apigateway.JoinSessionResponse\x12Q\n" +
+	"\fLeaveSession\x12\x1f.apigateway.LeaveSessionRequest\x1a .apigateway.LeaveSessionResponse\x12N\n" +
+	"\vCreateGroup\x12\x1e.apigateway.CreateGroupRequest\x1a\x1f.apigateway.CreateGroupResponse\x12N\n" +
+	"\vUpdateGroup\x12\x1e.apigateway.UpdateGroupRequest\x1a\x1f.apigateway.UpdateGroupResponse\x12Z\n" +
+	"\x0fAddGroupMembers\x12\".apigateway.AddGroupMembersRequest\x1a#.apigateway.AddGroupMembersResponse\x12`\n" +
+	"\x11RemoveGroupMember\x12$.apigateway.RemoveGroupMemberRequest\x1a%.apigateway.RemoveGroupMemberResponse\x12T\n" +
+	"\rSetGroupAdmin\x12 .apigateway.SetGroupAdminRequest\x1a!.apigateway.SetGroupAdminResponse\x12K\n" +
+	"\n" +
+	"LeaveGroup\x12\x1d.apigateway.LeaveGroupRequest\x1a\x1e.apigateway.LeaveGroupResponse\x12T\n" +
+	"\rDissolveGroup\x12 .apigateway.DissolveGroupRequest\x1a!.apigateway.DissolveGroupResponse\x12]\n" +
+	"\x10ListGroupMembers\x12#.apigateway.ListGroupMembersRequest\x1a$.apigateway.ListGroupMembersResponse\x12N\n" +
+	"\vSearchUsers\x12\x1e.apigateway.SearchUsersRequest\x1a\x1f.apigateway.SearchUsersResponse\x12`\n" +
+	"\x11SendFriendRequest\x12$.apigateway.SendFriendRequestRequest\x1a%.apigateway.SendFriendRequestResponse\x12c\n" +
+	"\x12ListFriendRequests\x12%.apigateway.ListFriendRequestsRequest\x1a&.apigateway.ListFriendRequestsResponse\x12f\n" +
+	"\x13AcceptFriendRequest\x12&.apigateway.AcceptFriendRequestRequest\x1a'.apigateway.AcceptFriendRequestResponse\x12f\n" +
+	"\x13RejectFriendRequest\x12&.apigateway.RejectFriendRequestRequest\x1a'.apigateway.RejectFriendRequestResponse\x12Q\n" +
+	"\fListContacts\x12\x1f.apigateway.ListContactsRequest\x1a .apigateway.ListContactsResponse\x12T\n" +
+	"\rRemoveContact\x12 .apigateway.RemoveContactRequest\x1a!.apigateway.RemoveContactResponse\x12]\n" +
+	"\x10SetContactRemark\x12#.apigateway.SetContactRemarkRequest\x1a$.apigateway.SetContactRemarkResponse\x12`\n" +
+	"\x11OpenSingleSession\x12$.apigateway.OpenSingleSessionRequest\x1a%.apigateway.OpenSingleSessionResponseB\fZ\n" +
 	"./;serviceb\x06proto3"
 
 var (
@@ -1106,49 +3563,147 @@ func file_rpc_service_apigateway_proto_rawDescGZIP() []byte {
 	return file_rpc_service_apigateway_proto_rawDescData
 }
 
-var file_rpc_service_apigateway_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_rpc_service_apigateway_proto_msgTypes = make([]protoimpl.MessageInfo, 61)
 var file_rpc_service_apigateway_proto_goTypes = []any{
-	(*HistoryMessageRequest)(nil),      // 0: apigateway.HistoryMessageRequest
-	(*HistoryMessageResponse)(nil),     // 1: apigateway.HistoryMessageResponse
-	(*GetSessionUserListRequest)(nil),  // 2: apigateway.GetSessionUserListRequest
-	(*GetSessionUserListResponse)(nil), // 3: apigateway.GetSessionUserListResponse
-	(*SessionUserListItem)(nil),        // 4: apigateway.SessionUserListItem
-	(*Message)(nil),                    // 5: apigateway.Message
-	(*SessionListRequest)(nil),         // 6: apigateway.SessionListRequest
-	(*SessionListResponse)(nil),        // 7: apigateway.SessionListResponse
-	(*Session)(nil),                    // 8: apigateway.Session
-	(*LoginRequest)(nil),               // 9: apigateway.LoginRequest
-	(*LoginResponse)(nil),              // 10: apigateway.LoginResponse
-	(*RegisterRequest)(nil),            // 11: apigateway.RegisterRequest
-	(*RegisterResponse)(nil),           // 12: apigateway.RegisterResponse
-	(*SendMessageRequest)(nil),         // 13: apigateway.SendMessageRequest
-	(*SendMessageResponse)(nil),        // 14: apigateway.SendMessageResponse
-	(*GetUserInfoRequest)(nil),         // 15: apigateway.GetUserInfoRequest
-	(*GetUserInfoResponse)(nil),        // 16: apigateway.GetUserInfoResponse
+	(*HistoryMessageRequest)(nil),       // 0: apigateway.HistoryMessageRequest
+	(*HistoryMessageResponse)(nil),      // 1: apigateway.HistoryMessageResponse
+	(*GetSessionUserListRequest)(nil),   // 2: apigateway.GetSessionUserListRequest
+	(*GetSessionUserListResponse)(nil),  // 3: apigateway.GetSessionUserListResponse
+	(*SessionUserListItem)(nil),         // 4: apigateway.SessionUserListItem
+	(*Message)(nil),                     // 5: apigateway.Message
+	(*SessionListRequest)(nil),          // 6: apigateway.SessionListRequest
+	(*SessionListResponse)(nil),         // 7: apigateway.SessionListResponse
+	(*Session)(nil),                     // 8: apigateway.Session
+	(*LoginRequest)(nil),                // 9: apigateway.LoginRequest
+	(*LoginResponse)(nil),               // 10: apigateway.LoginResponse
+	(*RegisterRequest)(nil),             // 11: apigateway.RegisterRequest
+	(*RegisterResponse)(nil),            // 12: apigateway.RegisterResponse
+	(*SendMessageRequest)(nil),          // 13: apigateway.SendMessageRequest
+	(*SendMessageResponse)(nil),         // 14: apigateway.SendMessageResponse
+	(*GetUserInfoRequest)(nil),          // 15: apigateway.GetUserInfoRequest
+	(*GetUserInfoResponse)(nil),         // 16: apigateway.GetUserInfoResponse
+	(*CreateSessionRequest)(nil),        // 17: apigateway.CreateSessionRequest
+	(*CreateSessionResponse)(nil),       // 18: apigateway.CreateSessionResponse
+	(*JoinSessionRequest)(nil),          // 19: apigateway.JoinSessionRequest
+	(*JoinSessionResponse)(nil),         // 20: apigateway.JoinSessionResponse
+	(*LeaveSessionRequest)(nil),         // 21: apigateway.LeaveSessionRequest
+	(*LeaveSessionResponse)(nil),        // 22: apigateway.LeaveSessionResponse
+	(*CreateGroupRequest)(nil),          // 23: apigateway.CreateGroupRequest
+	(*CreateGroupResponse)(nil),         // 24: apigateway.CreateGroupResponse
+	(*UpdateGroupRequest)(nil),          // 25: apigateway.UpdateGroupRequest
+	(*UpdateGroupResponse)(nil),         // 26: apigateway.UpdateGroupResponse
+	(*AddGroupMembersRequest)(nil),      // 27: apigateway.AddGroupMembersRequest
+	(*AddGroupMembersResponse)(nil),     // 28: apigateway.AddGroupMembersResponse
+	(*RemoveGroupMemberRequest)(nil),    // 29: apigateway.RemoveGroupMemberRequest
+	(*RemoveGroupMemberResponse)(nil),   // 30: apigateway.RemoveGroupMemberResponse
+	(*SetGroupAdminRequest)(nil),        // 31: apigateway.SetGroupAdminRequest
+	(*SetGroupAdminResponse)(nil),       // 32: apigateway.SetGroupAdminResponse
+	(*LeaveGroupRequest)(nil),           // 33: apigateway.LeaveGroupRequest
+	(*LeaveGroupResponse)(nil),          // 34: apigateway.LeaveGroupResponse
+	(*DissolveGroupRequest)(nil),        // 35: apigateway.DissolveGroupRequest
+	(*DissolveGroupResponse)(nil),       // 36: apigateway.DissolveGroupResponse
+	(*ListGroupMembersRequest)(nil),     // 37: apigateway.ListGroupMembersRequest
+	(*ListGroupMembersResponse)(nil),    // 38: apigateway.ListGroupMembersResponse
+	(*GroupMember)(nil),                 // 39: apigateway.GroupMember
+	(*SearchUsersRequest)(nil),          // 40: apigateway.SearchUsersRequest
+	(*SearchUsersResponse)(nil),         // 41: apigateway.SearchUsersResponse
+	(*UserSummary)(nil),                 // 42: apigateway.UserSummary
+	(*SendFriendRequestRequest)(nil),    // 43: apigateway.SendFriendRequestRequest
+	(*SendFriendRequestResponse)(nil),   // 44: apigateway.SendFriendRequestResponse
+	(*ListFriendRequestsRequest)(nil),   // 45: apigateway.ListFriendRequestsRequest
+	(*ListFriendRequestsResponse)(nil),  // 46: apigateway.ListFriendRequestsResponse
+	(*FriendRequest)(nil),               // 47: apigateway.FriendRequest
+	(*AcceptFriendRequestRequest)(nil),  // 48: apigateway.AcceptFriendRequestRequest
+	(*AcceptFriendRequestResponse)(nil), // 49: apigateway.AcceptFriendRequestResponse
+	(*RejectFriendRequestRequest)(nil),  // 50: apigateway.RejectFriendRequestRequest
+	(*RejectFriendRequestResponse)(nil), // 51: apigateway.RejectFriendRequestResponse
+	(*ListContactsRequest)(nil),         // 52: apigateway.ListContactsRequest
+	(*ListContactsResponse)(nil),        // 53: apigateway.ListContactsResponse
+	(*Contact)(nil),                     // 54: apigateway.Contact
+	(*RemoveContactRequest)(nil),        // 55: apigateway.RemoveContactRequest
+	(*RemoveContactResponse)(nil),       // 56: apigateway.RemoveContactResponse
+	(*SetContactRemarkRequest)(nil),     // 57: apigateway.SetContactRemarkRequest
+	(*SetContactRemarkResponse)(nil),    // 58: apigateway.SetContactRemarkResponse
+	(*OpenSingleSessionRequest)(nil),    // 59: apigateway.OpenSingleSessionRequest
+	(*OpenSingleSessionResponse)(nil),   // 60: apigateway.OpenSingleSessionResponse
 }
 var file_rpc_service_apigateway_proto_depIdxs = []int32{
 	5,  // 0: apigateway.HistoryMessageResponse.messages:type_name -> apigateway.Message
 	4,  // 1: apigateway.GetSessionUserListResponse.users:type_name -> apigateway.SessionUserListItem
 	8,  // 2: apigateway.SessionListResponse.sessions:type_name -> apigateway.Session
-	6,  // 3: apigateway.APIGateway.SessionList:input_type -> apigateway.SessionListRequest
-	2,  // 4: apigateway.APIGateway.GetSessionUserList:input_type -> apigateway.GetSessionUserListRequest
-	0,  // 5: apigateway.APIGateway.HistoryMessage:input_type -> apigateway.HistoryMessageRequest
-	9,  // 6: apigateway.APIGateway.Login:input_type -> apigateway.LoginRequest
-	11, // 7: apigateway.APIGateway.Register:input_type -> apigateway.RegisterRequest
-	13, // 8: apigateway.APIGateway.SendMessage:input_type -> apigateway.SendMessageRequest
-	15, // 9: apigateway.APIGateway.GetUserInfo:input_type -> apigateway.GetUserInfoRequest
-	7,  // 10: apigateway.APIGateway.SessionList:output_type -> apigateway.SessionListResponse
-	3,  // 11: apigateway.APIGateway.GetSessionUserList:output_type -> apigateway.GetSessionUserListResponse
-	1,  // 12: apigateway.APIGateway.HistoryMessage:output_type -> apigateway.HistoryMessageResponse
-	10, // 13: apigateway.APIGateway.Login:output_type -> apigateway.LoginResponse
-	12, // 14: apigateway.APIGateway.Register:output_type -> apigateway.RegisterResponse
-	14, // 15: apigateway.APIGateway.SendMessage:output_type -> apigateway.SendMessageResponse
-	16, // 16: apigateway.APIGateway.GetUserInfo:output_type -> apigateway.GetUserInfoResponse
-	10, // [10:17] is the sub-list for method output_type
-	3,  // [3:10] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	5,  // 3: apigateway.CreateSessionResponse.message:type_name -> apigateway.Message
+	5,  // 4: apigateway.JoinSessionResponse.message:type_name -> apigateway.Message
+	5,  // 5: apigateway.LeaveSessionResponse.message:type_name -> apigateway.Message
+	5,  // 6: apigateway.CreateGroupResponse.message:type_name -> apigateway.Message
+	5,  // 7: apigateway.UpdateGroupResponse.message:type_name -> apigateway.Message
+	5,  // 8: apigateway.AddGroupMembersResponse.message:type_name -> apigateway.Message
+	5,  // 9: apigateway.RemoveGroupMemberResponse.message:type_name -> apigateway.Message
+	5,  // 10: apigateway.SetGroupAdminResponse.message:type_name -> apigateway.Message
+	5,  // 11: apigateway.LeaveGroupResponse.message:type_name -> apigateway.Message
+	5,  // 12: apigateway.DissolveGroupResponse.message:type_name -> apigateway.Message
+	39, // 13: apigateway.ListGroupMembersResponse.members:type_name -> apigateway.GroupMember
+	42, // 14: apigateway.SearchUsersResponse.users:type_name -> apigateway.UserSummary
+	47, // 15: apigateway.ListFriendRequestsResponse.requests:type_name -> apigateway.FriendRequest
+	54, // 16: apigateway.ListContactsResponse.contacts:type_name -> apigateway.Contact
+	6,  // 17: apigateway.APIGateway.SessionList:input_type -> apigateway.SessionListRequest
+	2,  // 18: apigateway.APIGateway.GetSessionUserList:input_type -> apigateway.GetSessionUserListRequest
+	0,  // 19: apigateway.APIGateway.HistoryMessage:input_type -> apigateway.HistoryMessageRequest
+	9,  // 20: apigateway.APIGateway.Login:input_type -> apigateway.LoginRequest
+	11, // 21: apigateway.APIGateway.Register:input_type -> apigateway.RegisterRequest
+	13, // 22: apigateway.APIGateway.SendMessage:input_type -> apigateway.SendMessageRequest
+	15, // 23: apigateway.APIGateway.GetUserInfo:input_type -> apigateway.GetUserInfoRequest
+	17, // 24: apigateway.APIGateway.CreateSession:input_type -> apigateway.CreateSessionRequest
+	19, // 25: apigateway.APIGateway.JoinSession:input_type -> apigateway.JoinSessionRequest
+	21, // 26: apigateway.APIGateway.LeaveSession:input_type -> apigateway.LeaveSessionRequest
+	23, // 27: apigateway.APIGateway.CreateGroup:input_type -> apigateway.CreateGroupRequest
+	25, // 28: apigateway.APIGateway.UpdateGroup:input_type -> apigateway.UpdateGroupRequest
+	27, // 29: apigateway.APIGateway.AddGroupMembers:input_type -> apigateway.AddGroupMembersRequest
+	29, // 30: apigateway.APIGateway.RemoveGroupMember:input_type -> apigateway.RemoveGroupMemberRequest
+	31, // 31: apigateway.APIGateway.SetGroupAdmin:input_type -> apigateway.SetGroupAdminRequest
+	33, // 32: apigateway.APIGateway.LeaveGroup:input_type -> apigateway.LeaveGroupRequest
+	35, // 33: apigateway.APIGateway.DissolveGroup:input_type -> apigateway.DissolveGroupRequest
+	37, // 34: apigateway.APIGateway.ListGroupMembers:input_type -> apigateway.ListGroupMembersRequest
+	40, // 35: apigateway.APIGateway.SearchUsers:input_type -> apigateway.SearchUsersRequest
+	43, // 36: apigateway.APIGateway.SendFriendRequest:input_type -> apigateway.SendFriendRequestRequest
+	45, // 37: apigateway.APIGateway.ListFriendRequests:input_type -> apigateway.ListFriendRequestsRequest
+	48, // 38: apigateway.APIGateway.AcceptFriendRequest:input_type -> apigateway.AcceptFriendRequestRequest
+	50, // 39: apigateway.APIGateway.RejectFriendRequest:input_type -> apigateway.RejectFriendRequestRequest
+	52, // 40: apigateway.APIGateway.ListContacts:input_type -> apigateway.ListContactsRequest
+	55, // 41: apigateway.APIGateway.RemoveContact:input_type -> apigateway.RemoveContactRequest
+	57, // 42: apigateway.APIGateway.SetContactRemark:input_type -> apigateway.SetContactRemarkRequest
+	59, // 43: apigateway.APIGateway.OpenSingleSession:input_type -> apigateway.OpenSingleSessionRequest
+	7,  // 44: apigateway.APIGateway.SessionList:output_type -> apigateway.SessionListResponse
+	3,  // 45: apigateway.APIGateway.GetSessionUserList:output_type -> apigateway.GetSessionUserListResponse
+	1,  // 46: apigateway.APIGateway.HistoryMessage:output_type -> apigateway.HistoryMessageResponse
+	10, // 47: apigateway.APIGateway.Login:output_type -> apigateway.LoginResponse
+	12, // 48: apigateway.APIGateway.Register:output_type -> apigateway.RegisterResponse
+	14, // 49: apigateway.APIGateway.SendMessage:output_type -> apigateway.SendMessageResponse
+	16, // 50: apigateway.APIGateway.GetUserInfo:output_type -> apigateway.GetUserInfoResponse
+	18, // 51: apigateway.APIGateway.CreateSession:output_type -> apigateway.CreateSessionResponse
+	20, // 52: apigateway.APIGateway.JoinSession:output_type -> apigateway.JoinSessionResponse
+	22, // 53: apigateway.APIGateway.LeaveSession:output_type -> apigateway.LeaveSessionResponse
+	24, // 54: apigateway.APIGateway.CreateGroup:output_type -> apigateway.CreateGroupResponse
+	26, // 55: apigateway.APIGateway.UpdateGroup:output_type -> apigateway.UpdateGroupResponse
+	28, // 56: apigateway.APIGateway.AddGroupMembers:output_type -> apigateway.AddGroupMembersResponse
+	30, // 57: apigateway.APIGateway.RemoveGroupMember:output_type -> apigateway.RemoveGroupMemberResponse
+	32, // 58: apigateway.APIGateway.SetGroupAdmin:output_type -> apigateway.SetGroupAdminResponse
+	34, // 59: apigateway.APIGateway.LeaveGroup:output_type -> apigateway.LeaveGroupResponse
+	36, // 60: apigateway.APIGateway.DissolveGroup:output_type -> apigateway.DissolveGroupResponse
+	38, // 61: apigateway.APIGateway.ListGroupMembers:output_type -> apigateway.ListGroupMembersResponse
+	41, // 62: apigateway.APIGateway.SearchUsers:output_type -> apigateway.SearchUsersResponse
+	44, // 63: apigateway.APIGateway.SendFriendRequest:output_type -> apigateway.SendFriendRequestResponse
+	46, // 64: apigateway.APIGateway.ListFriendRequests:output_type -> apigateway.ListFriendRequestsResponse
+	49, // 65: apigateway.APIGateway.AcceptFriendRequest:output_type -> apigateway.AcceptFriendRequestResponse
+	51, // 66: apigateway.APIGateway.RejectFriendRequest:output_type -> apigateway.RejectFriendRequestResponse
+	53, // 67: apigateway.APIGateway.ListContacts:output_type -> apigateway.ListContactsResponse
+	56, // 68: apigateway.APIGateway.RemoveContact:output_type -> apigateway.RemoveContactResponse
+	58, // 69: apigateway.APIGateway.SetContactRemark:output_type -> apigateway.SetContactRemarkResponse
+	60, // 70: apigateway.APIGateway.OpenSingleSession:output_type -> apigateway.OpenSingleSessionResponse
+	44, // [44:71] is the sub-list for method output_type
+	17, // [17:44] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_rpc_service_apigateway_proto_init() }
@@ -1162,7 +3717,7 @@ func file_rpc_service_apigateway_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_service_apigateway_proto_rawDesc), len(file_rpc_service_apigateway_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   17,
+			NumMessages:   61,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,428 @@
+package service
+
+import (
+	context "context"
+	"errors"
+	"im/model"
+	"im/pkg/xcontext"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+const (
+	searchUsersLimit          = 20  // 搜索用户最多返回的数量
+	friendRequestsLimit       = 100 // 好友申请列表最多返回的数量
+	maxRemarkLength           = 64  // 备注最大长度
+	maxFriendRequestMsgLength = 128 // 验证消息最大长度
+)
+
+// 单聊会话已被并发请求创建
+var errSessionBound = errors.New("single session already bound")
+
+func (s *APIGatewayService) SearchUsers(ctx context.Context, req *SearchUsersRequest) (*SearchUsersResponse, error) {
+	userUuid := xcontext.GetUserUUID(ctx)
+	keyword := strings.TrimSpace(req.Keyword)
+	resp := &SearchUsersResponse{
+		Users: make([]*UserSummary, 0),
+	}
+	if len(keyword) == 0 {
+		return resp, nil
+	}
+	// 精确匹配的账号排在前面
+	userUuids, err := s.UserIdentityModel.FindUserUuidsByIdentifier(ctx, keyword)
+	if err != nil {
+		return nil, err
+	}
+	userBases := make([]*model.UserBase, 0)
+	if len(userUuids) > 0 {
+		userBases, err = s.UserBaseModel.FindByUuids(ctx, userUuids)
+		if err != nil {
+			return nil, err
+		}
+	}
+	byName, err := s.UserBaseModel.SearchByName(ctx, keyword, searchUsersLimit)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]struct{}{userUuid: {}}
+	foundUuids := make([]string, 0)
+	for _, userBase := range append(userBases, byName...) {
+		if _, ok := seen[userBase.Uuid]; ok || len(resp.Users) >= searchUsersLimit {
+			continue
+		}
+		seen[userBase.Uuid] = struct{}{}
+		foundUuids = append(foundUuids, userBase.Uuid)
+		resp.Users = append(resp.Users, &UserSummary{
+			UserUuid:   userBase.Uuid,
+			UserName:   userBase.Name,
+			UserAvatar: userBase.Avatar,
+		})
+	}
+	contactUuids, err := s.ContactsModel.FindContactUuids(ctx, userUuid, foundUuids)
+	if err != nil {
+		return nil, err
+	}
+	contactMap := make(map[string]struct{}, len(contactUuids))
+	for _, contactUuid := range contactUuids {
+		contactMap[contactUuid] = struct{}{}
+	}
+	for _, user := range resp.Users {
+		_, user.IsContact = contactMap[user.UserUuid]
+	}
+	return resp, nil
+}
+
+func (s *APIGatewayService) SendFriendRequest(ctx context.Context, req *SendFriendRequestRequest) (*SendFriendRequestResponse, error) {
+	fromUuid := xcontext.GetUserUUID(ctx)
+	if err := checkFriendRequest(fromUuid, req.ToUuid, req.Message); err != nil {
+		return nil, err
+	}
+	toUser, err := s.UserBaseModel.FindByUuid(ctx, req.ToUuid)
+	if err != nil {
+		return nil, err
+	}
+	if toUser == nil {
+		return nil, errors.New("用户不存在")
+	}
+	contact, err := s.ContactsModel.FindContact(ctx, fromUuid, req.ToUuid)
+	if err != nil {
+		return nil, err
+	}
+	if contact != nil {
+		return nil, errors.New("对方已是你的联系人")
+	}
+
+	// 对方已向自己发送申请，直接同意
+	reverse, err := s.FriendRequestsModel.FindPending(ctx, req.ToUuid, fromUuid)
+	if err != nil {
+		return nil, err
+	}
+	if reverse != nil {
+		if err := s.acceptFriendRequest(ctx, reverse); err != nil {
+			return nil, err
+		}
+		return &SendFriendRequestResponse{RequestUuid: reverse.Uuid, Accepted: true}, nil
+	}
+	pending, err := s.FriendRequestsModel.FindPending(ctx, fromUuid, req.ToUuid)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return &SendFriendRequestResponse{RequestUuid: pending.Uuid}, nil
+	}
+	request := &model.FriendRequests{
+		Uuid:     uuid.New().String(),
+		FromUuid: fromUuid,
+		ToUuid:   req.ToUuid,
+		Message:  req.Message,
+		Status:   model.FriendRequestStatusPending,
+	}
+	if err := s.FriendRequestsModel.CreateRequest(ctx, request); err != nil {
+		return nil, err
+	}
+	return &SendFriendRequestResponse{RequestUuid: request.Uuid}, nil
+}
+
+func (s *APIGatewayService) ListFriendRequests(ctx context.Context, req *ListFriendRequestsRequest) (*ListFriendRequestsResponse, error) {
+	requests, err := s.FriendRequestsModel.FindRecentByUserUuid(ctx, xcontext.GetUserUUID(ctx), friendRequestsLimit)
+	if err != nil {
+		return nil, err
+	}
+	resp := &ListFriendRequestsResponse{
+		Requests: make([]*FriendRequest, 0, len(requests)),
+	}
+	if len(requests) == 0 {
+		return resp, nil
+	}
+	userUuids := make([]string, 0, len(requests)*2)
+	for _, request := range requests {
+		userUuids = append(userUuids, request.FromUuid, request.ToUuid)
+	}
+	userBaseMap, err := s.userBaseMap(ctx, uniqueUuids(userUuids))
+	if err != nil {
+		return nil, err
+	}
+	for _, request := range requests {
+		item := &FriendRequest{
+			RequestUuid: request.Uuid,
+			FromUuid:    request.FromUuid,
+			ToUuid:      request.ToUuid,
+			Message:     request.Message,
+			Status:      request.Status,
+			CreateTime:  request.CreatedAt.Format("1月2日 15:04"),
+		}
+		if userBase, ok := userBaseMap[request.FromUuid]; ok {
+			item.FromName = userBase.Name
+			item.FromAvatar = userBase.Avatar
+		}
+		if userBase, ok := userBaseMap[request.ToUuid]; ok {
+			item.ToName = userBase.Name
+			item.ToAvatar = userBase.Avatar
+		}
+		resp.Requests = append(resp.Requests, item)
+	}
+	return resp, nil
+}
+
+func (s *APIGatewayService) AcceptFriendRequest(ctx context.Context, req *AcceptFriendRequestRequest) (*AcceptFriendRequestResponse, error) {
+	request, err := s.receivedFriendRequest(ctx, req.RequestUuid)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.acceptFriendRequest(ctx, request); err != nil {
+		return nil, err
+	}
+	return &AcceptFriendRequestResponse{ContactUuid: request.FromUuid}, nil
+}
+
+func (s *APIGatewayService) RejectFriendRequest(ctx context.Context, req *RejectFriendRequestRequest) (*RejectFriendRequestResponse, error) {
+	request, err := s.receivedFriendRequest(ctx, req.RequestUuid)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.FriendRequestsModel.UpdateStatus(ctx, nil, request.Uuid, model.FriendRequestStatusPending, model.FriendRequestStatusRejected)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.New("好友申请已处理")
+	}
+	return &RejectFriendRequestResponse{}, nil
+}
+
+func (s *APIGatewayService) ListContacts(ctx context.Context, req *ListContactsRequest) (*ListContactsResponse, error) {
+	contacts, err := s.ContactsModel.FindContactsByUserUuid(ctx, xcontext.GetUserUUID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	resp := &ListContactsResponse{
+		Contacts: make([]*Contact, 0, len(contacts)),
+	}
+	if len(contacts) == 0 {
+		return resp, nil
+	}
+	contactUuids := make([]string, 0, len(contacts))
+	for _, contact := range contacts {
+		contactUuids = append(contactUuids, contact.ContactUuid)
+	}
+	userBaseMap, err := s.userBaseMap(ctx, contactUuids)
+	if err != nil {
+		return nil, err
+	}
+	for _, contact := range contacts {
+		item := &Contact{
+			UserUuid:    contact.ContactUuid,
+			Remark:      contact.Remark,
+			SessionUuid: contact.SessionUuid,
+		}
+		if userBase, ok := userBaseMap[contact.ContactUuid]; ok {
+			item.UserName = userBase.Name
+			item.UserAvatar = userBase.Avatar
+		}
+		resp.Contacts = append(resp.Contacts, item)
+	}
+	return resp, nil
+}
+
+// RemoveContact 双方同时删除联系人，并删除两人的单聊会话，之后不能再互发消息
+func (s *APIGatewayService) RemoveContact(ctx context.Context, req *RemoveContactRequest) (*RemoveContactResponse, error) {
+	userUuid := xcontext.GetUserUUID(ctx)
+	if _, err := s.contactOf(ctx, userUuid, req.ContactUuid); err != nil {
+		return nil, err
+	}
+	// 与打开会话相同的顺序加锁，避免死锁
+	first, second := userUuid, req.ContactUuid
+	if first > second {
+		first, second = second, first
+	}
+	var sessionUuid string
+	if err := s.MysqlClient.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		for _, pair := range [][2]string{{first, second}, {second, first}} {
+			// 加锁读取，同时打开的会话要么已记录在联系人中，要么在删除后记录失败
+			contact, err := s.ContactsModel.FindContactForUpdate(ctx, session, pair[0], pair[1])
+			if err != nil {
+				return err
+			}
+			if contact != nil && len(contact.SessionUuid) > 0 {
+				sessionUuid = contact.SessionUuid
+			}
+			if err := s.ContactsModel.RemoveContact(ctx, session, pair[0], pair[1]); err != nil {
+				return err
+			}
+		}
+		if len(sessionUuid) == 0 {
+			return nil
+		}
+		if err := s.SessionsModel.UpdateStatus(ctx, session, sessionUuid, model.SessionStatusDeleted); err != nil {
+			return err
+		}
+		return s.SessionMembersModel.DeleteBySessionUuid(ctx, session, sessionUuid)
+	}); err != nil {
+		return nil, err
+	}
+	if len(sessionUuid) > 0 {
+		s.sessionMembersChanged(ctx, sessionUuid)
+	}
+	return &RemoveContactResponse{}, nil
+}
+
+func (s *APIGatewayService) SetContactRemark(ctx context.Context, req *SetContactRemarkRequest) (*SetContactRemarkResponse, error) {
+	userUuid := xcontext.GetUserUUID(ctx)
+	remark := strings.TrimSpace(req.Remark)
+	if utf8.RuneCountInString(remark) > maxRemarkLength {
+		return nil, errors.New("备注过长")
+	}
+	if _, err := s.contactOf(ctx, userUuid, req.ContactUuid); err != nil {
+		return nil, err
+	}
+	if err := s.ContactsModel.UpdateRemark(ctx, userUuid, req.ContactUuid, remark); err != nil {
+		return nil, err
+	}
+	return &SetContactRemarkResponse{}, nil
+}
+
+func (s *APIGatewayService) OpenSingleSession(ctx context.Context, req *OpenSingleSessionRequest) (*OpenSingleSessionResponse, error) {
+	userUuid := xcontext.GetUserUUID(ctx)
+	contact, err := s.contactOf(ctx, userUuid, req.ContactUuid)
+	if err != nil {
+		return nil, err
+	}
+	if len(contact.SessionUuid) > 0 {
+		return &OpenSingleSessionResponse{SessionUuid: contact.SessionUuid}, nil
+	}
+	sessionUuid, err := s.openSingleSession(ctx, userUuid, req.ContactUuid)
+	if errors.Is(err, errSessionBound) {
+		// 对方同时打开了会话，使用对方创建的会话
+		contact, err = s.contactOf(ctx, userUuid, req.ContactUuid)
+		if err != nil {
+			return nil, err
+		}
+		if len(contact.SessionUuid) == 0 {
+			return nil, errors.New("打开会话失败，请重试")
+		}
+		return &OpenSingleSessionResponse{SessionUuid: contact.SessionUuid}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &OpenSingleSessionResponse{SessionUuid: sessionUuid}, nil
+}
+
+// 复用两人之间已有的单聊会话，没有时创建，并记录到双方的联系人
+func (s *APIGatewayService) openSingleSession(ctx context.Context, userUuid string, contactUuid string) (string, error) {
+	// 联系人功能之前创建的单聊会话没有记录在联系人中，继续使用
+	sessionUuid, err := s.SessionMembersModel.FindSingleSession(ctx, userUuid, contactUuid)
+	if err != nil {
+		return "", err
+	}
+	create := len(sessionUuid) == 0
+	if create {
+		sessionUuid = uuid.New().String()
+	}
+	// 按固定顺序加锁，避免双方同时打开会话时死锁
+	first, second := userUuid, contactUuid
+	if first > second {
+		first, second = second, first
+	}
+	if err := s.MysqlClient.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		for _, pair := range [][2]string{{first, second}, {second, first}} {
+			bound, err := s.ContactsModel.BindSession(ctx, session, pair[0], pair[1], sessionUuid)
+			if err != nil {
+				return err
+			}
+			if !bound {
+				return errSessionBound
+			}
+		}
+		if !create {
+			return nil
+		}
+		if err := s.SessionsModel.CreateSession(ctx, session, &model.Sessions{
+			Uuid:        sessionUuid,
+			SessionType: model.SessionTypeSingle,
+			Status:      model.SessionStatusActive,
+		}); err != nil {
+			return err
+		}
+		if err := s.SessionMembersModel.JoinSession(ctx, session, sessionUuid, userUuid, model.MemberRoleMember); err != nil {
+			return err
+		}
+		return s.SessionMembersModel.JoinSession(ctx, session, sessionUuid, contactUuid, model.MemberRoleMember)
+	}); err != nil {
+		return "", err
+	}
+	return sessionUuid, nil
+}
+
+// 同意好友申请，双方互相添加为联系人
+func (s *APIGatewayService) acceptFriendRequest(ctx context.Context, request *model.FriendRequests) error {
+	return s.MysqlClient.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		updated, err := s.FriendRequestsModel.UpdateStatus(ctx, session, request.Uuid, model.FriendRequestStatusPending, model.FriendRequestStatusAccepted)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return errors.New("好友申请已处理")
+		}
+		if err := s.ContactsModel.AddContact(ctx, session, request.FromUuid, request.ToUuid); err != nil {
+			return err
+		}
+		return s.ContactsModel.AddContact(ctx, session, request.ToUuid, request.FromUuid)
+	})
+}
+
+// 查询发给当前用户的好友申请
+func (s *APIGatewayService) receivedFriendRequest(ctx context.Context, requestUuid string) (*model.FriendRequests, error) {
+	request, err := s.FriendRequestsModel.FindByUuid(ctx, requestUuid)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil || request.ToUuid != xcontext.GetUserUUID(ctx) {
+		return nil, errors.New("好友申请不存在")
+	}
+	if request.Status != model.FriendRequestStatusPending {
+		return nil, errors.New("好友申请已处理")
+	}
+	return request, nil
+}
+
+// 查询联系人，不是联系人时返回错误
+func (s *APIGatewayService) contactOf(ctx context.Context, userUuid string, contactUuid string) (*model.Contacts, error) {
+	contact, err := s.ContactsModel.FindContact(ctx, userUuid, contactUuid)
+	if err != nil {
+		return nil, err
+	}
+	if contact == nil {
+		return nil, errors.New("对方不是你的联系人")
+	}
+	return contact, nil
+}
+
+func (s *APIGatewayService) userBaseMap(ctx context.Context, userUuids []string) (map[string]*model.UserBase, error) {
+	userBases, err := s.UserBaseModel.FindByUuids(ctx, userUuids)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*model.UserBase, len(userBases))
+	for _, userBase := range userBases {
+		result[userBase.Uuid] = userBase
+	}
+	return result, nil
+}
+
+// 校验好友申请参数
+func checkFriendRequest(fromUuid string, toUuid string, message string) error {
+	if len(toUuid) == 0 {
+		return errors.New("接收者不能为空")
+	}
+	if fromUuid == toUuid {
+		return errors.New("不能添加自己为联系人")
+	}
+	if utf8.RuneCountInString(message) > maxFriendRequestMsgLength {
+		return errors.New("验证消息过长")
+	}
+	return nil
+}
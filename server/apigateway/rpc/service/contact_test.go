@@ -0,0 +1,393 @@
+package service
+
+import (
+	"context"
+	"im/model"
+	"im/pkg/event"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+func TestCheckFriendRequest(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		to       string
+		message  string
+		expected bool
+	}{
+		{"valid", "user-b", "你好", true},
+		{"empty receiver", "", "", false},
+		{"self", "user-a", "", false},
+		{"message max length", "user-b", strings.Repeat("好", maxFriendRequestMsgLength), true},
+		{"message too long", "user-b", strings.Repeat("好", maxFriendRequestMsgLength+1), false},
+	} {
+		if err := checkFriendRequest("user-a", tc.to, tc.message); (err == nil) != tc.expected {
+			t.Fatalf("%s: unexpected result %v", tc.name, err)
+		}
+	}
+}
+
+type memoryContactsModel struct {
+	model.ContactsModel
+	*memoryStore
+	binds [][2]string // BindSession的调用顺序
+}
+
+func (m *memoryContactsModel) FindContact(ctx context.Context, userUuid string, contactUuid string) (*model.Contacts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, contact := range m.contacts {
+		if contact.UserUuid == userUuid && contact.ContactUuid == contactUuid {
+			copied := *contact
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryContactsModel) FindContactForUpdate(ctx context.Context, tx sqlx.Session, userUuid string, contactUuid string) (*model.Contacts, error) {
+	return m.FindContact(ctx, userUuid, contactUuid)
+}
+
+func (m *memoryContactsModel) FindContactsByUserUuid(ctx context.Context, userUuid string) ([]*model.Contacts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var contacts []*model.Contacts
+	for _, contact := range m.contacts {
+		if contact.UserUuid == userUuid {
+			copied := *contact
+			contacts = append(contacts, &copied)
+		}
+	}
+	return contacts, nil
+}
+
+func (m *memoryContactsModel) AddContact(ctx context.Context, tx sqlx.Session, userUuid string, contactUuid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, contact := range m.contacts {
+		if contact.UserUuid == userUuid && contact.ContactUuid == contactUuid {
+			return nil
+		}
+	}
+	m.nextId++
+	m.contacts = append(m.contacts, &model.Contacts{Id: m.nextId, UserUuid: userUuid, ContactUuid: contactUuid})
+	return nil
+}
+
+func (m *memoryContactsModel) RemoveContact(ctx context.Context, tx sqlx.Session, userUuid string, contactUuid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.contacts = slices.DeleteFunc(m.contacts, func(contact *model.Contacts) bool {
+		return contact.UserUuid == userUuid && contact.ContactUuid == contactUuid
+	})
+	return nil
+}
+
+func (m *memoryContactsModel) BindSession(ctx context.Context, tx sqlx.Session, userUuid string, contactUuid string, sessionUuid string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.binds = append(m.binds, [2]string{userUuid, contactUuid})
+	for _, contact := range m.contacts {
+		if contact.UserUuid == userUuid && contact.ContactUuid == contactUuid && len(contact.SessionUuid) == 0 {
+			contact.SessionUuid = sessionUuid
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type memoryFriendRequestsModel struct {
+	model.FriendRequestsModel
+	*memoryStore
+}
+
+func (m *memoryFriendRequestsModel) FindByUuid(ctx context.Context, uuid string) (*model.FriendRequests, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, request := range m.requests {
+		if request.Uuid == uuid {
+			copied := *request
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryFriendRequestsModel) FindPending(ctx context.Context, fromUuid string, toUuid string) (*model.FriendRequests, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, request := range slices.Backward(m.requests) {
+		if request.FromUuid == fromUuid && request.ToUuid == toUuid && request.Status == model.FriendRequestStatusPending {
+			copied := *request
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryFriendRequestsModel) CreateRequest(ctx context.Context, data *model.FriendRequests) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextId++
+	copied := *data
+	copied.Id = m.nextId
+	m.requests = append(m.requests, &copied)
+	return nil
+}
+
+func (m *memoryFriendRequestsModel) UpdateStatus(ctx context.Context, tx sqlx.Session, uuid string, fromStatus int64, toStatus int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, request := range m.requests {
+		if request.Uuid == uuid && request.Status == fromStatus {
+			request.Status = toStatus
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func newTestContactService(t *testing.T) (*APIGatewayService, *memoryStore) {
+	t.Helper()
+	s, store, _ := newTestService(t)
+	for _, userUuid := range []string{"user-a", "user-b", "user-c"} {
+		store.addUser(userUuid, strings.ToUpper(userUuid[len(userUuid)-1:]))
+	}
+	return s, store
+}
+
+// 返回用户的联系人UUID
+func contactUuids(t *testing.T, s *APIGatewayService, userUuid string) []string {
+	t.Helper()
+	resp, err := s.ListContacts(userContext(userUuid), &ListContactsRequest{})
+	if err != nil {
+		t.Fatalf("failed to list contacts: %v", err)
+	}
+	result := make([]string, 0, len(resp.Contacts))
+	for _, contact := range resp.Contacts {
+		result = append(result, contact.UserUuid)
+	}
+	return result
+}
+
+// a和b互相添加为联系人
+func befriend(t *testing.T, s *APIGatewayService, a string, b string) {
+	t.Helper()
+	sent, err := s.SendFriendRequest(userContext(a), &SendFriendRequestRequest{ToUuid: b})
+	if err != nil {
+		t.Fatalf("failed to send friend request: %v", err)
+	}
+	if _, err := s.AcceptFriendRequest(userContext(b), &AcceptFriendRequestRequest{RequestUuid: sent.RequestUuid}); err != nil {
+		t.Fatalf("failed to accept friend request: %v", err)
+	}
+}
+
+func TestSendFriendRequestAutoAccept(t *testing.T) {
+	s, _ := newTestContactService(t)
+
+	sent, err := s.SendFriendRequest(userContext("user-a"), &SendFriendRequestRequest{ToUuid: "user-b", Message: "你好"})
+	if err != nil || sent.Accepted {
+		t.Fatalf("unexpected send result: %v %v", sent, err)
+	}
+	// 重复发送返回待处理的申请
+	resent, err := s.SendFriendRequest(userContext("user-a"), &SendFriendRequestRequest{ToUuid: "user-b"})
+	if err != nil || resent.RequestUuid != sent.RequestUuid {
+		t.Fatalf("expected pending request %s, got %v %v", sent.RequestUuid, resent, err)
+	}
+	if contacts := contactUuids(t, s, "user-a"); len(contacts) != 0 {
+		t.Fatalf("expected no contacts before accepted, got %v", contacts)
+	}
+
+	// 对方反向发送时直接同意
+	reverse, err := s.SendFriendRequest(userContext("user-b"), &SendFriendRequestRequest{ToUuid: "user-a"})
+	if err != nil {
+		t.Fatalf("failed to send reverse request: %v", err)
+	}
+	if !reverse.Accepted || reverse.RequestUuid != sent.RequestUuid {
+		t.Fatalf("expected request %s auto accepted, got %v", sent.RequestUuid, reverse)
+	}
+	if !slices.Equal(contactUuids(t, s, "user-a"), []string{"user-b"}) || !slices.Equal(contactUuids(t, s, "user-b"), []string{"user-a"}) {
+		t.Fatalf("expected contacts on both sides")
+	}
+	if _, err := s.SendFriendRequest(userContext("user-a"), &SendFriendRequestRequest{ToUuid: "user-b"}); err == nil {
+		t.Fatalf("expected error when already contacts")
+	}
+}
+
+func TestFriendRequestTransitions(t *testing.T) {
+	ctx := context.Background()
+	s, store := newTestContactService(t)
+
+	sent, err := s.SendFriendRequest(userContext("user-a"), &SendFriendRequestRequest{ToUuid: "user-b"})
+	if err != nil {
+		t.Fatalf("failed to send friend request: %v", err)
+	}
+	// 只有接收者可以处理
+	if _, err := s.AcceptFriendRequest(userContext("user-a"), &AcceptFriendRequestRequest{RequestUuid: sent.RequestUuid}); err == nil {
+		t.Fatalf("expected sender unable to accept")
+	}
+	if _, err := s.RejectFriendRequest(userContext("user-b"), &RejectFriendRequestRequest{RequestUuid: sent.RequestUuid}); err != nil {
+		t.Fatalf("failed to reject: %v", err)
+	}
+	// 已拒绝的申请不能再处理
+	if _, err := s.RejectFriendRequest(userContext("user-b"), &RejectFriendRequestRequest{RequestUuid: sent.RequestUuid}); err == nil {
+		t.Fatalf("expected rejected request unable to reject again")
+	}
+	if _, err := s.AcceptFriendRequest(userContext("user-b"), &AcceptFriendRequestRequest{RequestUuid: sent.RequestUuid}); err == nil {
+		t.Fatalf("expected rejected request unable to accept")
+	}
+
+	// 被拒绝后可以重新申请
+	resent, err := s.SendFriendRequest(userContext("user-a"), &SendFriendRequestRequest{ToUuid: "user-b"})
+	if err != nil || resent.RequestUuid == sent.RequestUuid {
+		t.Fatalf("expected new request, got %v %v", resent, err)
+	}
+	accepted, err := s.AcceptFriendRequest(userContext("user-b"), &AcceptFriendRequestRequest{RequestUuid: resent.RequestUuid})
+	if err != nil || accepted.ContactUuid != "user-a" {
+		t.Fatalf("unexpected accept result: %v %v", accepted, err)
+	}
+	if _, err := s.AcceptFriendRequest(userContext("user-b"), &AcceptFriendRequestRequest{RequestUuid: resent.RequestUuid}); err == nil {
+		t.Fatalf("expected accepted request unable to accept again")
+	}
+
+	// 并发同意时状态校验失败的一方回滚，不重复添加联系人
+	request, _ := s.FriendRequestsModel.FindByUuid(ctx, resent.RequestUuid)
+	request.Status = model.FriendRequestStatusPending
+	if err := s.acceptFriendRequest(ctx, request); err == nil {
+		t.Fatalf("expected stale accept to fail")
+	}
+	if len(store.contacts) != 2 {
+		t.Fatalf("expected 2 contact records, got %d", len(store.contacts))
+	}
+}
+
+func TestRemoveContact(t *testing.T) {
+	s, _ := newTestContactService(t)
+	befriend(t, s, "user-a", "user-b")
+	befriend(t, s, "user-a", "user-c")
+
+	opened, err := s.OpenSingleSession(userContext("user-a"), &OpenSingleSessionRequest{ContactUuid: "user-b"})
+	if err != nil {
+		t.Fatalf("failed to open session: %v", err)
+	}
+	pubsub := s.RedisClient.Subscribe(context.Background(), event.ChannelSessionMembers)
+	defer pubsub.Close()
+	if _, err := pubsub.Receive(context.Background()); err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	if _, err := s.RemoveContact(userContext("user-b"), &RemoveContactRequest{ContactUuid: "user-a"}); err != nil {
+		t.Fatalf("failed to remove contact: %v", err)
+	}
+	if !slices.Equal(contactUuids(t, s, "user-a"), []string{"user-c"}) || len(contactUuids(t, s, "user-b")) != 0 {
+		t.Fatalf("expected contact removed on both sides")
+	}
+	// 单聊会话的成员被移除，网关丢弃成员缓存后不能再发送消息
+	if members, _ := s.SessionMembersModel.FindMembersBySessionUuid(context.Background(), opened.SessionUuid); len(members) != 0 {
+		t.Fatalf("expected session members removed, got %v", members)
+	}
+	select {
+	case msg := <-pubsub.Channel():
+		if msg.Payload != opened.SessionUuid {
+			t.Fatalf("expected session %s invalidated, got %s", opened.SessionUuid, msg.Payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected session members changed published")
+	}
+	if _, err := s.RemoveContact(userContext("user-a"), &RemoveContactRequest{ContactUuid: "user-b"}); err == nil {
+		t.Fatalf("expected error removing non-contact")
+	}
+}
+
+func TestOpenSingleSession(t *testing.T) {
+	s, store := newTestContactService(t)
+	if _, err := s.OpenSingleSession(userContext("user-b"), &OpenSingleSessionRequest{ContactUuid: "user-a"}); err == nil {
+		t.Fatalf("expected error opening session with non-contact")
+	}
+	befriend(t, s, "user-a", "user-b")
+
+	// 成为联系人时不创建会话，首次打开时创建
+	if len(store.sessions) != 0 {
+		t.Fatalf("expected no session before opened, got %d", len(store.sessions))
+	}
+	opened, err := s.OpenSingleSession(userContext("user-b"), &OpenSingleSessionRequest{ContactUuid: "user-a"})
+	if err != nil {
+		t.Fatalf("failed to open session: %v", err)
+	}
+	// 按UUID顺序绑定双方的联系人，避免死锁
+	binds := s.ContactsModel.(*memoryContactsModel).binds
+	if !slices.Equal(binds, [][2]string{{"user-a", "user-b"}, {"user-b", "user-a"}}) {
+		t.Fatalf("unexpected bind order: %v", binds)
+	}
+	members, _ := s.SessionMembersModel.FindMembersBySessionUuid(context.Background(), opened.SessionUuid)
+	if len(members) != 2 {
+		t.Fatalf("expected 2 members, got %v", members)
+	}
+	reopened, err := s.OpenSingleSession(userContext("user-a"), &OpenSingleSessionRequest{ContactUuid: "user-b"})
+	if err != nil || reopened.SessionUuid != opened.SessionUuid {
+		t.Fatalf("expected session %s, got %v %v", opened.SessionUuid, reopened, err)
+	}
+
+	// 删除联系人时删除单聊会话，重新添加后打开新的会话
+	if _, err := s.RemoveContact(userContext("user-a"), &RemoveContactRequest{ContactUuid: "user-b"}); err != nil {
+		t.Fatalf("failed to remove contact: %v", err)
+	}
+	if members, _ := s.SessionMembersModel.FindMembersBySessionUuid(context.Background(), opened.SessionUuid); len(members) != 0 {
+		t.Fatalf("expected members removed, got %v", members)
+	}
+	if session := store.sessions[opened.SessionUuid]; session.Status != model.SessionStatusDeleted {
+		t.Fatalf("expected session deleted, got %v", session)
+	}
+	befriend(t, s, "user-b", "user-a")
+	readded, err := s.OpenSingleSession(userContext("user-a"), &OpenSingleSessionRequest{ContactUuid: "user-b"})
+	if err != nil || readded.SessionUuid == opened.SessionUuid {
+		t.Fatalf("expected new session, got %v %v", readded, err)
+	}
+	if len(store.sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(store.sessions))
+	}
+}
+
+func TestOpenSingleSessionConcurrent(t *testing.T) {
+	s, store := newTestContactService(t)
+	befriend(t, s, "user-a", "user-b")
+
+	// 双方都查不到已有会话后再继续，同时创建会话
+	var found sync.WaitGroup
+	found.Add(2)
+	s.SessionMembersModel.(*memorySessionMembersModel).beforeFindSingle = func() {
+		found.Done()
+		found.Wait()
+	}
+	var wg sync.WaitGroup
+	results := make([]string, 2)
+	errs := make([]error, 2)
+	for i, pair := range [][2]string{{"user-a", "user-b"}, {"user-b", "user-a"}} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := s.OpenSingleSession(userContext(pair[0]), &OpenSingleSessionRequest{ContactUuid: pair[1]})
+			if err == nil {
+				results[i] = resp.SessionUuid
+			}
+			errs[i] = err
+		}()
+	}
+	wg.Wait()
+	if errs[0] != nil || errs[1] != nil {
+		t.Fatalf("failed to open session: %v", errs)
+	}
+	if results[0] != results[1] {
+		t.Fatalf("expected same session, got %v", results)
+	}
+	// 后提交的一方回滚，只保留一个会话
+	if len(store.sessions) != 1 || len(store.members) != 2 {
+		t.Fatalf("expected 1 session with 2 members, got %d sessions %d members", len(store.sessions), len(store.members))
+	}
+}
@@ -0,0 +1,430 @@
+package service
+
+import (
+	context "context"
+	"errors"
+	"fmt"
+	"im/model"
+	"im/pkg/xcontext"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// 未指定名称时的默认群名称
+const defaultGroupName = "群聊"
+
+func (s *APIGatewayService) CreateGroup(ctx context.Context, req *CreateGroupRequest) (*CreateGroupResponse, error) {
+	sessionUuid, memberUuids, message, err := s.createGroup(ctx, xcontext.GetUserUUID(ctx), req.Name, req.Avatar, req.MemberUuids)
+	if err != nil {
+		return nil, err
+	}
+	return &CreateGroupResponse{
+		SessionUuid: sessionUuid,
+		MemberUuids: memberUuids,
+		Message:     message,
+	}, nil
+}
+
+func (s *APIGatewayService) UpdateGroup(ctx context.Context, req *UpdateGroupRequest) (*UpdateGroupResponse, error) {
+	operatorUuid := xcontext.GetUserUUID(ctx)
+	if len(req.Name) == 0 && len(req.Avatar) == 0 {
+		return nil, errors.New("群名称和头像不能同时为空")
+	}
+	session, _, operator, err := s.groupMembers(ctx, req.SessionUuid, operatorUuid)
+	if err != nil {
+		return nil, err
+	}
+	if operator.Role > model.MemberRoleAdmin {
+		return nil, errors.New("只有群主和管理员可以修改群资料")
+	}
+	name, avatar := session.Name, session.Avatar
+	if len(req.Name) > 0 {
+		name = req.Name
+	}
+	if len(req.Avatar) > 0 {
+		avatar = req.Avatar
+	}
+	if err := s.SessionsModel.UpdateProfile(ctx, req.SessionUuid, name, avatar); err != nil {
+		return nil, err
+	}
+
+	operatorName := s.userNames(ctx, []string{operatorUuid})
+	var content string
+	switch {
+	case len(req.Name) > 0 && len(req.Avatar) > 0:
+		content = fmt.Sprintf("%s 修改群名称为 %s 并更换了群头像", operatorName, name)
+	case len(req.Name) > 0:
+		content = fmt.Sprintf("%s 修改群名称为 %s", operatorName, name)
+	default:
+		content = fmt.Sprintf("%s 更换了群头像", operatorName)
+	}
+	return &UpdateGroupResponse{
+		Message: s.sendSystemMessage(ctx, req.SessionUuid, operatorUuid, content),
+	}, nil
+}
+
+// AddGroupMembers 群内任何成员都可以邀请用户加入，与创建群聊一样不要求被邀请者是联系人
+func (s *APIGatewayService) AddGroupMembers(ctx context.Context, req *AddGroupMembersRequest) (*AddGroupMembersResponse, error) {
+	joinedUuids, memberUuids, message, err := s.addGroupMembers(ctx, req.SessionUuid, xcontext.GetUserUUID(ctx), req.UserUuids)
+	if err != nil {
+		return nil, err
+	}
+	return &AddGroupMembersResponse{
+		JoinedUuids: joinedUuids,
+		MemberUuids: memberUuids,
+		Message:     message,
+	}, nil
+}
+
+func (s *APIGatewayService) RemoveGroupMember(ctx context.Context, req *RemoveGroupMemberRequest) (*RemoveGroupMemberResponse, error) {
+	operatorUuid := xcontext.GetUserUUID(ctx)
+	if req.UserUuid == operatorUuid {
+		return nil, errors.New("不能移除自己，请使用退出群聊")
+	}
+	_, members, operator, err := s.groupMembers(ctx, req.SessionUuid, operatorUuid)
+	if err != nil {
+		return nil, err
+	}
+	target := findMember(members, req.UserUuid)
+	if target == nil {
+		return nil, errors.New("被移除的用户不是会话成员")
+	}
+	if !canManage(operator.Role, target.Role) {
+		return nil, errors.New("没有权限移除该成员")
+	}
+	if err := s.SessionMembersModel.LeaveSession(ctx, nil, req.SessionUuid, req.UserUuid); err != nil {
+		return nil, err
+	}
+	s.sessionMembersChanged(ctx, req.SessionUuid)
+
+	content := fmt.Sprintf("%s 将 %s 移出了群聊", s.userNames(ctx, []string{operatorUuid}), s.userNames(ctx, []string{req.UserUuid}))
+	return &RemoveGroupMemberResponse{
+		MemberUuids: memberUuidsExcept(members, req.UserUuid),
+		Message:     s.sendSystemMessage(ctx, req.SessionUuid, operatorUuid, content),
+	}, nil
+}
+
+func (s *APIGatewayService) SetGroupAdmin(ctx context.Context, req *SetGroupAdminRequest) (*SetGroupAdminResponse, error) {
+	operatorUuid := xcontext.GetUserUUID(ctx)
+	_, members, operator, err := s.groupMembers(ctx, req.SessionUuid, operatorUuid)
+	if err != nil {
+		return nil, err
+	}
+	if operator.Role != model.MemberRoleOwner {
+		return nil, errors.New("只有群主可以设置管理员")
+	}
+	target := findMember(members, req.UserUuid)
+	if target == nil {
+		return nil, errors.New("目标用户不是会话成员")
+	}
+	if target.Role == model.MemberRoleOwner {
+		return nil, errors.New("不能修改群主的角色")
+	}
+	role := int64(model.MemberRoleMember)
+	if req.Admin {
+		role = model.MemberRoleAdmin
+	}
+	if target.Role == role {
+		return &SetGroupAdminResponse{}, nil
+	}
+	if err := s.SessionMembersModel.UpdateRole(ctx, nil, req.SessionUuid, req.UserUuid, role); err != nil {
+		return nil, err
+	}
+
+	operatorName, targetName := s.userNames(ctx, []string{operatorUuid}), s.userNames(ctx, []string{req.UserUuid})
+	content := fmt.Sprintf("%s 将 %s 设为管理员", operatorName, targetName)
+	if !req.Admin {
+		content = fmt.Sprintf("%s 取消了 %s 的管理员身份", operatorName, targetName)
+	}
+	return &SetGroupAdminResponse{
+		Message: s.sendSystemMessage(ctx, req.SessionUuid, operatorUuid, content),
+	}, nil
+}
+
+func (s *APIGatewayService) LeaveGroup(ctx context.Context, req *LeaveGroupRequest) (*LeaveGroupResponse, error) {
+	memberUuids, message, err := s.leaveGroup(ctx, req.SessionUuid, xcontext.GetUserUUID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &LeaveGroupResponse{
+		MemberUuids: memberUuids,
+		Message:     message,
+	}, nil
+}
+
+func (s *APIGatewayService) DissolveGroup(ctx context.Context, req *DissolveGroupRequest) (*DissolveGroupResponse, error) {
+	operatorUuid := xcontext.GetUserUUID(ctx)
+	_, members, operator, err := s.groupMembers(ctx, req.SessionUuid, operatorUuid)
+	if err != nil {
+		return nil, err
+	}
+	if operator.Role != model.MemberRoleOwner {
+		return nil, errors.New("只有群主可以解散群聊")
+	}
+	if err := s.dissolveGroup(ctx, req.SessionUuid); err != nil {
+		return nil, err
+	}
+	// 拉取历史消息不校验成员，解散后写入的系统消息成员仍可拉取到
+	content := fmt.Sprintf("%s 解散了群聊", s.userNames(ctx, []string{operatorUuid}))
+	return &DissolveGroupResponse{
+		MemberUuids: memberUuidsExcept(members, ""),
+		Message:     s.sendSystemMessage(ctx, req.SessionUuid, operatorUuid, content),
+	}, nil
+}
+
+func (s *APIGatewayService) ListGroupMembers(ctx context.Context, req *ListGroupMembersRequest) (*ListGroupMembersResponse, error) {
+	_, members, _, err := s.groupMembers(ctx, req.SessionUuid, xcontext.GetUserUUID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	userBaseMap, err := s.userBaseMap(ctx, memberUuidsExcept(members, ""))
+	if err != nil {
+		return nil, err
+	}
+	resp := &ListGroupMembersResponse{
+		Members: make([]*GroupMember, 0, len(members)),
+	}
+	for _, member := range members {
+		item := &GroupMember{
+			UserUuid: member.UserUuid,
+			Role:     member.Role,
+			JoinTime: member.CreatedAt.Format("1月2日 15:04"),
+		}
+		if userBase, ok := userBaseMap[member.UserUuid]; ok {
+			item.UserName = userBase.Name
+			item.UserAvatar = userBase.Avatar
+		}
+		resp.Members = append(resp.Members, item)
+	}
+	return resp, nil
+}
+
+// 创建群聊，创建者为群主，其他成员为普通成员
+func (s *APIGatewayService) createGroup(ctx context.Context, creatorUuid string, name string, avatar string, userUuids []string) (string, []string, *Message, error) {
+	if len(creatorUuid) == 0 {
+		return "", nil, nil, errors.New("创建者不能为空")
+	}
+	memberUuids := uniqueUuids(append([]string{creatorUuid}, userUuids...))
+	if err := s.checkUsersExist(ctx, memberUuids[1:]); err != nil {
+		return "", nil, nil, err
+	}
+	if len(name) == 0 {
+		name = defaultGroupName
+	}
+	sessionUuid := uuid.New().String()
+	if err := s.MysqlClient.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		if err := s.SessionsModel.CreateSession(ctx, session, &model.Sessions{
+			Uuid:        sessionUuid,
+			Name:        name,
+			Avatar:      avatar,
+			SessionType: model.SessionTypeGroup,
+			Status:      model.SessionStatusActive,
+		}); err != nil {
+			return err
+		}
+		for i, memberUuid := range memberUuids {
+			role := int64(model.MemberRoleMember)
+			if i == 0 {
+				role = model.MemberRoleOwner
+			}
+			if err := s.SessionMembersModel.JoinSession(ctx, session, sessionUuid, memberUuid, role); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return "", nil, nil, err
+	}
+
+	creatorName := s.userNames(ctx, []string{creatorUuid})
+	content := fmt.Sprintf("%s 创建了群聊", creatorName)
+	if len(memberUuids) > 1 {
+		content = fmt.Sprintf("%s 创建了群聊并邀请 %s 加入", creatorName, s.userNames(ctx, memberUuids[1:]))
+	}
+	return sessionUuid, memberUuids, s.sendSystemMessage(ctx, sessionUuid, creatorUuid, content), nil
+}
+
+// 邀请用户加入群聊，返回实际加入的用户和当前成员，普通成员也可以邀请
+func (s *APIGatewayService) addGroupMembers(ctx context.Context, sessionUuid string, operatorUuid string, userUuids []string) ([]string, []string, *Message, error) {
+	_, members, _, err := s.groupMembers(ctx, sessionUuid, operatorUuid)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	memberUuids := memberUuidsExcept(members, "")
+	joinedUuids := make([]string, 0, len(userUuids))
+	for _, userUuid := range uniqueUuids(userUuids) {
+		if findMember(members, userUuid) != nil {
+			continue
+		}
+		joinedUuids = append(joinedUuids, userUuid)
+	}
+	if len(joinedUuids) == 0 {
+		return nil, memberUuids, nil, nil
+	}
+	if err := s.checkUsersExist(ctx, joinedUuids); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := s.MysqlClient.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		for _, userUuid := range joinedUuids {
+			if err := s.SessionMembersModel.JoinSession(ctx, session, sessionUuid, userUuid, model.MemberRoleMember); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, nil, nil, err
+	}
+	s.sessionMembersChanged(ctx, sessionUuid)
+
+	content := fmt.Sprintf("%s 邀请 %s 加入了群聊", s.userNames(ctx, []string{operatorUuid}), s.userNames(ctx, joinedUuids))
+	return joinedUuids, append(memberUuids, joinedUuids...), s.sendSystemMessage(ctx, sessionUuid, operatorUuid, content), nil
+}
+
+// 退出群聊，群主退出时转让群主，最后一个成员退出时解散群聊
+func (s *APIGatewayService) leaveGroup(ctx context.Context, sessionUuid string, userUuid string) ([]string, *Message, error) {
+	if _, _, _, err := s.groupMembers(ctx, sessionUuid, userUuid); err != nil {
+		return nil, nil, err
+	}
+	var newOwner *model.SessionMembers
+	var remainUuids []string
+	if err := s.MysqlClient.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		// 锁定成员后重新读取，同时退出的成员按提交顺序选择新群主和判断是否解散
+		members, err := s.SessionMembersModel.FindMembersForUpdate(ctx, session, sessionUuid)
+		if err != nil {
+			return err
+		}
+		operator := findMember(members, userUuid)
+		if operator == nil {
+			return errors.New("不是会话成员")
+		}
+		remainUuids = memberUuidsExcept(members, userUuid)
+		if len(remainUuids) == 0 {
+			return s.deleteGroup(ctx, session, sessionUuid)
+		}
+		if err := s.SessionMembersModel.LeaveSession(ctx, session, sessionUuid, userUuid); err != nil {
+			return err
+		}
+		if operator.Role == model.MemberRoleOwner {
+			newOwner = nextOwner(members, userUuid)
+			return s.SessionMembersModel.UpdateRole(ctx, session, sessionUuid, newOwner.UserUuid, model.MemberRoleOwner)
+		}
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+	s.sessionMembersChanged(ctx, sessionUuid)
+
+	userName := s.userNames(ctx, []string{userUuid})
+	content := fmt.Sprintf("%s 退出了群聊", userName)
+	if newOwner != nil {
+		content = fmt.Sprintf("%s 退出了群聊，%s 成为新群主", userName, s.userNames(ctx, []string{newOwner.UserUuid}))
+	}
+	return remainUuids, s.sendSystemMessage(ctx, sessionUuid, userUuid, content), nil
+}
+
+// 标记会话为已删除并移除所有成员
+func (s *APIGatewayService) dissolveGroup(ctx context.Context, sessionUuid string) error {
+	if err := s.MysqlClient.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		return s.deleteGroup(ctx, session, sessionUuid)
+	}); err != nil {
+		return err
+	}
+	s.sessionMembersChanged(ctx, sessionUuid)
+	return nil
+}
+
+// 在事务中标记会话为已删除并移除所有成员
+func (s *APIGatewayService) deleteGroup(ctx context.Context, session sqlx.Session, sessionUuid string) error {
+	if err := s.SessionsModel.UpdateStatus(ctx, session, sessionUuid, model.SessionStatusDeleted); err != nil {
+		return err
+	}
+	return s.SessionMembersModel.DeleteBySessionUuid(ctx, session, sessionUuid)
+}
+
+// 查询群聊及成员，并校验操作者是会话成员
+func (s *APIGatewayService) groupMembers(ctx context.Context, sessionUuid string, operatorUuid string) (*model.Sessions, []*model.SessionMembers, *model.SessionMembers, error) {
+	session, err := s.SessionsModel.FindByUuid(ctx, sessionUuid)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if session == nil || session.Status == model.SessionStatusDeleted {
+		return nil, nil, nil, errors.New("会话不存在")
+	}
+	if session.SessionType != model.SessionTypeGroup {
+		return nil, nil, nil, errors.New("单聊会话不支持修改成员")
+	}
+	members, err := s.SessionMembersModel.FindMembersBySessionUuid(ctx, sessionUuid)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	operator := findMember(members, operatorUuid)
+	if operator == nil {
+		return nil, nil, nil, errors.New("不是会话成员")
+	}
+	return session, members, operator, nil
+}
+
+// 校验被邀请的用户都存在，有不存在的用户时整体失败
+func (s *APIGatewayService) checkUsersExist(ctx context.Context, userUuids []string) error {
+	if len(userUuids) == 0 {
+		return nil
+	}
+	userBaseMap, err := s.userBaseMap(ctx, userUuids)
+	if err != nil {
+		return err
+	}
+	var missing []string
+	for _, userUuid := range userUuids {
+		if _, ok := userBaseMap[userUuid]; !ok {
+			missing = append(missing, userUuid)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("用户不存在: %s", strings.Join(missing, "、"))
+	}
+	return nil
+}
+
+// 角色值越小权限越高，群主和管理员只能管理比自己角色低的成员
+func canManage(operatorRole int64, targetRole int64) bool {
+	return operatorRole <= model.MemberRoleAdmin && operatorRole < targetRole
+}
+
+// 群主退出后的新群主：最早加入的管理员，没有管理员时为最早加入的成员
+func nextOwner(members []*model.SessionMembers, leavingUuid string) *model.SessionMembers {
+	var candidate *model.SessionMembers
+	for _, member := range members {
+		if member.UserUuid == leavingUuid {
+			continue
+		}
+		if member.Role == model.MemberRoleAdmin {
+			return member
+		}
+		if candidate == nil {
+			candidate = member
+		}
+	}
+	return candidate
+}
+
+func findMember(members []*model.SessionMembers, userUuid string) *model.SessionMembers {
+	for _, member := range members {
+		if member.UserUuid == userUuid {
+			return member
+		}
+	}
+	return nil
+}
+
+// 成员UUID列表，排除指定用户
+func memberUuidsExcept(members []*model.SessionMembers, userUuid string) []string {
+	result := make([]string, 0, len(members))
+	for _, member := range members {
+		if member.UserUuid != userUuid {
+			result = append(result, member.UserUuid)
+		}
+	}
+	return result
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"im/model"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestCanManage(t *testing.T) {
+	for _, tc := range []struct {
+		operator, target int64
+		expected         bool
+	}{
+		{model.MemberRoleOwner, model.MemberRoleAdmin, true},
+		{model.MemberRoleOwner, model.MemberRoleMember, true},
+		{model.MemberRoleAdmin, model.MemberRoleMember, true},
+		{model.MemberRoleAdmin, model.MemberRoleAdmin, false},
+		{model.MemberRoleAdmin, model.MemberRoleOwner, false},
+		{model.MemberRoleMember, model.MemberRoleMember, false},
+	} {
+		if got := canManage(tc.operator, tc.target); got != tc.expected {
+			t.Fatalf("canManage(%d, %d) expected %v, got %v", tc.operator, tc.target, tc.expected, got)
+		}
+	}
+}
+
+func TestNextOwner(t *testing.T) {
+	owner := &model.SessionMembers{UserUuid: "owner", Role: model.MemberRoleOwner}
+	member := &model.SessionMembers{UserUuid: "member", Role: model.MemberRoleMember}
+	admin := &model.SessionMembers{UserUuid: "admin", Role: model.MemberRoleAdmin}
+
+	// 优先转让给管理员
+	if got := nextOwner([]*model.SessionMembers{owner, member, admin}, "owner"); got != admin {
+		t.Fatalf("expected admin, got %v", got)
+	}
+	// 没有管理员时转让给最早加入的成员
+	if got := nextOwner([]*model.SessionMembers{owner, member}, "owner"); got != member {
+		t.Fatalf("expected member, got %v", got)
+	}
+	if got := nextOwner([]*model.SessionMembers{owner}, "owner"); got != nil {
+		t.Fatalf("expected no owner, got %v", got)
+	}
+}
+
+func TestGroupLifecycle(t *testing.T) {
+	s, store, messagesModel := newTestService(t)
+	for userUuid, name := range map[string]string{"owner": "群主", "user-a": "A", "user-b": "B", "user-c": "C"} {
+		store.addUser(userUuid, name)
+	}
+	roles := func(operatorUuid string, sessionUuid string) map[string]int64 {
+		t.Helper()
+		resp, err := s.ListGroupMembers(userContext(operatorUuid), &ListGroupMembersRequest{SessionUuid: sessionUuid})
+		if err != nil {
+			t.Fatalf("failed to list members: %v", err)
+		}
+		result := make(map[string]int64, len(resp.Members))
+		for _, member := range resp.Members {
+			result[member.UserUuid] = member.Role
+		}
+		return result
+	}
+
+	created, err := s.CreateGroup(userContext("owner"), &CreateGroupRequest{Name: "测试群", MemberUuids: []string{"user-a", "user-b", "user-a"}})
+	if err != nil {
+		t.Fatalf("failed to create group: %v", err)
+	}
+	sessionUuid := created.SessionUuid
+	if !slices.Equal(created.MemberUuids, []string{"owner", "user-a", "user-b"}) || created.Message.Content != "群主 创建了群聊并邀请 A、B 加入" {
+		t.Fatalf("unexpected create response: %v", created)
+	}
+
+	// 普通成员可以邀请，已是成员的用户被忽略
+	added, err := s.AddGroupMembers(userContext("user-a"), &AddGroupMembersRequest{SessionUuid: sessionUuid, UserUuids: []string{"user-b", "user-c"}})
+	if err != nil {
+		t.Fatalf("failed to add members: %v", err)
+	}
+	if !slices.Equal(added.JoinedUuids, []string{"user-c"}) || len(added.MemberUuids) != 4 {
+		t.Fatalf("unexpected add response: %v", added)
+	}
+
+	// 只有群主可以设置管理员
+	if _, err := s.SetGroupAdmin(userContext("user-a"), &SetGroupAdminRequest{SessionUuid: sessionUuid, UserUuid: "user-b", Admin: true}); err == nil {
+		t.Fatalf("expected member unable to set admin")
+	}
+	if _, err := s.SetGroupAdmin(userContext("owner"), &SetGroupAdminRequest{SessionUuid: sessionUuid, UserUuid: "user-a", Admin: true}); err != nil {
+		t.Fatalf("failed to set admin: %v", err)
+	}
+
+	// 管理员可以移除普通成员，不能移除群主
+	if _, err := s.RemoveGroupMember(userContext("user-a"), &RemoveGroupMemberRequest{SessionUuid: sessionUuid, UserUuid: "owner"}); err == nil {
+		t.Fatalf("expected admin unable to remove owner")
+	}
+	removed, err := s.RemoveGroupMember(userContext("user-a"), &RemoveGroupMemberRequest{SessionUuid: sessionUuid, UserUuid: "user-b"})
+	if err != nil {
+		t.Fatalf("failed to remove member: %v", err)
+	}
+	if slices.Contains(removed.MemberUuids, "user-b") || removed.Message.Content != "A 将 B 移出了群聊" {
+		t.Fatalf("unexpected remove response: %v", removed)
+	}
+
+	// 普通成员不能修改群资料
+	if _, err := s.UpdateGroup(userContext("user-c"), &UpdateGroupRequest{SessionUuid: sessionUuid, Name: "新群名"}); err == nil {
+		t.Fatalf("expected member unable to update group")
+	}
+	if _, err := s.UpdateGroup(userContext("user-a"), &UpdateGroupRequest{SessionUuid: sessionUuid, Name: "新群名"}); err != nil {
+		t.Fatalf("failed to update group: %v", err)
+	}
+	if session, _ := s.SessionsModel.FindByUuid(context.Background(), sessionUuid); session.Name != "新群名" {
+		t.Fatalf("unexpected session name: %s", session.Name)
+	}
+
+	// 群主退出后管理员成为群主
+	left, err := s.LeaveGroup(userContext("owner"), &LeaveGroupRequest{SessionUuid: sessionUuid})
+	if err != nil {
+		t.Fatalf("failed to leave group: %v", err)
+	}
+	if left.Message.Content != "群主 退出了群聊，A 成为新群主" {
+		t.Fatalf("unexpected leave response: %v", left)
+	}
+	expected := map[string]int64{"user-a": model.MemberRoleOwner, "user-c": model.MemberRoleMember}
+	if got := roles("user-c", sessionUuid); !maps.Equal(got, expected) {
+		t.Fatalf("expected roles %v, got %v", expected, got)
+	}
+
+	// 只有群主可以解散
+	if _, err := s.DissolveGroup(userContext("user-c"), &DissolveGroupRequest{SessionUuid: sessionUuid}); err == nil {
+		t.Fatalf("expected member unable to dissolve group")
+	}
+	dissolved, err := s.DissolveGroup(userContext("user-a"), &DissolveGroupRequest{SessionUuid: sessionUuid})
+	if err != nil {
+		t.Fatalf("failed to dissolve group: %v", err)
+	}
+	if !slices.Equal(dissolved.MemberUuids, []string{"user-a", "user-c"}) || dissolved.Message == nil {
+		t.Fatalf("unexpected dissolve response: %v", dissolved)
+	}
+	if _, err := s.ListGroupMembers(userContext("user-a"), &ListGroupMembersRequest{SessionUuid: sessionUuid}); err == nil {
+		t.Fatalf("expected dissolved group not found")
+	}
+
+	// 每次变更写入一条系统消息，序列号连续
+	for i, message := range messagesModel.messages {
+		if message.SeqId != int64(i+1) || message.MessageType != model.MessageTypeSystem {
+			t.Fatalf("unexpected system message %d: %v", i, message)
+		}
+	}
+	if len(messagesModel.messages) != 7 {
+		t.Fatalf("expected 7 system messages, got %d", len(messagesModel.messages))
+	}
+}
+
+func TestGroupSystemMessageFailure(t *testing.T) {
+	s, store, messagesModel := newTestService(t)
+	store.addUser("owner", "群主")
+	store.addUser("user-a", "A")
+	messagesModel.err = errors.New("mysql unavailable")
+
+	// 成员变更已提交，系统消息写入失败不影响结果
+	created, err := s.CreateGroup(userContext("owner"), &CreateGroupRequest{MemberUuids: []string{"user-a"}})
+	if err != nil {
+		t.Fatalf("failed to create group: %v", err)
+	}
+	if created.Message != nil {
+		t.Fatalf("expected no system message, got %v", created.Message)
+	}
+	left, err := s.LeaveGroup(userContext("user-a"), &LeaveGroupRequest{SessionUuid: created.SessionUuid})
+	if err != nil {
+		t.Fatalf("failed to leave group: %v", err)
+	}
+	if !slices.Equal(left.MemberUuids, []string{"owner"}) || left.Message != nil {
+		t.Fatalf("unexpected leave response: %v", left)
+	}
+}
+
+// 普通成员可以邀请已存在的用户，邀请不存在的用户时整体失败
+func TestAddGroupMembersPolicy(t *testing.T) {
+	s, store, _ := newTestService(t)
+	for userUuid, name := range map[string]string{"owner": "群主", "user-a": "A", "user-b": "B", "user-c": "C"} {
+		store.addUser(userUuid, name)
+	}
+	if _, err := s.CreateGroup(userContext("owner"), &CreateGroupRequest{MemberUuids: []string{"user-a", "ghost"}}); err == nil {
+		t.Fatalf("expected unknown user rejected on create")
+	}
+	if len(store.sessions) != 0 {
+		t.Fatalf("expected no group created, got %v", store.sessions)
+	}
+	created, err := s.CreateGroup(userContext("owner"), &CreateGroupRequest{MemberUuids: []string{"user-a"}})
+	if err != nil {
+		t.Fatalf("failed to create group: %v", err)
+	}
+
+	if _, err := s.AddGroupMembers(userContext("user-a"), &AddGroupMembersRequest{SessionUuid: created.SessionUuid, UserUuids: []string{"user-b", "ghost"}}); err == nil {
+		t.Fatalf("expected unknown user rejected")
+	}
+	added, err := s.AddGroupMembers(userContext("user-a"), &AddGroupMembersRequest{SessionUuid: created.SessionUuid, UserUuids: []string{"user-b"}})
+	if err != nil {
+		t.Fatalf("expected member able to invite, got %v", err)
+	}
+	if !slices.Equal(added.JoinedUuids, []string{"user-b"}) || added.Message.Content != "A 邀请 B 加入了群聊" {
+		t.Fatalf("unexpected add response: %v", added)
+	}
+
+	// 非成员不能邀请
+	if _, err := s.AddGroupMembers(userContext("user-c"), &AddGroupMembersRequest{SessionUuid: created.SessionUuid, UserUuids: []string{"user-c"}}); err == nil {
+		t.Fatalf("expected non-member unable to invite")
+	}
+}
+
+// 群主读取成员后其他成员退出，群主退出时基于最新成员解散群聊
+func TestLeaveGroupConcurrent(t *testing.T) {
+	s, store, _ := newTestService(t)
+	store.addUser("owner", "群主")
+	store.addUser("user-a", "A")
+	created, err := s.CreateGroup(userContext("owner"), &CreateGroupRequest{MemberUuids: []string{"user-a"}})
+	if err != nil {
+		t.Fatalf("failed to create group: %v", err)
+	}
+	membersModel := s.SessionMembersModel.(*memorySessionMembersModel)
+	membersModel.afterFindMembers = func() {
+		membersModel.afterFindMembers = nil
+		if _, err := s.LeaveGroup(userContext("user-a"), &LeaveGroupRequest{SessionUuid: created.SessionUuid}); err != nil {
+			t.Errorf("failed to leave group: %v", err)
+		}
+	}
+	left, err := s.LeaveGroup(userContext("owner"), &LeaveGroupRequest{SessionUuid: created.SessionUuid})
+	if err != nil {
+		t.Fatalf("failed to leave group: %v", err)
+	}
+	if len(left.MemberUuids) != 0 || left.Message.Content != "群主 退出了群聊" {
+		t.Fatalf("unexpected leave response: %v", left)
+	}
+	if session, _ := s.SessionsModel.FindByUuid(context.Background(), created.SessionUuid); session.Status != model.SessionStatusDeleted {
+		t.Fatalf("expected group dissolved, got %v", session)
+	}
+	if members, _ := membersModel.FindMembersBySessionUuid(context.Background(), created.SessionUuid); len(members) != 0 {
+		t.Fatalf("expected no members, got %v", members)
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"im/model"
+	"log/slog"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 计数器存在时自增，不存在时返回nil，由调用方从MySQL恢复
+var incrIfExistsScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return redis.call('INCR', KEYS[1])
+end
+return false
+`)
+
+// SeqAllocator 会话内严格递增的序列号分配器
+// 优先使用Redis INCR，计数器丢失时从MySQL最大序列号恢复，Redis不可用时降级为MySQL分配
+type SeqAllocator struct {
+	logger        *slog.Logger
+	redisClient   *redis.Client
+	messagesModel model.MessagesModel
+}
+
+func NewSeqAllocator(logger *slog.Logger, redisClient *redis.Client, messagesModel model.MessagesModel) *SeqAllocator {
+	return &SeqAllocator{
+		logger:        logger,
+		redisClient:   redisClient,
+		messagesModel: messagesModel,
+	}
+}
+
+func seqKey(sessionUuid string) string {
+	return fmt.Sprintf("im:seq:%s", sessionUuid)
+}
+
+// Next 分配会话的下一个序列号
+func (a *SeqAllocator) Next(ctx context.Context, sessionUuid string) (int64, error) {
+	key := seqKey(sessionUuid)
+	seq, err := incrIfExistsScript.Run(ctx, a.redisClient, []string{key}).Int64()
+	if err == nil {
+		return seq, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		a.logger.Error("failed to incr seq, fallback to mysql", "error", err, "session_uuid", sessionUuid)
+		return a.nextFromMysql(ctx, sessionUuid)
+	}
+
+	// 首次发送或Redis数据丢失，从MySQL恢复计数器
+	maxSeq, err := a.messagesModel.FindMaxSeqidBySessionUuid(ctx, sessionUuid)
+	if err != nil {
+		return 0, err
+	}
+	// 并发恢复时只有一个SETNX生效，其余直接在其基础上自增
+	if err := a.redisClient.SetNX(ctx, key, maxSeq, 0).Err(); err != nil {
+		a.logger.Error("failed to recover seq, fallback to mysql", "error", err, "session_uuid", sessionUuid)
+		return maxSeq + 1, nil
+	}
+	seq, err = a.redisClient.Incr(ctx, key).Result()
+	if err != nil {
+		a.logger.Error("failed to incr seq, fallback to mysql", "error", err, "session_uuid", sessionUuid)
+		return maxSeq + 1, nil
+	}
+	return seq, nil
+}
+
+// Reset 删除Redis计数器，下次分配时从MySQL重新恢复
+// 写入时序列号冲突说明计数器落后于MySQL，需要调用此方法
+func (a *SeqAllocator) Reset(ctx context.Context, sessionUuid string) error {
+	return a.redisClient.Del(ctx, seqKey(sessionUuid)).Err()
+}
+
+// 降级分配，并发时可能得到相同的序列号，由(session_uuid, seq_id)唯一索引兜底后重试
+func (a *SeqAllocator) nextFromMysql(ctx context.Context, sessionUuid string) (int64, error) {
+	maxSeq, err := a.messagesModel.FindMaxSeqidBySessionUuid(ctx, sessionUuid)
+	if err != nil {
+		return 0, err
+	}
+	return maxSeq + 1, nil
+}
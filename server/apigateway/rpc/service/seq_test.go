@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"im/model"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// 只实现最大序列号查询的消息模型
+type fakeMessagesModel struct {
+	model.MessagesModel
+	maxSeq int64
+}
+
+func (m *fakeMessagesModel) FindMaxSeqidBySessionUuid(ctx context.Context, sessionUuid string) (int64, error) {
+	return m.maxSeq, nil
+}
+
+func TestSeqAllocator(t *testing.T) {
+	ctx := context.Background()
+	redisServer := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: redisServer.Addr()})
+	defer redisClient.Close()
+	messagesModel := &fakeMessagesModel{maxSeq: 10}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	allocator := NewSeqAllocator(logger, redisClient, messagesModel)
+
+	next := func(sessionUuid string, expected int64) {
+		t.Helper()
+		seq, err := allocator.Next(ctx, sessionUuid)
+		if err != nil {
+			t.Fatalf("failed to allocate seq: %v", err)
+		}
+		if seq != expected {
+			t.Fatalf("expected seq %d, got %d", expected, seq)
+		}
+	}
+
+	// 计数器不存在时从MySQL最大序列号恢复
+	next("session-1", 11)
+	next("session-1", 12)
+	next("session-1", 13)
+
+	// Redis数据丢失后重新恢复
+	redisServer.FlushAll()
+	messagesModel.maxSeq = 13
+	next("session-1", 14)
+
+	// 写入冲突后重置计数器
+	messagesModel.maxSeq = 20
+	if err := allocator.Reset(ctx, "session-1"); err != nil {
+		t.Fatalf("failed to reset: %v", err)
+	}
+	next("session-1", 21)
+
+	// Redis不可用时降级为MySQL分配
+	redisServer.Close()
+	next("session-1", 21)
+}
@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"im/model"
 	"im/pkg/config"
+	"im/pkg/event"
 	"im/pkg/jwt"
 	"im/pkg/password"
 	"im/pkg/xcontext"
 	"im/pkg/xstrings"
 	"log"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
@@ -31,8 +34,14 @@ type APIGatewayService struct {
 	UserInfoModel       model.UserInfoModel
 	SessionMembersModel model.SessionMembersModel
 	UserIdentityModel   model.UserIdentityModel
+	ContactsModel       model.ContactsModel
+	FriendRequestsModel model.FriendRequestsModel
+	SeqAllocator        *SeqAllocator
 }
 
+// 序列号冲突时的最大重试次数
+const maxSeqConflictRetry = 3
+
 func NewAPIGatewayService(ctx context.Context, logger *slog.Logger, conf *config.APIGatewayConfig) *APIGatewayService {
 	mysqlClient, err := sqlx.NewConn(sqlx.SqlConf{
 		DataSource: fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", conf.MysqlConfig.Username, conf.MysqlConfig.Password, conf.MysqlConfig.Addr, conf.MysqlConfig.DB),
@@ -48,6 +57,7 @@ func NewAPIGatewayService(ctx context.Context, logger *slog.Logger, conf *config
 		Password: conf.RedisConfig.Password,
 		DB:       conf.RedisConfig.DB,
 	})
+	messagesModel := model.NewMessagesModel(mysqlClient)
 	return &APIGatewayService{
 		ctx:                 ctx,
 		logger:              logger,
@@ -56,10 +66,13 @@ func NewAPIGatewayService(ctx context.Context, logger *slog.Logger, conf *config
 		RedisClient:         redisClient,
 		SessionsModel:       model.NewSessionsModel(mysqlClient),
 		UserBaseModel:       model.NewUserBaseModel(mysqlClient),
-		MessagesModel:       model.NewMessagesModel(mysqlClient),
+		MessagesModel:       messagesModel,
 		SessionMembersModel: model.NewSessionMembersModel(mysqlClient),
 		UserIdentityModel:   model.NewUserIdentityModel(mysqlClient),
 		UserInfoModel:       model.NewUserInfoModel(mysqlClient),
+		ContactsModel:       model.NewContactsModel(mysqlClient),
+		FriendRequestsModel: model.NewFriendRequestsModel(mysqlClient),
+		SeqAllocator:        NewSeqAllocator(logger, redisClient, messagesModel),
 	}
 }
 
@@ -72,6 +85,14 @@ func (s *APIGatewayService) SessionList(ctx context.Context, req *SessionListReq
 	if err != nil {
 		return nil, err
 	}
+	contacts, err := s.ContactsModel.FindContactsByUserUuid(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	remarkMap := make(map[string]string, len(contacts))
+	for _, contact := range contacts {
+		remarkMap[contact.ContactUuid] = contact.Remark
+	}
 	for _, sessionUuid := range sessionList {
 		session, err := s.SessionsModel.FindByUuid(ctx, sessionUuid)
 		if err != nil {
@@ -111,6 +132,10 @@ func (s *APIGatewayService) SessionList(ctx context.Context, req *SessionListReq
 			}
 			sessionItem.Name = otherMemberBase.Name
 			sessionItem.Avatar = otherMemberBase.Avatar
+			// 单聊优先显示联系人备注
+			if remark := remarkMap[otherMember]; len(remark) > 0 {
+				sessionItem.Name = remark
+			}
 		}
 
 		if latestMessage != nil {
@@ -126,7 +151,14 @@ func (s *APIGatewayService) HistoryMessage(ctx context.Context, req *HistoryMess
 	messageListResponse := &HistoryMessageResponse{
 		Messages: make([]*Message, 0),
 	}
-	messageList, err := s.MessagesModel.FindMessagesBySeqidGreaterThan(ctx, req.SessionUuid, req.StartSeqid)
+	var messageList []*model.Messages
+	var err error
+	if req.EndSeqid > 0 {
+		// 客户端发现序列号空洞时只拉取缺失的区间
+		messageList, err = s.MessagesModel.FindMessagesBySeqidRange(ctx, req.SessionUuid, req.StartSeqid, req.EndSeqid)
+	} else {
+		messageList, err = s.MessagesModel.FindMessagesBySeqidGreaterThan(ctx, req.SessionUuid, req.StartSeqid)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -250,10 +282,6 @@ func (s *APIGatewayService) Register(ctx context.Context, req *RegisterRequest)
 		return nil, err
 	}
 
-	if err := s.initSession(ctx, userUuid); err != nil {
-		return nil, err
-	}
-
 	token, _, err := jwt.GenerateToken(userIdentityNew.UserUuid, 3600, nil)
 	if err != nil {
 		return nil, err
@@ -268,46 +296,6 @@ func (s *APIGatewayService) Register(ctx context.Context, req *RegisterRequest)
 	}, nil
 }
 
-// 为当前用户与所有好友建立单聊会话
-func (s *APIGatewayService) initSession(ctx context.Context, userUUID string) error {
-	userBases, err := s.UserBaseModel.FindAll(ctx)
-	if err != nil {
-		return err
-	}
-	if err := s.MysqlClient.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
-		for _, userBase := range userBases {
-			if userBase.Uuid == userUUID {
-				continue
-			}
-			sessionUUID := uuid.New().String()
-
-			err := s.SessionsModel.CreateSession(ctx, session, &model.Sessions{
-				Uuid:        sessionUUID,
-				Name:        "",
-				Avatar:      "",
-				SessionType: model.SessionTypeSingle,
-				Status:      model.SessionStatusActive,
-			})
-			if err != nil {
-				return err
-			}
-			err = s.SessionMembersModel.JoinSession(ctx, session, sessionUUID, userUUID)
-			if err != nil {
-				return err
-			}
-			err = s.SessionMembersModel.JoinSession(ctx, session, sessionUUID, userBase.Uuid)
-			if err != nil {
-				return err
-			}
-		}
-		return nil
-	}); err != nil {
-		return err
-	}
-
-	return nil
-}
-
 func (s *APIGatewayService) GetSessionUserList(ctx context.Context, req *GetSessionUserListRequest) (*GetSessionUserListResponse, error) {
 	sessionUserListResponse := &GetSessionUserListResponse{
 		Users: make([]*SessionUserListItem, 0),
@@ -334,20 +322,73 @@ func (s *APIGatewayService) GetSessionUserList(ctx context.Context, req *GetSess
 }
 
 func (s *APIGatewayService) SendMessage(ctx context.Context, req *SendMessageRequest) (*SendMessageResponse, error) {
+	// 客户端重试时client_msg_id不变，直接返回首次写入的消息
+	if len(req.ClientMsgId) > 0 {
+		message, err := s.MessagesModel.FindBySenderUuidAndClientMsgId(ctx, req.SenderUuid, req.ClientMsgId)
+		if err != nil {
+			return nil, err
+		}
+		if message != nil {
+			return &SendMessageResponse{
+				MessageUuid: message.Uuid,
+				SeqId:       message.SeqId,
+				Duplicate:   true,
+			}, nil
+		}
+	}
 	message := &model.Messages{
 		Uuid:        uuid.New().String(),
 		SessionUuid: req.SessionUuid,
 		SenderUuid:  req.SenderUuid,
 		MessageType: req.MessageType,
 		Status:      model.MessageStatusSent,
-		SeqId:       req.SeqId,
 		Content:     req.Payload,
+		ClientMsgId: req.ClientMsgId,
 	}
-	if _, err := s.MessagesModel.Insert(ctx, message); err != nil {
-		return nil, err
+	// 未携带客户端消息ID时使用消息UUID，同一发送者的多条消息不会违反唯一索引
+	if len(message.ClientMsgId) == 0 {
+		message.ClientMsgId = message.Uuid
+	}
+	for i := 0; ; i++ {
+		seqId, err := s.SeqAllocator.Next(ctx, req.SessionUuid)
+		if err != nil {
+			return nil, err
+		}
+		message.SeqId = seqId
+		_, err = s.MessagesModel.Insert(ctx, message)
+		if err == nil {
+			break
+		}
+		// 并发重试时由唯一索引兜底
+		if model.IsDuplicateClientMsgId(err) && len(req.ClientMsgId) > 0 {
+			existing, findErr := s.MessagesModel.FindBySenderUuidAndClientMsgId(ctx, req.SenderUuid, req.ClientMsgId)
+			if findErr != nil {
+				return nil, findErr
+			}
+			if existing != nil {
+				return &SendMessageResponse{
+					MessageUuid: existing.Uuid,
+					SeqId:       existing.SeqId,
+					Duplicate:   true,
+				}, nil
+			}
+		}
+		// 只有序列号冲突时重试，其他唯一索引冲突重试也无法成功
+		if !model.IsDuplicateSeqId(err) {
+			return nil, err
+		}
+		// 序列号冲突，计数器落后于MySQL，重置后重新分配
+		if i+1 >= maxSeqConflictRetry {
+			return nil, err
+		}
+		s.logger.Warn("seq conflict, reset allocator", "session_uuid", req.SessionUuid, "seq_id", seqId)
+		if err := s.SeqAllocator.Reset(ctx, req.SessionUuid); err != nil {
+			s.logger.Error("failed to reset seq allocator", "error", err, "session_uuid", req.SessionUuid)
+		}
 	}
 	return &SendMessageResponse{
 		MessageUuid: message.Uuid,
+		SeqId:       message.SeqId,
 	}, nil
 }
 
@@ -374,3 +415,108 @@ func (s *APIGatewayService) GetUserInfo(ctx context.Context, req *GetUserInfoReq
 	}
 	return resp, nil
 }
+
+func (s *APIGatewayService) CreateSession(ctx context.Context, req *CreateSessionRequest) (*CreateSessionResponse, error) {
+	sessionUuid, memberUuids, message, err := s.createGroup(ctx, xcontext.GetUserUUID(ctx), req.Name, "", req.MemberUuids)
+	if err != nil {
+		return nil, err
+	}
+	return &CreateSessionResponse{
+		SessionUuid: sessionUuid,
+		MemberUuids: memberUuids,
+		Message:     message,
+	}, nil
+}
+
+func (s *APIGatewayService) JoinSession(ctx context.Context, req *JoinSessionRequest) (*JoinSessionResponse, error) {
+	joinedUuids, memberUuids, message, err := s.addGroupMembers(ctx, req.SessionUuid, xcontext.GetUserUUID(ctx), req.UserUuids)
+	if err != nil {
+		return nil, err
+	}
+	return &JoinSessionResponse{
+		JoinedUuids: joinedUuids,
+		MemberUuids: memberUuids,
+		Message:     message,
+	}, nil
+}
+
+func (s *APIGatewayService) LeaveSession(ctx context.Context, req *LeaveSessionRequest) (*LeaveSessionResponse, error) {
+	memberUuids, message, err := s.leaveGroup(ctx, req.SessionUuid, xcontext.GetUserUUID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &LeaveSessionResponse{
+		MemberUuids: memberUuids,
+		Message:     message,
+	}, nil
+}
+
+// 通知网关丢弃会话成员缓存，失败时网关缓存会在过期后自动刷新
+func (s *APIGatewayService) sessionMembersChanged(ctx context.Context, sessionUuid string) {
+	if err := event.PublishSessionMembersChanged(ctx, s.RedisClient, sessionUuid); err != nil {
+		s.logger.Error("failed to publish session members changed", "error", err, "session_uuid", sessionUuid)
+	}
+}
+
+// 写入系统消息，与普通消息共用会话序列号
+// 在成员变更提交后调用，写入失败不影响已提交的变更，只记录日志并返回nil
+func (s *APIGatewayService) sendSystemMessage(ctx context.Context, sessionUuid string, operatorUuid string, content string) *Message {
+	resp, err := s.SendMessage(ctx, &SendMessageRequest{
+		SessionUuid: sessionUuid,
+		Payload:     content,
+		SenderUuid:  operatorUuid,
+		MessageType: model.MessageTypeSystem,
+		Timestamp:   time.Now().Unix(),
+	})
+	if err != nil {
+		s.logger.Error("failed to send system message", "error", err, "session_uuid", sessionUuid, "content", content)
+		return nil
+	}
+	return &Message{
+		MessageUuid: resp.MessageUuid,
+		SessionUuid: sessionUuid,
+		SeqId:       resp.SeqId,
+		MessageType: model.MessageTypeSystem,
+		Content:     content,
+		SenderUuid:  operatorUuid,
+		SendTime:    time.Now().Format("1月2日 15:04"),
+	}
+}
+
+// 按顺序拼接用户名称，查不到的用户使用UUID，用于系统消息，查询失败时全部使用UUID
+func (s *APIGatewayService) userNames(ctx context.Context, userUuids []string) string {
+	if len(userUuids) == 0 {
+		return ""
+	}
+	userBases, err := s.UserBaseModel.FindByUuids(ctx, userUuids)
+	if err != nil {
+		s.logger.Error("failed to find user names", "error", err)
+	}
+	nameMap := make(map[string]string, len(userBases))
+	for _, userBase := range userBases {
+		nameMap[userBase.Uuid] = userBase.Name
+	}
+	names := make([]string, 0, len(userUuids))
+	for _, userUuid := range userUuids {
+		if name, ok := nameMap[userUuid]; ok {
+			names = append(names, name)
+			continue
+		}
+		names = append(names, userUuid)
+	}
+	return strings.Join(names, "、")
+}
+
+// 去重并保持原有顺序，忽略空值
+func uniqueUuids(uuids []string) []string {
+	seen := make(map[string]struct{}, len(uuids))
+	result := make([]string, 0, len(uuids))
+	for _, id := range uuids {
+		if _, ok := seen[id]; ok || len(id) == 0 {
+			continue
+		}
+		seen[id] = struct{}{}
+		result = append(result, id)
+	}
+	return result
+}
@@ -0,0 +1,407 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"im/model"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-sql-driver/mysql"
+	"github.com/redis/go-redis/v9"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// 内存实现的消息模型，和MySQL一样校验唯一索引
+type memoryMessagesModel struct {
+	model.MessagesModel
+	mu       sync.Mutex
+	messages []*model.Messages
+	inserts  int
+	err      error // 不为空时写入失败
+}
+
+func duplicateKeyError(value string, key string) error {
+	return &mysql.MySQLError{Number: 1062, Message: fmt.Sprintf("Duplicate entry '%s' for key 'messages.%s'", value, key)}
+}
+
+func (m *memoryMessagesModel) Insert(ctx context.Context, data *model.Messages) (sql.Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inserts++
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, message := range m.messages {
+		if message.SenderUuid == data.SenderUuid && message.ClientMsgId == data.ClientMsgId {
+			return nil, duplicateKeyError(data.SenderUuid+"-"+data.ClientMsgId, "idx_messages_sender_uuid_client_msg_id")
+		}
+		if message.SessionUuid == data.SessionUuid && message.SeqId == data.SeqId {
+			return nil, duplicateKeyError(fmt.Sprintf("%s-%d", data.SessionUuid, data.SeqId), "idx_messages_session_uuid_seq_id")
+		}
+	}
+	message := *data
+	m.messages = append(m.messages, &message)
+	return nil, nil
+}
+
+func (m *memoryMessagesModel) FindBySenderUuidAndClientMsgId(ctx context.Context, senderUuid string, clientMsgId string) (*model.Messages, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, message := range m.messages {
+		if message.SenderUuid == senderUuid && message.ClientMsgId == clientMsgId {
+			return message, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryMessagesModel) FindMaxSeqidBySessionUuid(ctx context.Context, sessionUuid string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	maxSeq := int64(0)
+	for _, message := range m.messages {
+		if message.SessionUuid == sessionUuid {
+			maxSeq = max(maxSeq, message.SeqId)
+		}
+	}
+	return maxSeq, nil
+}
+
+// 内存实现的会话、成员、用户和联系人数据，模型的假实现共用
+type memoryStore struct {
+	mu       sync.Mutex
+	nextId   int64
+	sessions map[string]*model.Sessions
+	members  []*model.SessionMembers
+	users    map[string]*model.UserBase
+	contacts []*model.Contacts
+	requests []*model.FriendRequests
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		sessions: make(map[string]*model.Sessions),
+		users:    make(map[string]*model.UserBase),
+	}
+}
+
+func (m *memoryStore) addUser(userUuid string, name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users[userUuid] = &model.UserBase{Uuid: userUuid, Name: name, Status: 1}
+}
+
+// 复制当前数据，事务失败时恢复
+func (m *memoryStore) snapshot() *memoryStore {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := &memoryStore{nextId: m.nextId, sessions: make(map[string]*model.Sessions, len(m.sessions))}
+	for uuid, session := range m.sessions {
+		copied := *session
+		snapshot.sessions[uuid] = &copied
+	}
+	for _, member := range m.members {
+		copied := *member
+		snapshot.members = append(snapshot.members, &copied)
+	}
+	for _, contact := range m.contacts {
+		copied := *contact
+		snapshot.contacts = append(snapshot.contacts, &copied)
+	}
+	for _, request := range m.requests {
+		copied := *request
+		snapshot.requests = append(snapshot.requests, &copied)
+	}
+	return snapshot
+}
+
+func (m *memoryStore) restore(snapshot *memoryStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextId, m.sessions, m.members = snapshot.nextId, snapshot.sessions, snapshot.members
+	m.contacts, m.requests = snapshot.contacts, snapshot.requests
+}
+
+// 事务串行执行，返回错误时回滚
+type memoryConn struct {
+	sqlx.SqlConn
+	mu    sync.Mutex
+	store *memoryStore
+}
+
+func (c *memoryConn) TransactCtx(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	snapshot := c.store.snapshot()
+	if err := fn(ctx, nil); err != nil {
+		c.store.restore(snapshot)
+		return err
+	}
+	return nil
+}
+
+type memorySessionsModel struct {
+	model.SessionsModel
+	*memoryStore
+}
+
+func (m *memorySessionsModel) FindByUuid(ctx context.Context, uuid string) (*model.Sessions, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if session, ok := m.sessions[uuid]; ok {
+		copied := *session
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *memorySessionsModel) CreateSession(ctx context.Context, tx sqlx.Session, session *model.Sessions) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextId++
+	copied := *session
+	copied.Id = m.nextId
+	m.sessions[session.Uuid] = &copied
+	return nil
+}
+
+func (m *memorySessionsModel) UpdateProfile(ctx context.Context, uuid string, name string, avatar string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if session, ok := m.sessions[uuid]; ok {
+		session.Name, session.Avatar = name, avatar
+	}
+	return nil
+}
+
+func (m *memorySessionsModel) UpdateStatus(ctx context.Context, tx sqlx.Session, uuid string, status int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if session, ok := m.sessions[uuid]; ok {
+		session.Status = status
+	}
+	return nil
+}
+
+type memorySessionMembersModel struct {
+	model.SessionMembersModel
+	*memoryStore
+	beforeFindSingle func() // 查询单聊会话前调用，用于构造并发
+	afterFindMembers func() // 不加锁查询成员后调用，用于构造并发
+}
+
+func (m *memorySessionMembersModel) JoinSession(ctx context.Context, tx sqlx.Session, sessionUuid string, userUuid string, role int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, member := range m.members {
+		if member.SessionUuid == sessionUuid && member.UserUuid == userUuid {
+			return &mysql.MySQLError{Number: 1062, Message: "Duplicate entry for key 'session_members.idx_session_members_user_uuid_session_uuid'"}
+		}
+	}
+	m.nextId++
+	m.members = append(m.members, &model.SessionMembers{Id: m.nextId, SessionUuid: sessionUuid, UserUuid: userUuid, Role: role, CreatedAt: time.Now()})
+	return nil
+}
+
+func (m *memorySessionMembersModel) FindMembersBySessionUuid(ctx context.Context, sessionUuid string) ([]*model.SessionMembers, error) {
+	members, err := m.FindMembersForUpdate(ctx, nil, sessionUuid)
+	if m.afterFindMembers != nil {
+		m.afterFindMembers()
+	}
+	return members, err
+}
+
+// 事务串行执行，加锁读取与普通读取相同
+func (m *memorySessionMembersModel) FindMembersForUpdate(ctx context.Context, tx sqlx.Session, sessionUuid string) ([]*model.SessionMembers, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var members []*model.SessionMembers
+	for _, member := range m.members {
+		if member.SessionUuid == sessionUuid {
+			copied := *member
+			members = append(members, &copied)
+		}
+	}
+	return members, nil
+}
+
+func (m *memorySessionMembersModel) LeaveSession(ctx context.Context, tx sqlx.Session, sessionUuid string, userUuid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.members = slices.DeleteFunc(m.members, func(member *model.SessionMembers) bool {
+		return member.SessionUuid == sessionUuid && member.UserUuid == userUuid
+	})
+	return nil
+}
+
+func (m *memorySessionMembersModel) UpdateRole(ctx context.Context, tx sqlx.Session, sessionUuid string, userUuid string, role int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, member := range m.members {
+		if member.SessionUuid == sessionUuid && member.UserUuid == userUuid {
+			member.Role = role
+		}
+	}
+	return nil
+}
+
+func (m *memorySessionMembersModel) DeleteBySessionUuid(ctx context.Context, tx sqlx.Session, sessionUuid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.members = slices.DeleteFunc(m.members, func(member *model.SessionMembers) bool {
+		return member.SessionUuid == sessionUuid
+	})
+	return nil
+}
+
+func (m *memorySessionMembersModel) FindSingleSession(ctx context.Context, userUuid string, otherUuid string) (string, error) {
+	if m.beforeFindSingle != nil {
+		m.beforeFindSingle()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var found *model.Sessions
+	for _, session := range m.sessions {
+		if session.SessionType != model.SessionTypeSingle || session.Status != model.SessionStatusActive {
+			continue
+		}
+		if m.isMember(session.Uuid, userUuid) && m.isMember(session.Uuid, otherUuid) && (found == nil || session.Id < found.Id) {
+			found = session
+		}
+	}
+	if found == nil {
+		return "", nil
+	}
+	return found.Uuid, nil
+}
+
+func (m *memoryStore) isMember(sessionUuid string, userUuid string) bool {
+	for _, member := range m.members {
+		if member.SessionUuid == sessionUuid && member.UserUuid == userUuid {
+			return true
+		}
+	}
+	return false
+}
+
+type memoryUserBaseModel struct {
+	model.UserBaseModel
+	*memoryStore
+}
+
+func (m *memoryUserBaseModel) FindByUuid(ctx context.Context, uuid string) (*model.UserBase, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if user, ok := m.users[uuid]; ok {
+		copied := *user
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *memoryUserBaseModel) FindByUuids(ctx context.Context, uuids []string) ([]*model.UserBase, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var users []*model.UserBase
+	for _, uuid := range uuids {
+		if user, ok := m.users[uuid]; ok {
+			copied := *user
+			users = append(users, &copied)
+		}
+	}
+	return users, nil
+}
+
+// 使用内存模型的服务，直接调用RPC方法
+func newTestService(t *testing.T) (*APIGatewayService, *memoryStore, *memoryMessagesModel) {
+	t.Helper()
+	s, messagesModel := newTestMessageService(t)
+	store := newMemoryStore()
+	s.MysqlClient = &memoryConn{store: store}
+	s.SessionsModel = &memorySessionsModel{memoryStore: store}
+	s.SessionMembersModel = &memorySessionMembersModel{memoryStore: store}
+	s.UserBaseModel = &memoryUserBaseModel{memoryStore: store}
+	s.ContactsModel = &memoryContactsModel{memoryStore: store}
+	s.FriendRequestsModel = &memoryFriendRequestsModel{memoryStore: store}
+	return s, store, messagesModel
+}
+
+// 模拟jwt拦截器写入的调用者
+func userContext(userUuid string) context.Context {
+	return context.WithValue(context.Background(), "user_uuid", userUuid)
+}
+
+func newTestMessageService(t *testing.T) (*APIGatewayService, *memoryMessagesModel) {
+	t.Helper()
+	redisServer := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: redisServer.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	messagesModel := &memoryMessagesModel{}
+	return &APIGatewayService{
+		logger:        logger,
+		RedisClient:   redisClient,
+		MessagesModel: messagesModel,
+		SeqAllocator:  NewSeqAllocator(logger, redisClient, messagesModel),
+	}, messagesModel
+}
+
+func TestSendSystemMessage(t *testing.T) {
+	ctx := context.Background()
+	s, messagesModel := newTestMessageService(t)
+
+	// 同一操作者连续发送系统消息
+	first := s.sendSystemMessage(ctx, "session-1", "owner", "owner 创建了群聊")
+	second := s.sendSystemMessage(ctx, "session-1", "owner", "owner 邀请 user-a 加入群聊")
+	if first == nil || second == nil {
+		t.Fatalf("failed to send system messages: %v %v", first, second)
+	}
+	if first.SeqId != 1 || second.SeqId != 2 || first.MessageUuid == second.MessageUuid {
+		t.Fatalf("unexpected system messages: %v %v", first, second)
+	}
+	if len(messagesModel.messages) != 2 || messagesModel.inserts != 2 {
+		t.Fatalf("expected 2 messages inserted once each, got %d messages %d inserts", len(messagesModel.messages), messagesModel.inserts)
+	}
+}
+
+func TestSendMessageDuplicateClientMsgId(t *testing.T) {
+	ctx := context.Background()
+	s, messagesModel := newTestMessageService(t)
+	req := &SendMessageRequest{SessionUuid: "session-1", SenderUuid: "user-a", Payload: "hello", ClientMsgId: "client-msg-1", MessageType: model.MessageTypeText}
+
+	first, err := s.SendMessage(ctx, req)
+	if err != nil {
+		t.Fatalf("failed to send message: %v", err)
+	}
+	retry, err := s.SendMessage(ctx, req)
+	if err != nil {
+		t.Fatalf("failed to resend message: %v", err)
+	}
+	if !retry.Duplicate || retry.MessageUuid != first.MessageUuid || retry.SeqId != first.SeqId {
+		t.Fatalf("expected duplicate of %v, got %v", first, retry)
+	}
+
+	// 未携带客户端消息ID的消息各自生成，不会互相冲突
+	inserts := messagesModel.inserts
+	one, err := s.SendMessage(ctx, &SendMessageRequest{SessionUuid: "session-1", SenderUuid: "user-b", Payload: "1"})
+	if err != nil {
+		t.Fatalf("failed to send message: %v", err)
+	}
+	two, err := s.SendMessage(ctx, &SendMessageRequest{SessionUuid: "session-1", SenderUuid: "user-b", Payload: "2"})
+	if err != nil {
+		t.Fatalf("failed to send message without client msg id: %v", err)
+	}
+	if one.Duplicate || two.Duplicate || one.MessageUuid == two.MessageUuid || two.SeqId != one.SeqId+1 {
+		t.Fatalf("expected 2 messages, got %v %v", one, two)
+	}
+	if messagesModel.inserts != inserts+2 {
+		t.Fatalf("expected no retry, got %d inserts", messagesModel.inserts-inserts)
+	}
+}
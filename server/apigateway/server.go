@@ -3,7 +3,10 @@ package apigateway
 import (
 	"context"
 	"im/pkg/config"
+	"im/pkg/graceful"
 	"im/pkg/grpcmiddreware"
+	"im/pkg/registry"
+	"im/pkg/xtls"
 	"log"
 	"log/slog"
 	"net"
@@ -19,7 +22,8 @@ import (
 //go:generate protoc --go_out=rpc/service --go-grpc_out=rpc/service rpc/service/apigateway.proto
 
 func Run() {
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 	conf := config.NewConf().GetAPIGatewayConfig()
 
 	level := slog.LevelInfo
@@ -37,17 +41,62 @@ func Run() {
 	fr.Start()
 	defer fr.Stop()
 
+	creds, err := xtls.ServerOption(ctx, conf.TLSConfig, logger)
+	if err != nil {
+		log.Fatalf("failed to load tls: %v", err)
+	}
 	server := grpc.NewServer(
-		grpc.ChainUnaryInterceptor(grpcmiddreware.MonitorUnaryInterceptor(ctx,fr,logger), grpcmiddreware.TraceUnaryInterceptor(), grpcmiddreware.LogUnaryInterceptor(logger), grpcmiddreware.JwtUnaryInterceptor(logger)),
+		creds,
+		grpc.ChainUnaryInterceptor(grpcmiddreware.MonitorUnaryInterceptor(ctx, fr, logger), grpcmiddreware.TraceUnaryInterceptor(), grpcmiddreware.LogUnaryInterceptor(logger), grpcmiddreware.JwtUnaryInterceptor(logger)),
 	)
 
 	service.RegisterAPIGatewayServer(server, service.NewAPIGatewayService(ctx, logger, conf))
+	healthServer := graceful.RegisterHealth(server)
 	listener, err := net.Listen("tcp", conf.Addr)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 	logger.Info("api gateway server listening", "address", conf.Addr)
-	if err := server.Serve(listener); err != nil {
-		log.Fatalf("failed to serve: %v", err)
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			log.Fatalf("failed to serve: %v", err)
+		}
+	}()
+
+	dialOption, err := xtls.DialOption(ctx, conf.TLSConfig, logger)
+	if err != nil {
+		log.Fatalf("failed to load tls: %v", err)
+	}
+	advertiseAddr, err := registry.AdvertiseAddr(conf.AdvertiseAddr, conf.Addr)
+	if err != nil {
+		log.Fatalf("failed to get advertise address: %v", err)
+	}
+	discoveryClient, err := registry.Dial(conf.DiscoveryEndpoint, dialOption)
+	if err != nil {
+		log.Fatalf("failed to create discovery client: %v", err)
+	}
+	registrar, err := registry.Start(ctx, discoveryClient, logger, registry.Instance{
+		Name:     registry.ServiceAPIGateway,
+		Addr:     advertiseAddr,
+		Weight:   conf.Weight,
+		Metadata: registry.Metadata(conf.Zone, conf.Version),
+	})
+	if err != nil {
+		log.Fatalf("failed to start registrar: %v", err)
+	}
+
+	signalCtx, stop := graceful.NotifyContext(ctx)
+	defer stop()
+	<-signalCtx.Done()
+	logger.Info("api gateway server shutting down")
+	healthServer.Shutdown()
+	timeout := time.Duration(conf.ShutdownTimeout) * time.Second
+	deregisterCtx, deregisterCancel := context.WithTimeout(context.Background(), timeout)
+	defer deregisterCancel()
+	if err := registrar.Deregister(deregisterCtx); err != nil {
+		logger.Error("failed to deregister", "error", err)
+	}
+	if !graceful.StopGRPC(server, timeout) {
+		logger.Warn("api gateway server shutdown timeout, force stopped")
 	}
 }
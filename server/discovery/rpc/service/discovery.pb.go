@@ -21,11 +21,116 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type HealthStatus int32
+
+const (
+	HealthStatus_HEALTH_STATUS_UNKNOWN   HealthStatus = 0 // 未检查或未开启健康检查 视为健康
+	HealthStatus_HEALTH_STATUS_HEALTHY   HealthStatus = 1
+	HealthStatus_HEALTH_STATUS_UNHEALTHY HealthStatus = 2 // 连续检查失败 GetServiceIP不再选择
+)
+
+// Enum value maps for HealthStatus.
+var (
+	HealthStatus_name = map[int32]string{
+		0: "HEALTH_STATUS_UNKNOWN",
+		1: "HEALTH_STATUS_HEALTHY",
+		2: "HEALTH_STATUS_UNHEALTHY",
+	}
+	HealthStatus_value = map[string]int32{
+		"HEALTH_STATUS_UNKNOWN":   0,
+		"HEALTH_STATUS_HEALTHY":   1,
+		"HEALTH_STATUS_UNHEALTHY": 2,
+	}
+)
+
+func (x HealthStatus) Enum() *HealthStatus {
+	p := new(HealthStatus)
+	*p = x
+	return p
+}
+
+func (x HealthStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HealthStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_rpc_service_discovery_proto_enumTypes[0].Descriptor()
+}
+
+func (HealthStatus) Type() protoreflect.EnumType {
+	return &file_rpc_service_discovery_proto_enumTypes[0]
+}
+
+func (x HealthStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HealthStatus.Descriptor instead.
+func (HealthStatus) EnumDescriptor() ([]byte, []int) {
+	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{0}
+}
+
+type EventType int32
+
+const (
+	EventType_EVENT_TYPE_SNAPSHOT EventType = 0 // 全部实例 订阅时发送
+	EventType_EVENT_TYPE_ADD      EventType = 1 // 新增实例
+	EventType_EVENT_TYPE_REMOVE   EventType = 2 // 实例注销或租约过期
+	EventType_EVENT_TYPE_UPDATE   EventType = 3 // 已有实例重新注册或健康状态变化
+)
+
+// Enum value maps for EventType.
+var (
+	EventType_name = map[int32]string{
+		0: "EVENT_TYPE_SNAPSHOT",
+		1: "EVENT_TYPE_ADD",
+		2: "EVENT_TYPE_REMOVE",
+		3: "EVENT_TYPE_UPDATE",
+	}
+	EventType_value = map[string]int32{
+		"EVENT_TYPE_SNAPSHOT": 0,
+		"EVENT_TYPE_ADD":      1,
+		"EVENT_TYPE_REMOVE":   2,
+		"EVENT_TYPE_UPDATE":   3,
+	}
+)
+
+func (x EventType) Enum() *EventType {
+	p := new(EventType)
+	*p = x
+	return p
+}
+
+func (x EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_rpc_service_discovery_proto_enumTypes[1].Descriptor()
+}
+
+func (EventType) Type() protoreflect.EnumType {
+	return &file_rpc_service_discovery_proto_enumTypes[1]
+}
+
+func (x EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventType.Descriptor instead.
+func (EventType) EnumDescriptor() ([]byte, []int) {
+	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{1}
+}
+
 type RegisterRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
-	ServiceName    string                 `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`          // 服务名称
-	ServiceAddress string                 `protobuf:"bytes,2,opt,name=service_address,json=serviceAddress,proto3" json:"service_address,omitempty"` // 服务地址
-	ServicePort    string                 `protobuf:"bytes,3,opt,name=service_port,json=servicePort,proto3" json:"service_port,omitempty"`          // 服务端口
+	ServiceName    string                 `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`                                                  // 服务名称
+	ServiceAddress string                 `protobuf:"bytes,2,opt,name=service_address,json=serviceAddress,proto3" json:"service_address,omitempty"`                                         // 服务地址
+	ServicePort    string                 `protobuf:"bytes,3,opt,name=service_port,json=servicePort,proto3" json:"service_port,omitempty"`                                                  // 服务端口
+	Ttl            int64                  `protobuf:"varint,4,opt,name=ttl,proto3" json:"ttl,omitempty"`                                                                                    // 租约有效期 秒 0表示使用服务端默认值
+	Weight         int32                  `protobuf:"varint,5,opt,name=weight,proto3" json:"weight,omitempty"`                                                                              // 权重 0表示1
+	Load           int64                  `protobuf:"varint,6,opt,name=load,proto3" json:"load,omitempty"`                                                                                  // 当前负载 如长连接数
+	Metadata       map[string]string      `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 实例元数据 如zone、version、其他协议端口
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -81,8 +186,38 @@ func (x *RegisterRequest) GetServicePort() string {
 	return ""
 }
 
+func (x *RegisterRequest) GetTtl() int64 {
+	if x != nil {
+		return x.Ttl
+	}
+	return 0
+}
+
+func (x *RegisterRequest) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *RegisterRequest) GetLoad() int64 {
+	if x != nil {
+		return x.Load
+	}
+	return 0
+}
+
+func (x *RegisterRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
 type RegisterResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	LeaseId       string                 `protobuf:"bytes,1,opt,name=lease_id,json=leaseId,proto3" json:"lease_id,omitempty"` // 租约ID
+	Ttl           int64                  `protobuf:"varint,2,opt,name=ttl,proto3" json:"ttl,omitempty"`                       // 租约有效期 秒
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -117,6 +252,116 @@ func (*RegisterResponse) Descriptor() ([]byte, []int) {
 	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{1}
 }
 
+func (x *RegisterResponse) GetLeaseId() string {
+	if x != nil {
+		return x.LeaseId
+	}
+	return ""
+}
+
+func (x *RegisterResponse) GetTtl() int64 {
+	if x != nil {
+		return x.Ttl
+	}
+	return 0
+}
+
+type KeepAliveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LeaseId       string                 `protobuf:"bytes,1,opt,name=lease_id,json=leaseId,proto3" json:"lease_id,omitempty"` // 租约ID
+	Load          int64                  `protobuf:"varint,2,opt,name=load,proto3" json:"load,omitempty"`                     // 当前负载 如长连接数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeepAliveRequest) Reset() {
+	*x = KeepAliveRequest{}
+	mi := &file_rpc_service_discovery_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeepAliveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeepAliveRequest) ProtoMessage() {}
+
+func (x *KeepAliveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_discovery_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeepAliveRequest.ProtoReflect.Descriptor instead.
+func (*KeepAliveRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *KeepAliveRequest) GetLeaseId() string {
+	if x != nil {
+		return x.LeaseId
+	}
+	return ""
+}
+
+func (x *KeepAliveRequest) GetLoad() int64 {
+	if x != nil {
+		return x.Load
+	}
+	return 0
+}
+
+type KeepAliveResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ttl           int64                  `protobuf:"varint,1,opt,name=ttl,proto3" json:"ttl,omitempty"` // 租约有效期 秒
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeepAliveResponse) Reset() {
+	*x = KeepAliveResponse{}
+	mi := &file_rpc_service_discovery_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeepAliveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeepAliveResponse) ProtoMessage() {}
+
+func (x *KeepAliveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_discovery_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeepAliveResponse.ProtoReflect.Descriptor instead.
+func (*KeepAliveResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *KeepAliveResponse) GetTtl() int64 {
+	if x != nil {
+		return x.Ttl
+	}
+	return 0
+}
+
 type DeregisterRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	ServiceName    string                 `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`          // 服务名称
@@ -128,7 +373,7 @@ type DeregisterRequest struct {
 
 func (x *DeregisterRequest) Reset() {
 	*x = DeregisterRequest{}
-	mi := &file_rpc_service_discovery_proto_msgTypes[2]
+	mi := &file_rpc_service_discovery_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -140,7 +385,7 @@ func (x *DeregisterRequest) String() string {
 func (*DeregisterRequest) ProtoMessage() {}
 
 func (x *DeregisterRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_service_discovery_proto_msgTypes[2]
+	mi := &file_rpc_service_discovery_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -153,7 +398,7 @@ func (x *DeregisterRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeregisterRequest.ProtoReflect.Descriptor instead.
 func (*DeregisterRequest) Descriptor() ([]byte, []int) {
-	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{2}
+	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *DeregisterRequest) GetServiceName() string {
@@ -185,7 +430,7 @@ type DeregisterResponse struct {
 
 func (x *DeregisterResponse) Reset() {
 	*x = DeregisterResponse{}
-	mi := &file_rpc_service_discovery_proto_msgTypes[3]
+	mi := &file_rpc_service_discovery_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -197,7 +442,7 @@ func (x *DeregisterResponse) String() string {
 func (*DeregisterResponse) ProtoMessage() {}
 
 func (x *DeregisterResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_service_discovery_proto_msgTypes[3]
+	mi := &file_rpc_service_discovery_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -210,7 +455,7 @@ func (x *DeregisterResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeregisterResponse.ProtoReflect.Descriptor instead.
 func (*DeregisterResponse) Descriptor() ([]byte, []int) {
-	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{3}
+	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{5}
 }
 
 type GetServiceRequest struct {
@@ -222,7 +467,7 @@ type GetServiceRequest struct {
 
 func (x *GetServiceRequest) Reset() {
 	*x = GetServiceRequest{}
-	mi := &file_rpc_service_discovery_proto_msgTypes[4]
+	mi := &file_rpc_service_discovery_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -234,7 +479,7 @@ func (x *GetServiceRequest) String() string {
 func (*GetServiceRequest) ProtoMessage() {}
 
 func (x *GetServiceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_service_discovery_proto_msgTypes[4]
+	mi := &file_rpc_service_discovery_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -247,7 +492,7 @@ func (x *GetServiceRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetServiceRequest.ProtoReflect.Descriptor instead.
 func (*GetServiceRequest) Descriptor() ([]byte, []int) {
-	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{4}
+	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GetServiceRequest) GetServiceName() string {
@@ -266,7 +511,7 @@ type GetServiceResponse struct {
 
 func (x *GetServiceResponse) Reset() {
 	*x = GetServiceResponse{}
-	mi := &file_rpc_service_discovery_proto_msgTypes[5]
+	mi := &file_rpc_service_discovery_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -278,7 +523,7 @@ func (x *GetServiceResponse) String() string {
 func (*GetServiceResponse) ProtoMessage() {}
 
 func (x *GetServiceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_service_discovery_proto_msgTypes[5]
+	mi := &file_rpc_service_discovery_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -291,7 +536,7 @@ func (x *GetServiceResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetServiceResponse.ProtoReflect.Descriptor instead.
 func (*GetServiceResponse) Descriptor() ([]byte, []int) {
-	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{5}
+	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetServiceResponse) GetServiceInfo() []*ServiceInfo {
@@ -303,15 +548,17 @@ func (x *GetServiceResponse) GetServiceInfo() []*ServiceInfo {
 
 type GetServiceIPRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ServiceName   string                 `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"` // 服务名称
-	ClientKey     string                 `protobuf:"bytes,2,opt,name=client_key,json=clientKey,proto3" json:"client_key,omitempty"`       // 客户端唯一标识，用于负载均衡
+	ServiceName   string                 `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`                                            // 服务名称
+	ClientKey     string                 `protobuf:"bytes,2,opt,name=client_key,json=clientKey,proto3" json:"client_key,omitempty"`                                                  // 客户端唯一标识，用于负载均衡和灰度分流
+	Zone          string                 `protobuf:"bytes,3,opt,name=zone,proto3" json:"zone,omitempty"`                                                                             // 客户端所在可用区，优先选择同可用区的实例
+	Match         map[string]string      `protobuf:"bytes,4,rep,name=match,proto3" json:"match,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 只选择元数据全部匹配的实例
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetServiceIPRequest) Reset() {
 	*x = GetServiceIPRequest{}
-	mi := &file_rpc_service_discovery_proto_msgTypes[6]
+	mi := &file_rpc_service_discovery_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -323,7 +570,7 @@ func (x *GetServiceIPRequest) String() string {
 func (*GetServiceIPRequest) ProtoMessage() {}
 
 func (x *GetServiceIPRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_service_discovery_proto_msgTypes[6]
+	mi := &file_rpc_service_discovery_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -336,7 +583,7 @@ func (x *GetServiceIPRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetServiceIPRequest.ProtoReflect.Descriptor instead.
 func (*GetServiceIPRequest) Descriptor() ([]byte, []int) {
-	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{6}
+	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *GetServiceIPRequest) GetServiceName() string {
@@ -353,17 +600,32 @@ func (x *GetServiceIPRequest) GetClientKey() string {
 	return ""
 }
 
+func (x *GetServiceIPRequest) GetZone() string {
+	if x != nil {
+		return x.Zone
+	}
+	return ""
+}
+
+func (x *GetServiceIPRequest) GetMatch() map[string]string {
+	if x != nil {
+		return x.Match
+	}
+	return nil
+}
+
 type GetServiceIPResponse struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
-	ServiceAddress string                 `protobuf:"bytes,1,opt,name=service_address,json=serviceAddress,proto3" json:"service_address,omitempty"` // 服务地址
-	ServicePort    string                 `protobuf:"bytes,2,opt,name=service_port,json=servicePort,proto3" json:"service_port,omitempty"`          // 服务端口
+	ServiceAddress string                 `protobuf:"bytes,1,opt,name=service_address,json=serviceAddress,proto3" json:"service_address,omitempty"`                                         // 服务地址
+	ServicePort    string                 `protobuf:"bytes,2,opt,name=service_port,json=servicePort,proto3" json:"service_port,omitempty"`                                                  // 服务端口
+	Metadata       map[string]string      `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 实例元数据
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
 func (x *GetServiceIPResponse) Reset() {
 	*x = GetServiceIPResponse{}
-	mi := &file_rpc_service_discovery_proto_msgTypes[7]
+	mi := &file_rpc_service_discovery_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -375,7 +637,7 @@ func (x *GetServiceIPResponse) String() string {
 func (*GetServiceIPResponse) ProtoMessage() {}
 
 func (x *GetServiceIPResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_service_discovery_proto_msgTypes[7]
+	mi := &file_rpc_service_discovery_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -388,7 +650,7 @@ func (x *GetServiceIPResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetServiceIPResponse.ProtoReflect.Descriptor instead.
 func (*GetServiceIPResponse) Descriptor() ([]byte, []int) {
-	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{7}
+	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetServiceIPResponse) GetServiceAddress() string {
@@ -405,6 +667,13 @@ func (x *GetServiceIPResponse) GetServicePort() string {
 	return ""
 }
 
+func (x *GetServiceIPResponse) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
 type ReadyRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -413,7 +682,7 @@ type ReadyRequest struct {
 
 func (x *ReadyRequest) Reset() {
 	*x = ReadyRequest{}
-	mi := &file_rpc_service_discovery_proto_msgTypes[8]
+	mi := &file_rpc_service_discovery_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -425,7 +694,7 @@ func (x *ReadyRequest) String() string {
 func (*ReadyRequest) ProtoMessage() {}
 
 func (x *ReadyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_service_discovery_proto_msgTypes[8]
+	mi := &file_rpc_service_discovery_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -438,19 +707,21 @@ func (x *ReadyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReadyRequest.ProtoReflect.Descriptor instead.
 func (*ReadyRequest) Descriptor() ([]byte, []int) {
-	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{8}
+	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{10}
 }
 
 type ReadyResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Ready         bool                   `protobuf:"varint,1,opt,name=ready,proto3" json:"ready,omitempty"`
+	Stale         bool                   `protobuf:"varint,2,opt,name=stale,proto3" json:"stale,omitempty"`                       // 存储不可用，返回的实例是最后一次同步成功时的数据
+	LastSync      int64                  `protobuf:"varint,3,opt,name=last_sync,json=lastSync,proto3" json:"last_sync,omitempty"` // 最后一次同步成功的时间 毫秒时间戳
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ReadyResponse) Reset() {
 	*x = ReadyResponse{}
-	mi := &file_rpc_service_discovery_proto_msgTypes[9]
+	mi := &file_rpc_service_discovery_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -462,7 +733,7 @@ func (x *ReadyResponse) String() string {
 func (*ReadyResponse) ProtoMessage() {}
 
 func (x *ReadyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_service_discovery_proto_msgTypes[9]
+	mi := &file_rpc_service_discovery_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -475,7 +746,7 @@ func (x *ReadyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReadyResponse.ProtoReflect.Descriptor instead.
 func (*ReadyResponse) Descriptor() ([]byte, []int) {
-	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{9}
+	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *ReadyResponse) GetReady() bool {
@@ -485,17 +756,35 @@ func (x *ReadyResponse) GetReady() bool {
 	return false
 }
 
+func (x *ReadyResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *ReadyResponse) GetLastSync() int64 {
+	if x != nil {
+		return x.LastSync
+	}
+	return 0
+}
+
 type ServiceInfo struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
-	ServiceAddress string                 `protobuf:"bytes,1,opt,name=service_address,json=serviceAddress,proto3" json:"service_address,omitempty"` // 服务地址
-	ServicePort    string                 `protobuf:"bytes,2,opt,name=service_port,json=servicePort,proto3" json:"service_port,omitempty"`          // 服务端口
+	ServiceAddress string                 `protobuf:"bytes,1,opt,name=service_address,json=serviceAddress,proto3" json:"service_address,omitempty"`                                         // 服务地址
+	ServicePort    string                 `protobuf:"bytes,2,opt,name=service_port,json=servicePort,proto3" json:"service_port,omitempty"`                                                  // 服务端口
+	Weight         int32                  `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`                                                                              // 权重
+	Load           int64                  `protobuf:"varint,4,opt,name=load,proto3" json:"load,omitempty"`                                                                                  // 最近一次续约时上报的负载
+	Metadata       map[string]string      `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 实例元数据
+	Health         HealthStatus           `protobuf:"varint,6,opt,name=health,proto3,enum=discovery.HealthStatus" json:"health,omitempty"`                                                  // 发现服务主动健康检查的结果
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ServiceInfo) Reset() {
 	*x = ServiceInfo{}
-	mi := &file_rpc_service_discovery_proto_msgTypes[10]
+	mi := &file_rpc_service_discovery_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -507,7 +796,7 @@ func (x *ServiceInfo) String() string {
 func (*ServiceInfo) ProtoMessage() {}
 
 func (x *ServiceInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_service_discovery_proto_msgTypes[10]
+	mi := &file_rpc_service_discovery_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -520,7 +809,7 @@ func (x *ServiceInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServiceInfo.ProtoReflect.Descriptor instead.
 func (*ServiceInfo) Descriptor() ([]byte, []int) {
-	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{10}
+	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *ServiceInfo) GetServiceAddress() string {
@@ -537,16 +826,162 @@ func (x *ServiceInfo) GetServicePort() string {
 	return ""
 }
 
+func (x *ServiceInfo) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *ServiceInfo) GetLoad() int64 {
+	if x != nil {
+		return x.Load
+	}
+	return 0
+}
+
+func (x *ServiceInfo) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *ServiceInfo) GetHealth() HealthStatus {
+	if x != nil {
+		return x.Health
+	}
+	return HealthStatus_HEALTH_STATUS_UNKNOWN
+}
+
+type WatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceName   string                 `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"` // 服务名称
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_rpc_service_discovery_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_discovery_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WatchRequest) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+type WatchEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=discovery.EventType" json:"type,omitempty"`        // 事件类型
+	ServiceName   string                 `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"` // 服务名称
+	ServiceInfo   []*ServiceInfo         `protobuf:"bytes,3,rep,name=service_info,json=serviceInfo,proto3" json:"service_info,omitempty"` // 快照时为全部实例，其他事件为变更的实例
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEvent) Reset() {
+	*x = WatchEvent{}
+	mi := &file_rpc_service_discovery_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEvent) ProtoMessage() {}
+
+func (x *WatchEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_discovery_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEvent.ProtoReflect.Descriptor instead.
+func (*WatchEvent) Descriptor() ([]byte, []int) {
+	return file_rpc_service_discovery_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *WatchEvent) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_EVENT_TYPE_SNAPSHOT
+}
+
+func (x *WatchEvent) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *WatchEvent) GetServiceInfo() []*ServiceInfo {
+	if x != nil {
+		return x.ServiceInfo
+	}
+	return nil
+}
+
 var File_rpc_service_discovery_proto protoreflect.FileDescriptor
 
 const file_rpc_service_discovery_proto_rawDesc = "" +
 	"\n" +
-	"\x1brpc/service/discovery.proto\x12\tdiscovery\"\x80\x01\n" +
+	"\x1brpc/service/discovery.proto\x12\tdiscovery\"\xc1\x02\n" +
 	"\x0fRegisterRequest\x12!\n" +
 	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x12'\n" +
 	"\x0fservice_address\x18\x02 \x01(\tR\x0eserviceAddress\x12!\n" +
-	"\fservice_port\x18\x03 \x01(\tR\vservicePort\"\x12\n" +
-	"\x10RegisterResponse\"\x82\x01\n" +
+	"\fservice_port\x18\x03 \x01(\tR\vservicePort\x12\x10\n" +
+	"\x03ttl\x18\x04 \x01(\x03R\x03ttl\x12\x16\n" +
+	"\x06weight\x18\x05 \x01(\x05R\x06weight\x12\x12\n" +
+	"\x04load\x18\x06 \x01(\x03R\x04load\x12D\n" +
+	"\bmetadata\x18\a \x03(\v2(.discovery.RegisterRequest.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"?\n" +
+	"\x10RegisterResponse\x12\x19\n" +
+	"\blease_id\x18\x01 \x01(\tR\aleaseId\x12\x10\n" +
+	"\x03ttl\x18\x02 \x01(\x03R\x03ttl\"A\n" +
+	"\x10KeepAliveRequest\x12\x19\n" +
+	"\blease_id\x18\x01 \x01(\tR\aleaseId\x12\x12\n" +
+	"\x04load\x18\x02 \x01(\x03R\x04load\"%\n" +
+	"\x11KeepAliveResponse\x12\x10\n" +
+	"\x03ttl\x18\x01 \x01(\x03R\x03ttl\"\x82\x01\n" +
 	"\x11DeregisterRequest\x12!\n" +
 	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x12'\n" +
 	"\x0fservice_address\x18\x02 \x01(\tR\x0eserviceAddress\x12!\n" +
@@ -555,28 +990,65 @@ const file_rpc_service_discovery_proto_rawDesc = "" +
 	"\x11GetServiceRequest\x12!\n" +
 	"\fservice_name\x18\x01 \x01(\tR\vserviceName\"O\n" +
 	"\x12GetServiceResponse\x129\n" +
-	"\fservice_info\x18\x01 \x03(\v2\x16.discovery.ServiceInfoR\vserviceInfo\"W\n" +
+	"\fservice_info\x18\x01 \x03(\v2\x16.discovery.ServiceInfoR\vserviceInfo\"\xe6\x01\n" +
 	"\x13GetServiceIPRequest\x12!\n" +
 	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x12\x1d\n" +
 	"\n" +
-	"client_key\x18\x02 \x01(\tR\tclientKey\"b\n" +
+	"client_key\x18\x02 \x01(\tR\tclientKey\x12\x12\n" +
+	"\x04zone\x18\x03 \x01(\tR\x04zone\x12?\n" +
+	"\x05match\x18\x04 \x03(\v2).discovery.GetServiceIPRequest.MatchEntryR\x05match\x1a8\n" +
+	"\n" +
+	"MatchEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xea\x01\n" +
 	"\x14GetServiceIPResponse\x12'\n" +
 	"\x0fservice_address\x18\x01 \x01(\tR\x0eserviceAddress\x12!\n" +
-	"\fservice_port\x18\x02 \x01(\tR\vservicePort\"\x0e\n" +
-	"\fReadyRequest\"%\n" +
+	"\fservice_port\x18\x02 \x01(\tR\vservicePort\x12I\n" +
+	"\bmetadata\x18\x03 \x03(\v2-.discovery.GetServiceIPResponse.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x0e\n" +
+	"\fReadyRequest\"X\n" +
 	"\rReadyResponse\x12\x14\n" +
-	"\x05ready\x18\x01 \x01(\bR\x05ready\"Y\n" +
+	"\x05ready\x18\x01 \x01(\bR\x05ready\x12\x14\n" +
+	"\x05stale\x18\x02 \x01(\bR\x05stale\x12\x1b\n" +
+	"\tlast_sync\x18\x03 \x01(\x03R\blastSync\"\xb5\x02\n" +
 	"\vServiceInfo\x12'\n" +
 	"\x0fservice_address\x18\x01 \x01(\tR\x0eserviceAddress\x12!\n" +
-	"\fservice_port\x18\x02 \x01(\tR\vservicePort2\xf3\x02\n" +
+	"\fservice_port\x18\x02 \x01(\tR\vservicePort\x12\x16\n" +
+	"\x06weight\x18\x03 \x01(\x05R\x06weight\x12\x12\n" +
+	"\x04load\x18\x04 \x01(\x03R\x04load\x12@\n" +
+	"\bmetadata\x18\x05 \x03(\v2$.discovery.ServiceInfo.MetadataEntryR\bmetadata\x12/\n" +
+	"\x06health\x18\x06 \x01(\x0e2\x17.discovery.HealthStatusR\x06health\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"1\n" +
+	"\fWatchRequest\x12!\n" +
+	"\fservice_name\x18\x01 \x01(\tR\vserviceName\"\x94\x01\n" +
+	"\n" +
+	"WatchEvent\x12(\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x14.discovery.EventTypeR\x04type\x12!\n" +
+	"\fservice_name\x18\x02 \x01(\tR\vserviceName\x129\n" +
+	"\fservice_info\x18\x03 \x03(\v2\x16.discovery.ServiceInfoR\vserviceInfo*a\n" +
+	"\fHealthStatus\x12\x19\n" +
+	"\x15HEALTH_STATUS_UNKNOWN\x10\x00\x12\x19\n" +
+	"\x15HEALTH_STATUS_HEALTHY\x10\x01\x12\x1b\n" +
+	"\x17HEALTH_STATUS_UNHEALTHY\x10\x02*f\n" +
+	"\tEventType\x12\x17\n" +
+	"\x13EVENT_TYPE_SNAPSHOT\x10\x00\x12\x12\n" +
+	"\x0eEVENT_TYPE_ADD\x10\x01\x12\x15\n" +
+	"\x11EVENT_TYPE_REMOVE\x10\x02\x12\x15\n" +
+	"\x11EVENT_TYPE_UPDATE\x10\x032\xf6\x03\n" +
 	"\tDiscovery\x12C\n" +
-	"\bRegister\x12\x1a.discovery.RegisterRequest\x1a\x1b.discovery.RegisterResponse\x12I\n" +
+	"\bRegister\x12\x1a.discovery.RegisterRequest\x1a\x1b.discovery.RegisterResponse\x12F\n" +
+	"\tKeepAlive\x12\x1b.discovery.KeepAliveRequest\x1a\x1c.discovery.KeepAliveResponse\x12I\n" +
 	"\n" +
 	"Deregister\x12\x1c.discovery.DeregisterRequest\x1a\x1d.discovery.DeregisterResponse\x12I\n" +
 	"\n" +
 	"GetService\x12\x1c.discovery.GetServiceRequest\x1a\x1d.discovery.GetServiceResponse\x12O\n" +
 	"\fGetServiceIP\x12\x1e.discovery.GetServiceIPRequest\x1a\x1f.discovery.GetServiceIPResponse\x12:\n" +
-	"\x05Ready\x12\x17.discovery.ReadyRequest\x1a\x18.discovery.ReadyResponseB\fZ\n" +
+	"\x05Ready\x12\x17.discovery.ReadyRequest\x1a\x18.discovery.ReadyResponse\x129\n" +
+	"\x05Watch\x12\x17.discovery.WatchRequest\x1a\x15.discovery.WatchEvent0\x01B\fZ\n" +
 	"./;serviceb\x06proto3"
 
 var (
@@ -591,37 +1063,59 @@ func file_rpc_service_discovery_proto_rawDescGZIP() []byte {
 	return file_rpc_service_discovery_proto_rawDescData
 }
 
-var file_rpc_service_discovery_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_rpc_service_discovery_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_rpc_service_discovery_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
 var file_rpc_service_discovery_proto_goTypes = []any{
-	(*RegisterRequest)(nil),      // 0: discovery.RegisterRequest
-	(*RegisterResponse)(nil),     // 1: discovery.RegisterResponse
-	(*DeregisterRequest)(nil),    // 2: discovery.DeregisterRequest
-	(*DeregisterResponse)(nil),   // 3: discovery.DeregisterResponse
-	(*GetServiceRequest)(nil),    // 4: discovery.GetServiceRequest
-	(*GetServiceResponse)(nil),   // 5: discovery.GetServiceResponse
-	(*GetServiceIPRequest)(nil),  // 6: discovery.GetServiceIPRequest
-	(*GetServiceIPResponse)(nil), // 7: discovery.GetServiceIPResponse
-	(*ReadyRequest)(nil),         // 8: discovery.ReadyRequest
-	(*ReadyResponse)(nil),        // 9: discovery.ReadyResponse
-	(*ServiceInfo)(nil),          // 10: discovery.ServiceInfo
+	(HealthStatus)(0),            // 0: discovery.HealthStatus
+	(EventType)(0),               // 1: discovery.EventType
+	(*RegisterRequest)(nil),      // 2: discovery.RegisterRequest
+	(*RegisterResponse)(nil),     // 3: discovery.RegisterResponse
+	(*KeepAliveRequest)(nil),     // 4: discovery.KeepAliveRequest
+	(*KeepAliveResponse)(nil),    // 5: discovery.KeepAliveResponse
+	(*DeregisterRequest)(nil),    // 6: discovery.DeregisterRequest
+	(*DeregisterResponse)(nil),   // 7: discovery.DeregisterResponse
+	(*GetServiceRequest)(nil),    // 8: discovery.GetServiceRequest
+	(*GetServiceResponse)(nil),   // 9: discovery.GetServiceResponse
+	(*GetServiceIPRequest)(nil),  // 10: discovery.GetServiceIPRequest
+	(*GetServiceIPResponse)(nil), // 11: discovery.GetServiceIPResponse
+	(*ReadyRequest)(nil),         // 12: discovery.ReadyRequest
+	(*ReadyResponse)(nil),        // 13: discovery.ReadyResponse
+	(*ServiceInfo)(nil),          // 14: discovery.ServiceInfo
+	(*WatchRequest)(nil),         // 15: discovery.WatchRequest
+	(*WatchEvent)(nil),           // 16: discovery.WatchEvent
+	nil,                          // 17: discovery.RegisterRequest.MetadataEntry
+	nil,                          // 18: discovery.GetServiceIPRequest.MatchEntry
+	nil,                          // 19: discovery.GetServiceIPResponse.MetadataEntry
+	nil,                          // 20: discovery.ServiceInfo.MetadataEntry
 }
 var file_rpc_service_discovery_proto_depIdxs = []int32{
-	10, // 0: discovery.GetServiceResponse.service_info:type_name -> discovery.ServiceInfo
-	0,  // 1: discovery.Discovery.Register:input_type -> discovery.RegisterRequest
-	2,  // 2: discovery.Discovery.Deregister:input_type -> discovery.DeregisterRequest
-	4,  // 3: discovery.Discovery.GetService:input_type -> discovery.GetServiceRequest
-	6,  // 4: discovery.Discovery.GetServiceIP:input_type -> discovery.GetServiceIPRequest
-	8,  // 5: discovery.Discovery.Ready:input_type -> discovery.ReadyRequest
-	1,  // 6: discovery.Discovery.Register:output_type -> discovery.RegisterResponse
-	3,  // 7: discovery.Discovery.Deregister:output_type -> discovery.DeregisterResponse
-	5,  // 8: discovery.Discovery.GetService:output_type -> discovery.GetServiceResponse
-	7,  // 9: discovery.Discovery.GetServiceIP:output_type -> discovery.GetServiceIPResponse
-	9,  // 10: discovery.Discovery.Ready:output_type -> discovery.ReadyResponse
-	6,  // [6:11] is the sub-list for method output_type
-	1,  // [1:6] is the sub-list for method input_type
-	1,  // [1:1] is the sub-list for extension type_name
-	1,  // [1:1] is the sub-list for extension extendee
-	0,  // [0:1] is the sub-list for field type_name
+	17, // 0: discovery.RegisterRequest.metadata:type_name -> discovery.RegisterRequest.MetadataEntry
+	14, // 1: discovery.GetServiceResponse.service_info:type_name -> discovery.ServiceInfo
+	18, // 2: discovery.GetServiceIPRequest.match:type_name -> discovery.GetServiceIPRequest.MatchEntry
+	19, // 3: discovery.GetServiceIPResponse.metadata:type_name -> discovery.GetServiceIPResponse.MetadataEntry
+	20, // 4: discovery.ServiceInfo.metadata:type_name -> discovery.ServiceInfo.MetadataEntry
+	0,  // 5: discovery.ServiceInfo.health:type_name -> discovery.HealthStatus
+	1,  // 6: discovery.WatchEvent.type:type_name -> discovery.EventType
+	14, // 7: discovery.WatchEvent.service_info:type_name -> discovery.ServiceInfo
+	2,  // 8: discovery.Discovery.Register:input_type -> discovery.RegisterRequest
+	4,  // 9: discovery.Discovery.KeepAlive:input_type -> discovery.KeepAliveRequest
+	6,  // 10: discovery.Discovery.Deregister:input_type -> discovery.DeregisterRequest
+	8,  // 11: discovery.Discovery.GetService:input_type -> discovery.GetServiceRequest
+	10, // 12: discovery.Discovery.GetServiceIP:input_type -> discovery.GetServiceIPRequest
+	12, // 13: discovery.Discovery.Ready:input_type -> discovery.ReadyRequest
+	15, // 14: discovery.Discovery.Watch:input_type -> discovery.WatchRequest
+	3,  // 15: discovery.Discovery.Register:output_type -> discovery.RegisterResponse
+	5,  // 16: discovery.Discovery.KeepAlive:output_type -> discovery.KeepAliveResponse
+	7,  // 17: discovery.Discovery.Deregister:output_type -> discovery.DeregisterResponse
+	9,  // 18: discovery.Discovery.GetService:output_type -> discovery.GetServiceResponse
+	11, // 19: discovery.Discovery.GetServiceIP:output_type -> discovery.GetServiceIPResponse
+	13, // 20: discovery.Discovery.Ready:output_type -> discovery.ReadyResponse
+	16, // 21: discovery.Discovery.Watch:output_type -> discovery.WatchEvent
+	15, // [15:22] is the sub-list for method output_type
+	8,  // [8:15] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_rpc_service_discovery_proto_init() }
@@ -634,13 +1128,14 @@ func file_rpc_service_discovery_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_service_discovery_proto_rawDesc), len(file_rpc_service_discovery_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   11,
+			NumEnums:      2,
+			NumMessages:   19,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_rpc_service_discovery_proto_goTypes,
 		DependencyIndexes: file_rpc_service_discovery_proto_depIdxs,
+		EnumInfos:         file_rpc_service_discovery_proto_enumTypes,
 		MessageInfos:      file_rpc_service_discovery_proto_msgTypes,
 	}.Build()
 	File_rpc_service_discovery_proto = out.File
@@ -20,18 +20,22 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	Discovery_Register_FullMethodName     = "/discovery.Discovery/Register"
+	Discovery_KeepAlive_FullMethodName    = "/discovery.Discovery/KeepAlive"
 	Discovery_Deregister_FullMethodName   = "/discovery.Discovery/Deregister"
 	Discovery_GetService_FullMethodName   = "/discovery.Discovery/GetService"
 	Discovery_GetServiceIP_FullMethodName = "/discovery.Discovery/GetServiceIP"
 	Discovery_Ready_FullMethodName        = "/discovery.Discovery/Ready"
+	Discovery_Watch_FullMethodName        = "/discovery.Discovery/Watch"
 )
 
 // DiscoveryClient is the client API for Discovery service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type DiscoveryClient interface {
-	// 服务注册
+	// 服务注册 返回租约，租约到期未续约的实例会被清除
 	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
+	// 租约续约
+	KeepAlive(ctx context.Context, in *KeepAliveRequest, opts ...grpc.CallOption) (*KeepAliveResponse, error)
 	// 服务注销
 	Deregister(ctx context.Context, in *DeregisterRequest, opts ...grpc.CallOption) (*DeregisterResponse, error)
 	// 获取服务IP列表 - 客户端负载均衡
@@ -40,6 +44,8 @@ type DiscoveryClient interface {
 	GetServiceIP(ctx context.Context, in *GetServiceIPRequest, opts ...grpc.CallOption) (*GetServiceIPResponse, error)
 	// 是否就绪 用于服务启动时检查
 	Ready(ctx context.Context, in *ReadyRequest, opts ...grpc.CallOption) (*ReadyResponse, error)
+	// 订阅服务实例变更 先返回全部实例，之后推送增删改事件
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error)
 }
 
 type discoveryClient struct {
@@ -60,6 +66,16 @@ func (c *discoveryClient) Register(ctx context.Context, in *RegisterRequest, opt
 	return out, nil
 }
 
+func (c *discoveryClient) KeepAlive(ctx context.Context, in *KeepAliveRequest, opts ...grpc.CallOption) (*KeepAliveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KeepAliveResponse)
+	err := c.cc.Invoke(ctx, Discovery_KeepAlive_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *discoveryClient) Deregister(ctx context.Context, in *DeregisterRequest, opts ...grpc.CallOption) (*DeregisterResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DeregisterResponse)
@@ -100,12 +116,33 @@ func (c *discoveryClient) Ready(ctx context.Context, in *ReadyRequest, opts ...g
 	return out, nil
 }
 
+func (c *discoveryClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Discovery_ServiceDesc.Streams[0], Discovery_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, WatchEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Discovery_WatchClient = grpc.ServerStreamingClient[WatchEvent]
+
 // DiscoveryServer is the server API for Discovery service.
 // All implementations must embed UnimplementedDiscoveryServer
 // for forward compatibility.
 type DiscoveryServer interface {
-	// 服务注册
+	// 服务注册 返回租约，租约到期未续约的实例会被清除
 	Register(context.Context, *RegisterRequest) (*RegisterResponse, error)
+	// 租约续约
+	KeepAlive(context.Context, *KeepAliveRequest) (*KeepAliveResponse, error)
 	// 服务注销
 	Deregister(context.Context, *DeregisterRequest) (*DeregisterResponse, error)
 	// 获取服务IP列表 - 客户端负载均衡
@@ -114,6 +151,8 @@ type DiscoveryServer interface {
 	GetServiceIP(context.Context, *GetServiceIPRequest) (*GetServiceIPResponse, error)
 	// 是否就绪 用于服务启动时检查
 	Ready(context.Context, *ReadyRequest) (*ReadyResponse, error)
+	// 订阅服务实例变更 先返回全部实例，之后推送增删改事件
+	Watch(*WatchRequest, grpc.ServerStreamingServer[WatchEvent]) error
 	mustEmbedUnimplementedDiscoveryServer()
 }
 
@@ -127,6 +166,9 @@ type UnimplementedDiscoveryServer struct{}
 func (UnimplementedDiscoveryServer) Register(context.Context, *RegisterRequest) (*RegisterResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
 }
+func (UnimplementedDiscoveryServer) KeepAlive(context.Context, *KeepAliveRequest) (*KeepAliveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KeepAlive not implemented")
+}
 func (UnimplementedDiscoveryServer) Deregister(context.Context, *DeregisterRequest) (*DeregisterResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Deregister not implemented")
 }
@@ -139,6 +181,9 @@ func (UnimplementedDiscoveryServer) GetServiceIP(context.Context, *GetServiceIPR
 func (UnimplementedDiscoveryServer) Ready(context.Context, *ReadyRequest) (*ReadyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Ready not implemented")
 }
+func (UnimplementedDiscoveryServer) Watch(*WatchRequest, grpc.ServerStreamingServer[WatchEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
 func (UnimplementedDiscoveryServer) mustEmbedUnimplementedDiscoveryServer() {}
 func (UnimplementedDiscoveryServer) testEmbeddedByValue()                   {}
 
@@ -178,6 +223,24 @@ func _Discovery_Register_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Discovery_KeepAlive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeepAliveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiscoveryServer).KeepAlive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Discovery_KeepAlive_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiscoveryServer).KeepAlive(ctx, req.(*KeepAliveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Discovery_Deregister_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeregisterRequest)
 	if err := dec(in); err != nil {
@@ -250,6 +313,17 @@ func _Discovery_Ready_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Discovery_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DiscoveryServer).Watch(m, &grpc.GenericServerStream[WatchRequest, WatchEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Discovery_WatchServer = grpc.ServerStreamingServer[WatchEvent]
+
 // Discovery_ServiceDesc is the grpc.ServiceDesc for Discovery service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -261,6 +335,10 @@ var Discovery_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Register",
 			Handler:    _Discovery_Register_Handler,
 		},
+		{
+			MethodName: "KeepAlive",
+			Handler:    _Discovery_KeepAlive_Handler,
+		},
 		{
 			MethodName: "Deregister",
 			Handler:    _Discovery_Deregister_Handler,
@@ -278,6 +356,12 @@ var Discovery_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _Discovery_Ready_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _Discovery_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "rpc/service/discovery.proto",
 }
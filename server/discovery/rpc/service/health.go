@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"im/pkg/config"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// 一个服务的健康检查配置
+type healthCheck struct {
+	interval  time.Duration // 0表示不检查
+	threshold int           // 连续失败次数，达到后标记为不健康
+}
+
+// 一个实例的健康状态
+type instanceHealth struct {
+	status    HealthStatus
+	failures  int
+	lastCheck time.Time
+}
+
+// 解析健康检查配置，无效的按服务配置记录错误并使用默认配置
+func parseHealthChecks(logger *slog.Logger, conf *config.DiscoveryConfig) (healthCheck, map[string]healthCheck) {
+	defaultCheck := healthCheck{
+		interval:  time.Duration(max(conf.HealthCheckInterval, 0)) * time.Second,
+		threshold: max(conf.HealthCheckThreshold, 1),
+	}
+	checks := make(map[string]healthCheck)
+	for name, value := range parseServiceMap(conf.ServiceHealthCheck) {
+		check := defaultCheck
+		intervalValue, thresholdValue, hasThreshold := strings.Cut(value, ":")
+		interval, err := strconv.Atoi(intervalValue)
+		if err != nil || interval < 0 {
+			logger.Error("invalid health check interval, use default", "service", name, "value", value)
+			continue
+		}
+		check.interval = time.Duration(interval) * time.Second
+		if hasThreshold {
+			threshold, err := strconv.Atoi(thresholdValue)
+			if err != nil || threshold < 1 {
+				logger.Error("invalid health check threshold, use default", "service", name, "value", value)
+				continue
+			}
+			check.threshold = threshold
+		}
+		checks[name] = check
+	}
+	return defaultCheck, checks
+}
+
+func (s *DiscoveryService) healthCheckFor(serviceName string) healthCheck {
+	if check, ok := s.healthChecks[serviceName]; ok {
+		return check
+	}
+	return s.defaultHealthCheck
+}
+
+// 健康检查的地址，注册了gRPC端口的实例检查gRPC端口，否则检查注册端口
+func probeAddr(info *ServiceInfo) string {
+	port := info.ServicePort
+	if rpcPort, ok := info.Metadata[MetadataRPCPort]; ok && len(rpcPort) > 0 {
+		port = rpcPort
+	}
+	return net.JoinHostPort(info.ServiceAddress, port)
+}
+
+// 定期使用grpc.health.v1检查实例，检查间隔按服务配置
+func (s *DiscoveryService) healthLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			s.closeProbeConns()
+			return
+		case <-ticker.C:
+			s.checkHealth(s.ctx, false)
+		}
+	}
+}
+
+// 检查到期的实例，force为true时检查所有开启健康检查的实例，并清理已不存在的实例的状态
+func (s *DiscoveryService) checkHealth(ctx context.Context, force bool) {
+	s.mu.RLock()
+	services := make(map[string][]*ServiceInfo, len(s.services))
+	for name, instances := range s.services {
+		services[name] = instances
+	}
+	s.mu.RUnlock()
+
+	now := s.now()
+	alive := make(map[string]struct{})
+	addrs := make(map[string]struct{})
+	var wg sync.WaitGroup
+	for name, instances := range services {
+		check := s.healthCheckFor(name)
+		if check.interval <= 0 {
+			continue
+		}
+		for _, info := range instances {
+			key := instanceField(name, info.ServiceAddress, info.ServicePort)
+			alive[key] = struct{}{}
+			addrs[probeAddr(info)] = struct{}{}
+			s.healthMu.Lock()
+			state, ok := s.health[key]
+			if !ok {
+				state = &instanceHealth{}
+				s.health[key] = state
+			}
+			due := force || now.Sub(state.lastCheck) >= check.interval
+			if due {
+				state.lastCheck = now
+			}
+			s.healthMu.Unlock()
+			if !due {
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.recordHealth(name, info, check, s.probe(ctx, probeAddr(info)))
+			}()
+		}
+	}
+	wg.Wait()
+
+	// 实例注销或关闭健康检查后删除状态和连接
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	for key := range s.health {
+		if _, ok := alive[key]; !ok {
+			delete(s.health, key)
+		}
+	}
+	for addr, conn := range s.probeConns {
+		if _, ok := addrs[addr]; !ok {
+			conn.Close()
+			delete(s.probeConns, addr)
+		}
+	}
+}
+
+// 检查一个实例是否在服务
+func (s *DiscoveryService) probe(ctx context.Context, addr string) bool {
+	conn, err := s.probeConn(addr)
+	if err != nil {
+		s.logger.Warn("failed to create health check client", "address", addr, "error", err)
+		return false
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.healthTimeout)
+	defer cancel()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		s.logger.Debug("health check failed", "address", addr, "error", err)
+		return false
+	}
+	return resp.Status == healthpb.HealthCheckResponse_SERVING
+}
+
+// 复用到实例的连接，避免每次检查重新握手
+func (s *DiscoveryService) probeConn(addr string) (*grpc.ClientConn, error) {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	if conn, ok := s.probeConns[addr]; ok {
+		return conn, nil
+	}
+	conn, err := grpc.NewClient(addr, s.probeDialOption)
+	if err != nil {
+		return nil, err
+	}
+	s.probeConns[addr] = conn
+	return conn, nil
+}
+
+func (s *DiscoveryService) closeProbeConns() {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	for addr, conn := range s.probeConns {
+		conn.Close()
+		delete(s.probeConns, addr)
+	}
+}
+
+// 记录检查结果，健康状态变化时推送UPDATE
+func (s *DiscoveryService) recordHealth(serviceName string, info *ServiceInfo, check healthCheck, ok bool) {
+	key := instanceField(serviceName, info.ServiceAddress, info.ServicePort)
+	s.healthMu.Lock()
+	state, exists := s.health[key]
+	if !exists {
+		s.healthMu.Unlock()
+		return
+	}
+	previous := state.status
+	if ok {
+		state.failures = 0
+		state.status = HealthStatus_HEALTH_STATUS_HEALTHY
+	} else {
+		state.failures++
+		if state.failures >= check.threshold {
+			state.status = HealthStatus_HEALTH_STATUS_UNHEALTHY
+		}
+	}
+	current := state.status
+	s.healthMu.Unlock()
+
+	if previous == current {
+		return
+	}
+	s.logger.Info("instance health changed", "service", serviceName, "address", info.ServiceAddress, "port", info.ServicePort, "from", previous, "to", current)
+	// 未知到健康不影响选择，不推送
+	if previous == HealthStatus_HEALTH_STATUS_UNKNOWN && current == HealthStatus_HEALTH_STATUS_HEALTHY {
+		return
+	}
+	s.broadcast(&WatchEvent{
+		Type:        EventType_EVENT_TYPE_UPDATE,
+		ServiceName: serviceName,
+		ServiceInfo: []*ServiceInfo{info},
+	})
+}
+
+func (s *DiscoveryService) healthOf(serviceName string, info *ServiceInfo) HealthStatus {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	if state, ok := s.health[instanceField(serviceName, info.ServiceAddress, info.ServicePort)]; ok {
+		return state.status
+	}
+	return HealthStatus_HEALTH_STATUS_UNKNOWN
+}
+
+// 返回带健康状态的实例副本，本地缓存中的实例不保存健康状态
+func (s *DiscoveryService) withHealth(serviceName string, services []*ServiceInfo) []*ServiceInfo {
+	result := make([]*ServiceInfo, 0, len(services))
+	for _, info := range services {
+		info = proto.Clone(info).(*ServiceInfo)
+		info.Health = s.healthOf(serviceName, info)
+		result = append(result, info)
+	}
+	return result
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"im/pkg/config"
+	"log/slog"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// 启动只提供健康检查的实例，返回端口
+func startHealthServer(t *testing.T, host string) (*health.Server, string) {
+	listener, err := net.Listen("tcp", net.JoinHostPort(host, "0"))
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	return healthServer, port
+}
+
+func TestParseHealthChecks(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	defaultCheck, checks := parseHealthChecks(logger, &config.DiscoveryConfig{
+		HealthCheckInterval:  10,
+		HealthCheckThreshold: 3,
+		ServiceHealthCheck:   "imgateway=5:2, apigateway=0, bad=x, worse=5:0",
+	})
+	if defaultCheck.interval != 10*time.Second || defaultCheck.threshold != 3 {
+		t.Fatalf("unexpected default %v", defaultCheck)
+	}
+	if checks["imgateway"] != (healthCheck{interval: 5 * time.Second, threshold: 2}) {
+		t.Fatalf("unexpected imgateway check %v", checks["imgateway"])
+	}
+	if checks["apigateway"] != (healthCheck{interval: 0, threshold: 3}) {
+		t.Fatalf("unexpected apigateway check %v", checks["apigateway"])
+	}
+	if _, ok := checks["bad"]; ok {
+		t.Fatalf("expected invalid interval ignored")
+	}
+	if _, ok := checks["worse"]; ok {
+		t.Fatalf("expected invalid threshold ignored")
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	serv := newTestReplicaWithConfig(t, NewMemoryStore(), clock, &config.DiscoveryConfig{
+		LeaseTTL:             30,
+		LoadBalance:          "round_robin",
+		HealthCheckInterval:  10,
+		HealthCheckTimeout:   1,
+		HealthCheckThreshold: 2,
+		ServiceHealthCheck:   "unchecked=0",
+	})
+	ctx := context.Background()
+	failing, failingPort := startHealthServer(t, "127.0.0.1")
+	_, healthyPort := startHealthServer(t, "127.0.0.2")
+	w := serv.addWatcher("imgateway")
+	// 注册端口不是gRPC端口，通过元数据指定检查端口
+	for _, req := range []*RegisterRequest{
+		{ServiceName: "imgateway", ServiceAddress: "127.0.0.1", ServicePort: failingPort},
+		{ServiceName: "imgateway", ServiceAddress: "127.0.0.2", ServicePort: "8086", Metadata: map[string]string{MetadataRPCPort: healthyPort}},
+		{ServiceName: "unchecked", ServiceAddress: "127.0.0.1", ServicePort: "1"},
+	} {
+		if _, err := serv.Register(ctx, req); err != nil {
+			t.Fatalf("failed to register: %v", err)
+		}
+	}
+	health := func(serviceName string) map[string]HealthStatus {
+		resp, err := serv.GetService(ctx, &GetServiceRequest{ServiceName: serviceName})
+		if err != nil {
+			t.Fatalf("failed to get service: %v", err)
+		}
+		result := make(map[string]HealthStatus)
+		for _, info := range resp.ServiceInfo {
+			result[info.ServiceAddress] = info.Health
+		}
+		return result
+	}
+	expectEvent(t, w, EventType_EVENT_TYPE_ADD, "127.0.0.1")
+	expectEvent(t, w, EventType_EVENT_TYPE_ADD, "127.0.0.2")
+
+	serv.checkHealth(ctx, true)
+	if got := health("imgateway"); got["127.0.0.1"] != HealthStatus_HEALTH_STATUS_HEALTHY || got["127.0.0.2"] != HealthStatus_HEALTH_STATUS_HEALTHY {
+		t.Fatalf("expected instances healthy, got %v", got)
+	}
+	if got := health("unchecked"); got["127.0.0.1"] != HealthStatus_HEALTH_STATUS_UNKNOWN {
+		t.Fatalf("expected unchecked instance unknown, got %v", got)
+	}
+	expectNoEvent(t, w)
+
+	// 连续失败达到阈值后标记为不健康，GetServiceIP跳过
+	failing.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	serv.checkHealth(ctx, true)
+	if got := health("imgateway"); got["127.0.0.1"] != HealthStatus_HEALTH_STATUS_HEALTHY {
+		t.Fatalf("expected instance healthy below threshold, got %v", got)
+	}
+	serv.checkHealth(ctx, true)
+	if event := expectEvent(t, w, EventType_EVENT_TYPE_UPDATE, "127.0.0.1"); event.ServiceInfo[0].Health != HealthStatus_HEALTH_STATUS_UNHEALTHY {
+		t.Fatalf("unexpected event %v", event)
+	}
+	for i := range 10 {
+		resp, err := serv.GetServiceIP(ctx, &GetServiceIPRequest{ServiceName: "imgateway", ClientKey: string(rune('a' + i))})
+		if err != nil {
+			t.Fatalf("failed to get service ip: %v", err)
+		}
+		if resp.ServiceAddress != "127.0.0.2" {
+			t.Fatalf("expected healthy instance, got %s", resp.ServiceAddress)
+		}
+	}
+
+	// 未到检查间隔不检查
+	failing.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	serv.checkHealth(ctx, false)
+	if got := health("imgateway"); got["127.0.0.1"] != HealthStatus_HEALTH_STATUS_UNHEALTHY {
+		t.Fatalf("expected instance not checked before interval, got %v", got)
+	}
+	clock.Advance(10 * time.Second)
+	serv.checkHealth(ctx, false)
+	if event := expectEvent(t, w, EventType_EVENT_TYPE_UPDATE, "127.0.0.1"); event.ServiceInfo[0].Health != HealthStatus_HEALTH_STATUS_HEALTHY {
+		t.Fatalf("unexpected event %v", event)
+	}
+
+	// 实例注销后清除状态和连接
+	if _, err := serv.Deregister(ctx, &DeregisterRequest{ServiceName: "imgateway", ServiceAddress: "127.0.0.1", ServicePort: failingPort}); err != nil {
+		t.Fatalf("failed to deregister: %v", err)
+	}
+	serv.checkHealth(ctx, true)
+	serv.healthMu.Lock()
+	defer serv.healthMu.Unlock()
+	if len(serv.health) != 1 || len(serv.probeConns) != 1 {
+		t.Fatalf("expected state of removed instance cleared, got %v %v", serv.health, serv.probeConns)
+	}
+}
+
+func TestHealthCheckAllUnhealthy(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	serv := newTestReplicaWithConfig(t, NewMemoryStore(), clock, &config.DiscoveryConfig{
+		LeaseTTL:             30,
+		HealthCheckInterval:  10,
+		HealthCheckTimeout:   1,
+		HealthCheckThreshold: 1,
+	})
+	ctx := context.Background()
+	failing, port := startHealthServer(t, "127.0.0.1")
+	failing.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	if _, err := serv.Register(ctx, &RegisterRequest{ServiceName: "imgateway", ServiceAddress: "127.0.0.1", ServicePort: port}); err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	serv.checkHealth(ctx, true)
+	if _, err := serv.GetServiceIP(ctx, &GetServiceIPRequest{ServiceName: "imgateway"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 注册实例并创建租约，返回租约ID和实例是否为新增
+func (s *DiscoveryService) grantLease(ctx context.Context, serviceName string, info *ServiceInfo, ttl int64) (string, bool, error) {
+	leaseId := uuid.New().String()
+	expireAt := s.now().Add(time.Duration(ttl) * time.Second)
+	added, err := s.store.Register(ctx, serviceName, info, leaseId, ttl, expireAt)
+	return leaseId, added, err
+}
+
+// 续约并更新负载，租约不存在或已到期时返回errLeaseNotFound
+func (s *DiscoveryService) renewLease(ctx context.Context, leaseId string, load int64) (int64, error) {
+	return s.store.KeepAlive(ctx, leaseId, load, s.now())
+}
+
+// 注销实例，返回实例是否存在
+func (s *DiscoveryService) revokeInstance(ctx context.Context, req *DeregisterRequest) (bool, error) {
+	return s.store.Deregister(ctx, req.ServiceName, req.ServiceAddress, req.ServicePort)
+}
+
+// 清除所有已到期的租约及其实例，多个发现服务副本并发执行时结果一致
+func (s *DiscoveryService) purgeExpiredLeases(ctx context.Context) (int, error) {
+	events, err := s.store.ExpireLeases(ctx, s.now())
+	for _, event := range events {
+		info := event.ServiceInfo[0]
+		s.applyLocal(ctx, EventType_EVENT_TYPE_REMOVE, event.ServiceName, info)
+		s.logger.Info("lease expired, instance removed", "service", event.ServiceName, "address", info.ServiceAddress, "port", info.ServicePort)
+	}
+	return len(events), err
+}
+
+func (s *DiscoveryService) watchLeases(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.purgeExpiredLeases(s.ctx); err != nil {
+				s.logger.Error("failed to purge expired leases", "error", err)
+			}
+		}
+	}
+}
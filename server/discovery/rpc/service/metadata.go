@@ -0,0 +1,64 @@
+package service
+
+import (
+	"hash/crc32"
+)
+
+// 实例元数据中约定的key
+const (
+	MetadataZone    = "zone"    // 可用区，GetServiceIP优先选择与客户端同可用区的实例
+	MetadataVersion = "version" // 版本，值为canary的实例只接收灰度流量
+	VersionCanary   = "canary"
+	MetadataRPCPort = "rpc_port" // gRPC端口，健康检查使用，未设置时检查注册端口
+)
+
+// 按元数据过滤候选实例
+// 1. 只保留元数据与match全部匹配的实例
+// 2. 灰度：client_key按哈希落在灰度比例内时只选canary实例，否则排除canary实例，没有可选实例时不拆分
+// 3. 优先选择同可用区的实例，同可用区没有实例时跨可用区
+func filterInstances(services []*ServiceInfo, req *GetServiceIPRequest, canaryPercent int) []*ServiceInfo {
+	services = filter(services, func(info *ServiceInfo) bool {
+		for key, value := range req.Match {
+			if info.Metadata[key] != value {
+				return false
+			}
+		}
+		return true
+	})
+	isCanary := func(info *ServiceInfo) bool { return info.Metadata[MetadataVersion] == VersionCanary }
+	if inCanary(req.ClientKey, canaryPercent) {
+		services = prefer(services, isCanary)
+	} else {
+		services = prefer(services, func(info *ServiceInfo) bool { return !isCanary(info) })
+	}
+	if len(req.Zone) > 0 {
+		services = prefer(services, func(info *ServiceInfo) bool { return info.Metadata[MetadataZone] == req.Zone })
+	}
+	return services
+}
+
+// client_key是否落在灰度比例内，同一个client_key结果固定，未提供client_key时不进入灰度
+func inCanary(clientKey string, percent int) bool {
+	if len(clientKey) == 0 || percent <= 0 {
+		return false
+	}
+	return int(crc32.ChecksumIEEE([]byte(clientKey))%100) < percent
+}
+
+func filter(services []*ServiceInfo, keep func(*ServiceInfo) bool) []*ServiceInfo {
+	result := make([]*ServiceInfo, 0, len(services))
+	for _, info := range services {
+		if keep(info) {
+			result = append(result, info)
+		}
+	}
+	return result
+}
+
+// 有满足条件的实例时只保留这些实例，否则保持不变
+func prefer(services []*ServiceInfo, keep func(*ServiceInfo) bool) []*ServiceInfo {
+	if result := filter(services, keep); len(result) > 0 {
+		return result
+	}
+	return services
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func testInstance(address string, metadata map[string]string) *ServiceInfo {
+	return &ServiceInfo{ServiceAddress: address, ServicePort: "8086", Weight: 1, Metadata: metadata}
+}
+
+func addresses(services []*ServiceInfo) map[string]struct{} {
+	result := make(map[string]struct{}, len(services))
+	for _, info := range services {
+		result[info.ServiceAddress] = struct{}{}
+	}
+	return result
+}
+
+func TestFilterInstances(t *testing.T) {
+	services := []*ServiceInfo{
+		testInstance("10.0.0.1", map[string]string{MetadataZone: "a", "protocol": "tcp"}),
+		testInstance("10.0.0.2", map[string]string{MetadataZone: "b", "protocol": "tcp"}),
+		testInstance("10.0.0.3", map[string]string{MetadataZone: "a", MetadataVersion: VersionCanary, "protocol": "tcp"}),
+		testInstance("10.0.0.4", map[string]string{MetadataZone: "a", "protocol": "websocket"}),
+	}
+
+	// 同可用区优先，非灰度请求不选择canary实例
+	result := addresses(filterInstances(services, &GetServiceIPRequest{ClientKey: "user", Zone: "a"}, 0))
+	if _, ok := result["10.0.0.3"]; ok || len(result) != 2 {
+		t.Fatalf("unexpected instances %v", result)
+	}
+	// 同可用区没有实例时跨可用区
+	if result := filterInstances(services, &GetServiceIPRequest{Zone: "c"}, 0); len(result) != 3 {
+		t.Fatalf("expected all stable instances, got %v", addresses(result))
+	}
+	// match必须全部匹配
+	result = addresses(filterInstances(services, &GetServiceIPRequest{Match: map[string]string{"protocol": "websocket"}}, 0))
+	if _, ok := result["10.0.0.4"]; !ok || len(result) != 1 {
+		t.Fatalf("unexpected instances %v", result)
+	}
+	if result := filterInstances(services, &GetServiceIPRequest{Match: map[string]string{"protocol": "quic"}}, 0); len(result) != 0 {
+		t.Fatalf("expected no instance, got %v", addresses(result))
+	}
+	// 只有canary实例时仍然可用
+	if result := filterInstances(services[2:3], &GetServiceIPRequest{ClientKey: "user"}, 0); len(result) != 1 {
+		t.Fatalf("expected canary instance, got %v", addresses(result))
+	}
+}
+
+func TestCanary(t *testing.T) {
+	services := []*ServiceInfo{
+		testInstance("10.0.0.1", nil),
+		testInstance("10.0.0.2", map[string]string{MetadataVersion: VersionCanary}),
+	}
+	canary := 0
+	for i := range 1000 {
+		req := &GetServiceIPRequest{ClientKey: fmt.Sprintf("user-%d", i)}
+		result := filterInstances(services, req, 10)
+		if len(result) != 1 {
+			t.Fatalf("expected one instance, got %v", addresses(result))
+		}
+		if result[0].ServiceAddress == "10.0.0.2" {
+			canary++
+		}
+		// 同一个client_key结果固定
+		if again := filterInstances(services, req, 10); again[0] != result[0] {
+			t.Fatalf("client key %s switched instance", req.ClientKey)
+		}
+	}
+	if canary < 50 || canary > 150 {
+		t.Fatalf("expected about 10%% canary, got %d", canary)
+	}
+	if result := filterInstances(services, &GetServiceIPRequest{}, 100); result[0].ServiceAddress != "10.0.0.1" {
+		t.Fatalf("expected request without client key not in canary")
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	runWithStores(t, testMetadata)
+}
+
+func testMetadata(t *testing.T, connect func() Store) {
+	serv, _ := newTestService(t, connect)
+	ctx := context.Background()
+	req := &RegisterRequest{
+		ServiceName:    "imgateway",
+		ServiceAddress: "10.0.0.1",
+		ServicePort:    "8086",
+		Metadata:       map[string]string{MetadataZone: "a", MetadataVersion: VersionCanary, "rpc_port": "8087"},
+	}
+	if _, err := serv.Register(ctx, req); err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	services, err := serv.store.Instances(ctx, "imgateway")
+	if err != nil {
+		t.Fatalf("failed to get service: %v", err)
+	}
+	if len(services) != 1 || services[0].Metadata["rpc_port"] != "8087" || services[0].Metadata[MetadataZone] != "a" {
+		t.Fatalf("unexpected service %v", services)
+	}
+
+	// 重新注册替换全部元数据
+	req.Metadata = map[string]string{MetadataZone: "b"}
+	if _, err := serv.Register(ctx, req); err != nil {
+		t.Fatalf("failed to register again: %v", err)
+	}
+	if err := serv.reconcile(ctx); err != nil {
+		t.Fatalf("failed to sync services: %v", err)
+	}
+	resp, err := serv.GetServiceIP(ctx, &GetServiceIPRequest{ServiceName: "imgateway", ClientKey: "user"})
+	if err != nil {
+		t.Fatalf("failed to get service ip: %v", err)
+	}
+	if len(resp.Metadata) != 1 || resp.Metadata[MetadataZone] != "b" {
+		t.Fatalf("unexpected metadata %v", resp.Metadata)
+	}
+	if _, err := serv.GetServiceIP(ctx, &GetServiceIPRequest{ServiceName: "imgateway", Match: map[string]string{MetadataZone: "a"}}); err == nil {
+		t.Fatalf("expected no instance matches")
+	}
+}
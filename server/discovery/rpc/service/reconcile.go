@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"maps"
+	"time"
+)
+
+// 定期用存储中的实例校正本地缓存，弥补丢失的订阅事件，并刷新实例负载
+// 存储不可用时保留本地缓存继续服务，Ready返回stale
+func (s *DiscoveryService) reconcileLoop(interval time.Duration) {
+	defer close(s.reconcileDone)
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("discovery reconcile panic recover", "error", r)
+		}
+	}()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			s.logger.Info("discovery reconcile stopped")
+			return
+		case <-ticker.C:
+			if err := s.reconcile(s.ctx); err != nil {
+				s.logger.Error("failed to reconcile services, serving stale data", "error", err, "last_sync", time.UnixMilli(s.lastSync.Load()))
+			}
+		}
+	}
+}
+
+// 对比存储和本地缓存，更新本地缓存并把差异推送给订阅者
+func (s *DiscoveryService) reconcile(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.initTimeout)
+	defer cancel()
+	// 读取存储期间通过事件更新过的服务以事件为准，留给下一轮校正
+	s.mu.RLock()
+	versions := maps.Clone(s.versions)
+	s.mu.RUnlock()
+
+	names, err := s.store.Services(ctx)
+	if err != nil {
+		s.stale.Store(true)
+		return err
+	}
+	desired := make(map[string][]*ServiceInfo, len(names))
+	for _, name := range names {
+		instances, err := s.store.Instances(ctx, name)
+		if err != nil {
+			s.stale.Store(true)
+			return err
+		}
+		if len(instances) > 0 {
+			desired[name] = instances
+		}
+	}
+
+	var events []*WatchEvent
+	s.mu.Lock()
+	for name := range s.services {
+		if _, ok := desired[name]; !ok && s.versions[name] == versions[name] {
+			events = append(events, diffInstances(name, s.services[name], nil)...)
+			delete(s.services, name)
+		}
+	}
+	for name, instances := range desired {
+		if s.versions[name] != versions[name] {
+			continue
+		}
+		events = append(events, diffInstances(name, s.services[name], instances)...)
+		s.services[name] = instances
+	}
+	s.mu.Unlock()
+
+	for _, event := range events {
+		s.logger.Info("service reconciled", "type", event.Type, "service", event.ServiceName, "instances", len(event.ServiceInfo))
+		s.broadcast(event)
+	}
+	s.lastSync.Store(s.now().UnixMilli())
+	if s.stale.Swap(false) {
+		s.logger.Info("discovery storage recovered")
+	}
+	return nil
+}
+
+// 计算一个服务从current到desired的变更事件
+// 负载每次续约都会变化，只刷新缓存不推送，权重或元数据变化时推送UPDATE
+func diffInstances(serviceName string, current []*ServiceInfo, desired []*ServiceInfo) []*WatchEvent {
+	key := func(info *ServiceInfo) string { return info.ServiceAddress + ":" + info.ServicePort }
+	existing := make(map[string]*ServiceInfo, len(current))
+	for _, info := range current {
+		existing[key(info)] = info
+	}
+	var added, updated []*ServiceInfo
+	for _, info := range desired {
+		old, ok := existing[key(info)]
+		delete(existing, key(info))
+		if !ok {
+			added = append(added, info)
+		} else if instanceChanged(old, info) {
+			updated = append(updated, info)
+		}
+	}
+	var removed []*ServiceInfo
+	for _, info := range current {
+		if _, ok := existing[key(info)]; ok {
+			removed = append(removed, &ServiceInfo{ServiceAddress: info.ServiceAddress, ServicePort: info.ServicePort})
+		}
+	}
+
+	var events []*WatchEvent
+	for _, change := range []struct {
+		eventType EventType
+		instances []*ServiceInfo
+	}{
+		{EventType_EVENT_TYPE_ADD, added},
+		{EventType_EVENT_TYPE_UPDATE, updated},
+		{EventType_EVENT_TYPE_REMOVE, removed},
+	} {
+		if len(change.instances) == 0 {
+			continue
+		}
+		events = append(events, &WatchEvent{
+			Type:        change.eventType,
+			ServiceName: serviceName,
+			ServiceInfo: change.instances,
+		})
+	}
+	return events
+}
+
+// 权重或元数据变化的实例需要推送UPDATE，负载变化不推送
+func instanceChanged(old *ServiceInfo, info *ServiceInfo) bool {
+	return old.Weight != info.Weight || !maps.Equal(old.Metadata, info.Metadata)
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"im/pkg/config"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// 可以模拟故障的存储
+type faultyStore struct {
+	Store
+	broken      atomic.Bool
+	onInstances func() // 读取实例时调用，模拟校正期间收到事件
+}
+
+func (f *faultyStore) Services(ctx context.Context) ([]string, error) {
+	if f.broken.Load() {
+		return nil, errors.New("storage unavailable")
+	}
+	return f.Store.Services(ctx)
+}
+
+func (f *faultyStore) Instances(ctx context.Context, serviceName string) ([]*ServiceInfo, error) {
+	if f.onInstances != nil {
+		f.onInstances()
+	}
+	return f.Store.Instances(ctx, serviceName)
+}
+
+func expectEvent(t *testing.T, w *watcher, eventType EventType, address string) *WatchEvent {
+	t.Helper()
+	select {
+	case event := <-w.events:
+		if event.Type != eventType || len(event.ServiceInfo) != 1 || event.ServiceInfo[0].ServiceAddress != address {
+			t.Fatalf("expected %s %s, got %v", eventType, address, event)
+		}
+		return event
+	case <-time.After(time.Second):
+		t.Fatalf("expected %s %s, got nothing", eventType, address)
+	}
+	return nil
+}
+
+func expectNoEvent(t *testing.T, w *watcher) {
+	t.Helper()
+	select {
+	case event := <-w.events:
+		t.Fatalf("unexpected event %v", event)
+	default:
+	}
+}
+
+// 绕过发现服务直接修改存储，模拟丢失的订阅事件
+func TestReconcile(t *testing.T) {
+	runWithStores(t, testReconcile)
+}
+
+func testReconcile(t *testing.T, connect func() Store) {
+	serv, clock := newTestService(t, connect)
+	store := serv.store
+	ctx := context.Background()
+	w := serv.addWatcher("imgateway")
+	info := &ServiceInfo{ServiceAddress: "10.0.0.1", ServicePort: "8086", Metadata: map[string]string{MetadataZone: "a"}}
+
+	if _, err := store.Register(ctx, "imgateway", info, "lease-1", 30, clock.Now().Add(30*time.Second)); err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	if err := serv.reconcile(ctx); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+	expectEvent(t, w, EventType_EVENT_TYPE_ADD, "10.0.0.1")
+	if services, _ := serv.getServiceLocal("imgateway"); len(services) != 1 {
+		t.Fatalf("expected instance added locally, got %v", services)
+	}
+
+	// 只有负载变化时刷新缓存，不推送
+	if _, err := store.KeepAlive(ctx, "lease-1", 7, clock.Now()); err != nil {
+		t.Fatalf("failed to keep alive: %v", err)
+	}
+	if err := serv.reconcile(ctx); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+	expectNoEvent(t, w)
+	if services, _ := serv.getServiceLocal("imgateway"); services[0].Load != 7 {
+		t.Fatalf("expected load refreshed, got %v", services)
+	}
+
+	info.Metadata = map[string]string{MetadataZone: "b"}
+	if _, err := store.Register(ctx, "imgateway", info, "lease-2", 30, clock.Now().Add(30*time.Second)); err != nil {
+		t.Fatalf("failed to register again: %v", err)
+	}
+	if err := serv.reconcile(ctx); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+	if event := expectEvent(t, w, EventType_EVENT_TYPE_UPDATE, "10.0.0.1"); event.ServiceInfo[0].Metadata[MetadataZone] != "b" {
+		t.Fatalf("unexpected event %v", event)
+	}
+
+	// 服务的实例全部消失后本地移除
+	if _, err := store.Deregister(ctx, "imgateway", "10.0.0.1", "8086"); err != nil {
+		t.Fatalf("failed to deregister: %v", err)
+	}
+	if err := serv.reconcile(ctx); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+	expectEvent(t, w, EventType_EVENT_TYPE_REMOVE, "10.0.0.1")
+	serv.mu.RLock()
+	_, ok := serv.services["imgateway"]
+	serv.mu.RUnlock()
+	if ok {
+		t.Fatalf("expected service removed locally")
+	}
+}
+
+func TestReconcileStale(t *testing.T) {
+	store := &faultyStore{Store: NewMemoryStore()}
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	serv := newTestReplica(t, store, clock)
+	ctx := context.Background()
+	if _, err := serv.Register(ctx, &RegisterRequest{ServiceName: "imgateway", ServiceAddress: "10.0.0.1", ServicePort: "8086"}); err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	if err := serv.reconcile(ctx); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+	synced, _ := serv.Ready(ctx, &ReadyRequest{})
+	if !synced.Ready || synced.Stale || synced.LastSync != clock.Now().UnixMilli() {
+		t.Fatalf("unexpected ready response %v", synced)
+	}
+
+	// 存储不可用时继续使用本地缓存
+	store.broken.Store(true)
+	clock.Advance(time.Minute)
+	if err := serv.reconcile(ctx); err == nil {
+		t.Fatalf("expected reconcile failed")
+	}
+	ready, _ := serv.Ready(ctx, &ReadyRequest{})
+	if !ready.Ready || !ready.Stale || ready.LastSync != synced.LastSync {
+		t.Fatalf("unexpected ready response %v", ready)
+	}
+	if _, err := serv.GetServiceIP(ctx, &GetServiceIPRequest{ServiceName: "imgateway"}); err != nil {
+		t.Fatalf("expected stale instance served, got %v", err)
+	}
+
+	store.broken.Store(false)
+	if err := serv.reconcile(ctx); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+	if ready, _ := serv.Ready(ctx, &ReadyRequest{}); ready.Stale || ready.LastSync != clock.Now().UnixMilli() {
+		t.Fatalf("unexpected ready response %v", ready)
+	}
+}
+
+// 读取存储期间收到事件的服务保留事件的结果
+func TestReconcileConcurrentEvent(t *testing.T) {
+	store := &faultyStore{Store: NewMemoryStore()}
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	serv := newTestReplica(t, store, clock)
+	ctx := context.Background()
+	if _, err := store.Register(ctx, "imgateway", &ServiceInfo{ServiceAddress: "10.0.0.1", ServicePort: "8086"}, "lease-1", 30, clock.Now().Add(30*time.Second)); err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	var once atomic.Bool
+	store.onInstances = func() {
+		if once.CompareAndSwap(false, true) {
+			serv.addServiceLocal("imgateway", &ServiceInfo{ServiceAddress: "10.0.0.2", ServicePort: "8086"})
+		}
+	}
+	if err := serv.reconcile(ctx); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+	if services, _ := serv.getServiceLocal("imgateway"); len(services) != 1 || services[0].ServiceAddress != "10.0.0.2" {
+		t.Fatalf("expected event result kept, got %v", services)
+	}
+}
+
+// 定期校正和订阅事件应用同一变更时只推送一次
+func TestReconcileDuplicateEvent(t *testing.T) {
+	runWithStores(t, testReconcileDuplicateEvent)
+}
+
+func testReconcileDuplicateEvent(t *testing.T, connect func() Store) {
+	serv, clock := newTestService(t, connect)
+	store := serv.store
+	ctx := context.Background()
+	w := serv.addWatcher("imgateway")
+	info := &ServiceInfo{ServiceAddress: "10.0.0.1", ServicePort: "8086", Weight: 1, Metadata: map[string]string{MetadataZone: "a"}}
+	event := func(eventType EventType, info *ServiceInfo) *WatchEvent {
+		return &WatchEvent{Type: eventType, ServiceName: "imgateway", ServiceInfo: []*ServiceInfo{info}}
+	}
+
+	// 校正先于事件
+	if _, err := store.Register(ctx, "imgateway", info, "lease-1", 30, clock.Now().Add(30*time.Second)); err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	if err := serv.reconcile(ctx); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+	serv.applyEvent(event(EventType_EVENT_TYPE_ADD, info))
+	expectEvent(t, w, EventType_EVENT_TYPE_ADD, "10.0.0.1")
+	expectNoEvent(t, w)
+
+	// 事件先于校正
+	updated := &ServiceInfo{ServiceAddress: "10.0.0.1", ServicePort: "8086", Weight: 1, Metadata: map[string]string{MetadataZone: "b"}}
+	if _, err := store.Register(ctx, "imgateway", updated, "lease-2", 30, clock.Now().Add(30*time.Second)); err != nil {
+		t.Fatalf("failed to register again: %v", err)
+	}
+	serv.applyEvent(event(EventType_EVENT_TYPE_UPDATE, updated))
+	if err := serv.reconcile(ctx); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+	expectEvent(t, w, EventType_EVENT_TYPE_UPDATE, "10.0.0.1")
+	expectNoEvent(t, w)
+
+	// 只有负载变化的事件不推送
+	serv.applyEvent(event(EventType_EVENT_TYPE_UPDATE, &ServiceInfo{ServiceAddress: "10.0.0.1", ServicePort: "8086", Weight: 1, Metadata: map[string]string{MetadataZone: "b"}, Load: 3}))
+	expectNoEvent(t, w)
+
+	if _, err := store.Deregister(ctx, "imgateway", "10.0.0.1", "8086"); err != nil {
+		t.Fatalf("failed to deregister: %v", err)
+	}
+	if err := serv.reconcile(ctx); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+	serv.applyEvent(event(EventType_EVENT_TYPE_REMOVE, info))
+	expectEvent(t, w, EventType_EVENT_TYPE_REMOVE, "10.0.0.1")
+	expectNoEvent(t, w)
+
+	// 注册到本副本时本地推送，收到自己发布的事件不再推送
+	if _, err := serv.Register(ctx, &RegisterRequest{ServiceName: "imgateway", ServiceAddress: "10.0.0.2", ServicePort: "8086", Ttl: 10}); err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	expectEvent(t, w, EventType_EVENT_TYPE_ADD, "10.0.0.2")
+	if err := serv.reconcile(ctx); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	expectNoEvent(t, w)
+}
+
+func TestReconcileLoopStop(t *testing.T) {
+	store := NewMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	serv := newDiscoveryService(ctx, logger, &config.DiscoveryConfig{LeaseTTL: 30}, store, grpc.WithTransportCredentials(insecure.NewCredentials()), clock.Now)
+	cancel()
+	select {
+	case <-serv.reconcileDone:
+	case <-time.After(time.Second):
+		t.Fatalf("reconcile loop not stopped")
+	}
+}
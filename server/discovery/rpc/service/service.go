@@ -2,9 +2,13 @@ package service
 
 import (
 	context "context"
+	"errors"
 	"im/pkg/config"
 	"im/pkg/loadbalance"
 	"log/slog"
+	"net"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -12,140 +16,175 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
 )
 
 type DiscoveryService struct {
 	UnimplementedDiscoveryServer
-	ctx          context.Context
-	services     map[string][]*ServiceInfo
-	mu           sync.RWMutex
-	redisClient  *redis.Client
-	redisKey     string
-	ready        atomic.Bool
-	initTimeout  time.Duration
-	loadBalancer loadbalance.LoadBalancer
-	logger       *slog.Logger
-}
-
-func NewDiscoveryService(ctx context.Context, logger *slog.Logger, conf *config.DiscoveryConfig) *DiscoveryService {
+	ctx             context.Context
+	services        map[string][]*ServiceInfo
+	versions        map[string]uint64 // 服务名称 -> 本地缓存被事件修改的次数，校正时跳过读取存储期间修改过的服务
+	mu              sync.RWMutex
+	store           Store
+	ready           atomic.Bool
+	stale           atomic.Bool   // 最近一次校正失败，本地缓存可能已过期
+	lastSync        atomic.Int64  // 最后一次校正成功的时间 毫秒时间戳
+	reconcileDone   chan struct{} // 校正循环退出后关闭
+	initTimeout     time.Duration
+	strategies      map[string]string // 服务名称 -> 负载均衡策略，未配置的服务使用defaultStrategy
+	defaultStrategy string
+	canary          map[string]int // 服务名称 -> 灰度比例 百分比
+	balancerMu      sync.Mutex
+	balancers       map[string]loadbalance.LoadBalancer // 服务名称 -> 负载均衡器，有状态的策略需要复用
+	logger          *slog.Logger
+	leaseTTL        int64            // 默认租约有效期 秒
+	now             func() time.Time // 租约计时使用的时钟，测试时替换
+	watchMu         sync.Mutex
+	watchers        map[string]map[*watcher]struct{} // 服务名称 -> 订阅者
+	// 主动健康检查
+	defaultHealthCheck healthCheck
+	healthChecks       map[string]healthCheck // 服务名称 -> 健康检查配置，未配置的服务使用defaultHealthCheck
+	healthTimeout      time.Duration
+	probeDialOption    grpc.DialOption
+	healthMu           sync.Mutex
+	health             map[string]*instanceHealth  // <service_name>/<address>:<port> -> 健康状态
+	probeConns         map[string]*grpc.ClientConn // 检查地址 -> 连接
+}
+
+// NewDiscoveryService dialOption用于健康检查连接实例
+func NewDiscoveryService(ctx context.Context, logger *slog.Logger, conf *config.DiscoveryConfig, dialOption grpc.DialOption) *DiscoveryService {
+	return newDiscoveryService(ctx, logger, conf, newStore(ctx, logger, conf), dialOption, time.Now)
+}
+
+// 按配置创建注册信息存储，未知的存储类型记录错误并使用Redis
+func newStore(ctx context.Context, logger *slog.Logger, conf *config.DiscoveryConfig) Store {
+	switch conf.Storage {
+	case StorageMemory:
+		return NewMemoryStore()
+	case StorageRedis:
+	default:
+		logger.Error("invalid discovery storage, use redis", "storage", conf.Storage)
+	}
+	store := NewRedisStore(redis.NewClient(&redis.Options{
+		Addr:     conf.RedisConfig.Addr,
+		Password: conf.RedisConfig.Password,
+		DB:       conf.RedisConfig.DB,
+	}), logger)
+	if err := store.RebuildIndex(ctx); err != nil {
+		logger.Error("failed to rebuild service index", "error", err)
+	}
+	return store
+}
+
+func newDiscoveryService(ctx context.Context, logger *slog.Logger, conf *config.DiscoveryConfig, store Store, dialOption grpc.DialOption, now func() time.Time) *DiscoveryService {
 	serv := &DiscoveryService{
-		ctx:         ctx,
-		initTimeout: 10 * time.Second,
-		redisKey:    "im:discovery:",
-		services:    make(map[string][]*ServiceInfo),
-		logger:      logger.With("service_uuid", uuid.New().String(), "service_name", "discovery"),
-		redisClient: redis.NewClient(&redis.Options{
-			Addr:     conf.RedisConfig.Addr,
-			Password: conf.RedisConfig.Password,
-			DB:       conf.RedisConfig.DB,
-		}),
-		loadBalancer: loadbalance.NewConsistentHashBalancer(),
+		ctx:           ctx,
+		initTimeout:   10 * time.Second,
+		services:      make(map[string][]*ServiceInfo),
+		versions:      make(map[string]uint64),
+		logger:        logger.With("service_uuid", uuid.New().String(), "service_name", "discovery"),
+		store:         store,
+		balancers:     make(map[string]loadbalance.LoadBalancer),
+		leaseTTL:      int64(conf.LeaseTTL),
+		now:           now,
+		watchers:      make(map[string]map[*watcher]struct{}),
+		reconcileDone: make(chan struct{}),
+
+		healthTimeout:   time.Duration(max(conf.HealthCheckTimeout, 1)) * time.Second,
+		probeDialOption: dialOption,
+		health:          make(map[string]*instanceHealth),
+		probeConns:      make(map[string]*grpc.ClientConn),
 	}
 
 	serv.logger.Debug("discovery service config", "config", conf)
+	serv.defaultStrategy, serv.strategies = parseStrategies(serv.logger, conf)
+	serv.canary = parseCanary(serv.logger, conf)
+	serv.defaultHealthCheck, serv.healthChecks = parseHealthChecks(serv.logger, conf)
 
-	if err := initServiceMap(serv); err != nil {
+	if err := serv.reconcile(ctx); err != nil {
 		serv.logger.Error("failed to init discovery service", "error", err)
 	}
 	serv.ready.Store(true)
 	serv.logger.Info("discovery service ready")
 
-	go func() {
-		defer func() {
-			if r := recover(); r != nil {
-				serv.logger.Error("discovery service panic recover", "error", r)
-			}
-		}()
-		ticker := time.NewTicker(1 * time.Second)
-		defer ticker.Stop()
-		select {
-		case <-serv.ctx.Done():
-			serv.logger.Info("discovery service context done")
-			return
-		case <-ticker.C:
-			if err := initServiceMap(serv); err != nil {
-				serv.logger.Error("failed to init discovery service", "error", err)
-			}
-		}
-	}()
+	go serv.reconcileLoop(time.Duration(max(conf.ReconcileInterval, 1)) * time.Second)
+	go serv.watchLeases(time.Second)
+	go serv.healthLoop(time.Second)
+	// 订阅生效后再返回，之后的变更都能推送给订阅者
+	events, err := serv.subscribeEvents()
+	if err != nil {
+		serv.logger.Error("failed to subscribe discovery events", "error", err)
+	}
+	go serv.receiveEvents(events)
 
 	return serv
 }
 
-func initServiceMap(s *DiscoveryService) error {
-	ctx, cancel := context.WithTimeout(s.ctx, s.initTimeout)
-	defer cancel()
-	serverList, err := s.redisClient.Keys(ctx, s.redisKey+"*").Result()
+// Register 注册实例并返回租约，重复注册时替换原有租约
+func (s *DiscoveryService) Register(ctx context.Context, req *RegisterRequest) (*RegisterResponse, error) {
+	if len(req.ServiceName) == 0 || len(req.ServiceAddress) == 0 || len(req.ServicePort) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "service name, address and port are required")
+	}
+	ttl := req.Ttl
+	if ttl <= 0 {
+		ttl = s.leaseTTL
+	}
+	info := &ServiceInfo{
+		ServiceAddress: req.ServiceAddress,
+		ServicePort:    req.ServicePort,
+		Weight:         weightOf(req.Weight),
+		Load:           req.Load,
+		Metadata:       req.Metadata,
+	}
+	leaseId, added, err := s.grantLease(ctx, req.ServiceName, info, ttl)
 	if err != nil {
-		s.logger.Error("failed to get service list " + s.redisKey + "*")
-		return err
+		return nil, err
 	}
-	for _, server := range serverList {
-		serviceList, err := s.getServiceRedis(ctx, server)
-		if err != nil {
-			return err
-		}
-		if err := s.saveServiceLocal(strings.TrimPrefix(server, s.redisKey), serviceList); err != nil {
-			return err
-		}
+	eventType := EventType_EVENT_TYPE_UPDATE
+	if added {
+		eventType = EventType_EVENT_TYPE_ADD
 	}
-	return nil
+	s.applyLocal(ctx, eventType, req.ServiceName, info)
+	return &RegisterResponse{LeaseId: leaseId, Ttl: ttl}, nil
 }
 
-func (s *DiscoveryService) Register(ctx context.Context, req *RegisterRequest) (*RegisterResponse, error) {
-	success, err := s.redisClient.SAdd(ctx, s.redisKey+req.ServiceName, req.ServiceAddress+":"+req.ServicePort).Result()
+// KeepAlive 续约并更新负载，租约已过期时返回NotFound，客户端需要重新注册
+// 负载只写入存储，各副本定期全量同步时更新本地缓存，不推送订阅事件
+func (s *DiscoveryService) KeepAlive(ctx context.Context, req *KeepAliveRequest) (*KeepAliveResponse, error) {
+	ttl, err := s.renewLease(ctx, req.LeaseId, req.Load)
+	if errors.Is(err, errLeaseNotFound) {
+		return nil, status.Errorf(codes.NotFound, "lease %s not found", req.LeaseId)
+	}
 	if err != nil {
 		return nil, err
 	}
-	if success == 0 {
-		return nil, status.Errorf(codes.AlreadyExists, "service already exists")
-	}
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.services[req.ServiceName] = append(s.services[req.ServiceName], &ServiceInfo{
-		ServiceAddress: req.ServiceAddress,
-		ServicePort:    req.ServicePort,
-	})
-	return nil, nil
+	return &KeepAliveResponse{Ttl: ttl}, nil
 }
 
 func (s *DiscoveryService) Deregister(ctx context.Context, req *DeregisterRequest) (*DeregisterResponse, error) {
-	success, err := s.redisClient.SRem(ctx, s.redisKey+req.ServiceName, req.ServiceAddress+":"+req.ServicePort).Result()
+	removed, err := s.revokeInstance(ctx, req)
 	if err != nil {
 		return nil, err
 	}
-	if success == 0 {
+	if !removed {
 		return nil, status.Errorf(codes.NotFound, "address not found in service")
 	}
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	for i, service := range s.services[req.ServiceName] {
-		if service.ServiceAddress == req.ServiceAddress && service.ServicePort == req.ServicePort {
-			s.services[req.ServiceName] = append(s.services[req.ServiceName][:i], s.services[req.ServiceName][i+1:]...)
-			break
-		}
-	}
-	return nil, nil
+	s.applyLocal(ctx, EventType_EVENT_TYPE_REMOVE, req.ServiceName, &ServiceInfo{ServiceAddress: req.ServiceAddress, ServicePort: req.ServicePort})
+	return &DeregisterResponse{}, nil
 }
 
 func (s *DiscoveryService) GetService(ctx context.Context, req *GetServiceRequest) (*GetServiceResponse, error) {
 	if !s.ready.Load() {
 		return nil, status.Errorf(codes.Unavailable, "service not ready")
 	}
-	service, err := s.getServiceLocal(req.ServiceName)
+	service, err := s.getService(ctx, req.ServiceName)
 	if err != nil {
-		service, err = s.getServiceRedis(ctx, req.ServiceName)
-		if err != nil {
-			return nil, err
-		}
-		if err := s.saveServiceLocal(req.ServiceName, service); err != nil {
-			return nil, err
-		}
+		return nil, err
 	}
 	return &GetServiceResponse{
-		ServiceInfo: service,
+		ServiceInfo: s.withHealth(req.ServiceName, service),
 	}, nil
 }
 
@@ -153,28 +192,40 @@ func (s *DiscoveryService) GetServiceIP(ctx context.Context, req *GetServiceIPRe
 	if !s.ready.Load() {
 		return nil, status.Errorf(codes.Unavailable, "service not ready")
 	}
-	service, err := s.getServiceLocal(req.ServiceName)
+	service, err := s.getService(ctx, req.ServiceName)
 	if err != nil {
-		service, err = s.getServiceRedis(ctx, req.ServiceName)
-		if err != nil {
-			return nil, err
-		}
-		if err := s.saveServiceLocal(req.ServiceName, service); err != nil {
-			return nil, err
-		}
+		return nil, err
 	}
 
-	index := s.loadBalancer.Select(int64(len(service)), req.ClientKey)
+	service = filter(service, func(info *ServiceInfo) bool {
+		return s.healthOf(req.ServiceName, info) != HealthStatus_HEALTH_STATUS_UNHEALTHY
+	})
+	service = filterInstances(service, req, s.canary[req.ServiceName])
+	if len(service) == 0 {
+		return nil, status.Errorf(codes.NotFound, "no instance matches %v", req.Match)
+	}
+	instances := make([]loadbalance.Instance, 0, len(service))
+	for _, info := range service {
+		instances = append(instances, loadbalance.Instance{
+			Addr:   net.JoinHostPort(info.ServiceAddress, info.ServicePort),
+			Weight: int(info.Weight),
+			Load:   info.Load,
+		})
+	}
+	index := s.balancerFor(req.ServiceName).Select(instances, req.ClientKey)
 
 	return &GetServiceIPResponse{
 		ServiceAddress: service[index].ServiceAddress,
 		ServicePort:    service[index].ServicePort,
+		Metadata:       service[index].Metadata,
 	}, nil
 }
 
 func (s *DiscoveryService) Ready(ctx context.Context, req *ReadyRequest) (*ReadyResponse, error) {
 	return &ReadyResponse{
-		Ready: s.ready.Load(),
+		Ready:    s.ready.Load(),
+		Stale:    s.stale.Load(),
+		LastSync: s.lastSync.Load(),
 	}, nil
 }
 
@@ -191,28 +242,131 @@ func (s *DiscoveryService) getServiceLocal(serviceName string) ([]*ServiceInfo,
 	return service, nil
 }
 
-func (s *DiscoveryService) getServiceRedis(ctx context.Context, key string) ([]*ServiceInfo, error) {
-	serviceList, err := s.redisClient.SMembers(ctx, key).Result()
+// 本地没有时从存储读取并缓存
+func (s *DiscoveryService) getService(ctx context.Context, serviceName string) ([]*ServiceInfo, error) {
+	service, err := s.getServiceLocal(serviceName)
+	if err == nil {
+		return service, nil
+	}
+	service, err = s.store.Instances(ctx, serviceName)
 	if err != nil {
 		return nil, err
 	}
-	arr := make([]*ServiceInfo, 0, len(serviceList))
-	for _, service := range serviceList {
-		serviceInfoList := strings.SplitN(service, ":", 2)
-		arr = append(arr, &ServiceInfo{
-			ServiceAddress: serviceInfoList[0],
-			ServicePort:    serviceInfoList[1],
-		})
+	if len(service) == 0 {
+		return nil, status.Errorf(codes.NotFound, "service %s not found", serviceName)
+	}
+	if err := s.saveServiceLocal(serviceName, service); err != nil {
+		return nil, err
 	}
-	if len(arr) == 0 {
-		return nil, status.Errorf(codes.NotFound, "service not found in redis")
+	return service, nil
+}
+
+// 添加实例，实例已存在时更新
+// 新增或替换本地缓存中的实例，返回是否需要推送给订阅者，只有负载变化时不需要
+func (s *DiscoveryService) addServiceLocal(serviceName string, info *ServiceInfo) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	services := s.services[serviceName]
+	for i, service := range services {
+		if service.ServiceAddress == info.ServiceAddress && service.ServicePort == info.ServicePort {
+			// 复制后再修改，避免影响正在读取旧切片的请求
+			services = slices.Clone(services)
+			services[i] = info
+			s.services[serviceName] = services
+			s.versions[serviceName]++
+			return instanceChanged(service, info)
+		}
+	}
+	s.services[serviceName] = append(services, info)
+	s.versions[serviceName]++
+	return true
+}
+
+// 从本地缓存移除实例，返回实例是否存在
+func (s *DiscoveryService) removeServiceLocal(serviceName string, address string, port string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	services := s.services[serviceName]
+	for i, service := range services {
+		if service.ServiceAddress == address && service.ServicePort == port {
+			// 复制后再修改，避免影响正在读取旧切片的请求
+			s.services[serviceName] = append(append([]*ServiceInfo{}, services[:i]...), services[i+1:]...)
+			s.versions[serviceName]++
+			return true
+		}
 	}
-	return arr, nil
+	return false
 }
 
 func (s *DiscoveryService) saveServiceLocal(serviceName string, service []*ServiceInfo) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.services[serviceName] = service
+	s.versions[serviceName]++
 	return nil
 }
+
+// 解析负载均衡策略配置，无效的策略记录错误并使用一致性哈希
+func parseStrategies(logger *slog.Logger, conf *config.DiscoveryConfig) (string, map[string]string) {
+	valid := func(strategy string) bool {
+		if _, err := loadbalance.New(strategy); err != nil {
+			logger.Error("invalid load balance strategy, use consistent hash", "error", err)
+			return false
+		}
+		return true
+	}
+	defaultStrategy := loadbalance.StrategyConsistentHash
+	if valid(conf.LoadBalance) {
+		defaultStrategy = conf.LoadBalance
+	}
+	strategies := parseServiceMap(conf.ServiceLoadBalance)
+	for name, strategy := range strategies {
+		if !valid(strategy) {
+			delete(strategies, name)
+		}
+	}
+	return defaultStrategy, strategies
+}
+
+// 解析灰度比例配置，无效的比例记录错误并忽略
+func parseCanary(logger *slog.Logger, conf *config.DiscoveryConfig) map[string]int {
+	canary := make(map[string]int)
+	for name, value := range parseServiceMap(conf.ServiceCanary) {
+		percent, err := strconv.Atoi(value)
+		if err != nil || percent < 0 || percent > 100 {
+			logger.Error("invalid canary percent, ignored", "service", name, "percent", value)
+			continue
+		}
+		canary[name] = percent
+	}
+	return canary
+}
+
+// 解析 name=value,name=value 格式的按服务配置
+func parseServiceMap(value string) map[string]string {
+	result := make(map[string]string)
+	for _, item := range strings.Split(value, ",") {
+		name, v, ok := strings.Cut(item, "=")
+		if !ok {
+			continue
+		}
+		result[strings.TrimSpace(name)] = strings.TrimSpace(v)
+	}
+	return result
+}
+
+func (s *DiscoveryService) balancerFor(serviceName string) loadbalance.LoadBalancer {
+	s.balancerMu.Lock()
+	defer s.balancerMu.Unlock()
+	if b, ok := s.balancers[serviceName]; ok {
+		return b
+	}
+	strategy, ok := s.strategies[serviceName]
+	if !ok {
+		strategy = s.defaultStrategy
+	}
+	// 策略已在启动时校验
+	b, _ := loadbalance.New(strategy)
+	s.balancers[serviceName] = b
+	return b
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"im/pkg/config"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// 手动推进的时钟
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// 返回连接同一份注册信息的存储，每次调用对应一个发现服务副本
+func newTestStore(t *testing.T, storage string) func() Store {
+	if storage == StorageMemory {
+		store := NewMemoryStore()
+		return func() Store { return store }
+	}
+	redisServer := miniredis.RunT(t)
+	return func() Store {
+		redisClient := redis.NewClient(&redis.Options{Addr: redisServer.Addr()})
+		t.Cleanup(func() { redisClient.Close() })
+		return NewRedisStore(redisClient, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	}
+}
+
+// 分别使用每种存储运行测试
+func runWithStores(t *testing.T, test func(t *testing.T, connect func() Store)) {
+	for _, storage := range []string{StorageRedis, StorageMemory} {
+		t.Run(storage, func(t *testing.T) {
+			test(t, newTestStore(t, storage))
+		})
+	}
+}
+
+func newTestService(t *testing.T, connect func() Store) (*DiscoveryService, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	return newTestReplica(t, connect(), clock), clock
+}
+
+// 共享存储的发现服务副本
+func newTestReplica(t *testing.T, store Store, clock *fakeClock) *DiscoveryService {
+	return newTestReplicaWithConfig(t, store, clock, &config.DiscoveryConfig{LeaseTTL: 30, LoadBalance: "consistent_hash"})
+}
+
+func newTestReplicaWithConfig(t *testing.T, store Store, clock *fakeClock, conf *config.DiscoveryConfig) *DiscoveryService {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	return newDiscoveryService(ctx, logger, conf, store, grpc.WithTransportCredentials(insecure.NewCredentials()), clock.Now)
+}
+
+func TestLease(t *testing.T) {
+	runWithStores(t, testLease)
+}
+
+func testLease(t *testing.T, connect func() Store) {
+	serv, clock := newTestService(t, connect)
+	ctx := context.Background()
+
+	resp, err := serv.Register(ctx, &RegisterRequest{ServiceName: "imgateway", ServiceAddress: "10.0.0.1", ServicePort: "8086", Ttl: 10})
+	if err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	if len(resp.LeaseId) == 0 || resp.Ttl != 10 {
+		t.Fatalf("unexpected register response %v", resp)
+	}
+
+	// 到期前续约，租约延长
+	clock.Advance(8 * time.Second)
+	if _, err := serv.KeepAlive(ctx, &KeepAliveRequest{LeaseId: resp.LeaseId}); err != nil {
+		t.Fatalf("failed to keep alive: %v", err)
+	}
+	clock.Advance(8 * time.Second)
+	if purged, err := serv.purgeExpiredLeases(ctx); err != nil || purged != 0 {
+		t.Fatalf("expected no lease purged, got %d %v", purged, err)
+	}
+
+	// 停止续约后过期，实例从存储和本地移除，后台清理可能先于这里完成
+	clock.Advance(3 * time.Second)
+	if _, err := serv.purgeExpiredLeases(ctx); err != nil {
+		t.Fatalf("failed to purge expired leases: %v", err)
+	}
+	if services, _ := serv.store.Instances(ctx, "imgateway"); len(services) != 0 {
+		t.Fatalf("expected instance removed from store, got %v", services)
+	}
+	if services, _ := serv.getServiceLocal("imgateway"); len(services) != 0 {
+		t.Fatalf("expected instance removed locally, got %v", services)
+	}
+	_, err = serv.KeepAlive(ctx, &KeepAliveRequest{LeaseId: resp.LeaseId})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestLeaseReplaced(t *testing.T) {
+	runWithStores(t, testLeaseReplaced)
+}
+
+func testLeaseReplaced(t *testing.T, connect func() Store) {
+	serv, clock := newTestService(t, connect)
+	ctx := context.Background()
+	req := &RegisterRequest{ServiceName: "imgateway", ServiceAddress: "10.0.0.1", ServicePort: "8086"}
+
+	first, err := serv.Register(ctx, req)
+	if err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	if first.Ttl != 30 {
+		t.Fatalf("expected default ttl, got %d", first.Ttl)
+	}
+	// 实例重启后重新注册，旧租约失效
+	second, err := serv.Register(ctx, req)
+	if err != nil {
+		t.Fatalf("failed to register again: %v", err)
+	}
+	if _, err := serv.KeepAlive(ctx, &KeepAliveRequest{LeaseId: first.LeaseId}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected old lease not found, got %v", err)
+	}
+	if services, _ := serv.getServiceLocal("imgateway"); len(services) != 1 {
+		t.Fatalf("expected 1 instance, got %v", services)
+	}
+
+	clock.Advance(20 * time.Second)
+	if _, err := serv.KeepAlive(ctx, &KeepAliveRequest{LeaseId: second.LeaseId}); err != nil {
+		t.Fatalf("failed to keep alive: %v", err)
+	}
+	clock.Advance(20 * time.Second)
+	if purged, err := serv.purgeExpiredLeases(ctx); err != nil || purged != 0 {
+		t.Fatalf("expected no lease purged, got %d %v", purged, err)
+	}
+
+	// 注销后租约一并删除
+	if _, err := serv.Deregister(ctx, &DeregisterRequest{ServiceName: "imgateway", ServiceAddress: "10.0.0.1", ServicePort: "8086"}); err != nil {
+		t.Fatalf("failed to deregister: %v", err)
+	}
+	if _, err := serv.KeepAlive(ctx, &KeepAliveRequest{LeaseId: second.LeaseId}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected lease not found after deregister, got %v", err)
+	}
+}
+
+func TestGetServiceIPByLoad(t *testing.T) {
+	runWithStores(t, testGetServiceIPByLoad)
+}
+
+func testGetServiceIPByLoad(t *testing.T, connect func() Store) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	serv := newTestReplicaWithConfig(t, connect(), clock, &config.DiscoveryConfig{
+		LeaseTTL:           30,
+		LoadBalance:        "unknown",
+		ServiceLoadBalance: "imgateway=least_conn, apigateway=round_robin",
+	})
+	if serv.defaultStrategy != "consistent_hash" || serv.strategies["imgateway"] != "least_conn" || serv.strategies["apigateway"] != "round_robin" {
+		t.Fatalf("unexpected strategies %s %v", serv.defaultStrategy, serv.strategies)
+	}
+	ctx := context.Background()
+
+	first, err := serv.Register(ctx, &RegisterRequest{ServiceName: "imgateway", ServiceAddress: "10.0.0.1", ServicePort: "8086", Weight: 2, Load: 10})
+	if err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	second, err := serv.Register(ctx, &RegisterRequest{ServiceName: "imgateway", ServiceAddress: "10.0.0.2", ServicePort: "8086"})
+	if err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	// 单位权重负载 5 < 8
+	if _, err := serv.KeepAlive(ctx, &KeepAliveRequest{LeaseId: second.LeaseId, Load: 8}); err != nil {
+		t.Fatalf("failed to keep alive: %v", err)
+	}
+	if err := serv.reconcile(ctx); err != nil {
+		t.Fatalf("failed to sync services: %v", err)
+	}
+	resp, err := serv.GetService(ctx, &GetServiceRequest{ServiceName: "imgateway"})
+	if err != nil {
+		t.Fatalf("failed to get service: %v", err)
+	}
+	for _, info := range resp.ServiceInfo {
+		if info.ServiceAddress == "10.0.0.2" && (info.Weight != 1 || info.Load != 8) {
+			t.Fatalf("unexpected instance %v", info)
+		}
+	}
+	for range 3 {
+		ip, err := serv.GetServiceIP(ctx, &GetServiceIPRequest{ServiceName: "imgateway"})
+		if err != nil {
+			t.Fatalf("failed to get service ip: %v", err)
+		}
+		if ip.ServiceAddress != "10.0.0.1" {
+			t.Fatalf("expected least loaded instance, got %s", ip.ServiceAddress)
+		}
+	}
+
+	// 负载变化后选择另一个实例
+	if _, err := serv.KeepAlive(ctx, &KeepAliveRequest{LeaseId: first.LeaseId, Load: 40}); err != nil {
+		t.Fatalf("failed to keep alive: %v", err)
+	}
+	if err := serv.reconcile(ctx); err != nil {
+		t.Fatalf("failed to sync services: %v", err)
+	}
+	ip, err := serv.GetServiceIP(ctx, &GetServiceIPRequest{ServiceName: "imgateway"})
+	if err != nil {
+		t.Fatalf("failed to get service ip: %v", err)
+	}
+	if ip.ServiceAddress != "10.0.0.2" {
+		t.Fatalf("expected least loaded instance, got %s", ip.ServiceAddress)
+	}
+}
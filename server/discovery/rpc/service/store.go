@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// 注册信息存储类型
+const (
+	StorageRedis  = "redis"  // 多个发现服务副本共享
+	StorageMemory = "memory" // 只在本进程内共享，适合单副本部署和测试
+)
+
+var errLeaseNotFound = errors.New("lease not found")
+
+// Store 发现服务的注册信息存储，实例、租约和变更通知
+// 共享同一个Store的发现服务副本看到相同的实例，并能收到彼此的变更通知
+type Store interface {
+	// Register 注册实例并创建租约，替换实例原有的租约和元数据，返回实例是否为新增
+	Register(ctx context.Context, serviceName string, info *ServiceInfo, leaseId string, ttl int64, expireAt time.Time) (bool, error)
+	// KeepAlive 续约并更新实例负载，返回租约有效期，租约不存在或在now之前到期时返回errLeaseNotFound
+	KeepAlive(ctx context.Context, leaseId string, load int64, now time.Time) (int64, error)
+	// Deregister 注销实例并删除其租约，返回实例是否存在
+	Deregister(ctx context.Context, serviceName string, address string, port string) (bool, error)
+	// ExpireLeases 清除now之前到期的租约，租约仍是实例当前租约时同时移除实例，返回被移除实例的REMOVE事件
+	// 多个副本并发调用时每个实例只会被一个副本移除
+	ExpireLeases(ctx context.Context, now time.Time) ([]*WatchEvent, error)
+	// Services 返回有实例的服务名称
+	Services(ctx context.Context) ([]string, error)
+	// Instances 返回服务的全部实例，服务不存在时返回空列表
+	Instances(ctx context.Context, serviceName string) ([]*ServiceInfo, error)
+	// Publish 通知所有订阅者实例变更，包括本副本
+	Publish(ctx context.Context, event *WatchEvent) error
+	// Subscribe 订阅实例变更，订阅生效后返回，ctx结束或订阅断开后关闭channel
+	Subscribe(ctx context.Context) (<-chan *WatchEvent, error)
+}
+
+func instanceField(serviceName string, address string, port string) string {
+	return serviceName + "/" + address + ":" + port
+}
+
+func weightOf(weight int32) int32 {
+	return max(weight, 1)
+}
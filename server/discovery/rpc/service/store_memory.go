@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type memoryLease struct {
+	serviceName string
+	address     string
+	port        string
+	ttl         int64
+	expireAt    time.Time
+}
+
+type memorySubscriber struct {
+	events chan *WatchEvent
+	ctx    context.Context
+}
+
+// MemoryStore 注册信息保存在进程内存中，只有共享同一个MemoryStore的发现服务能看到彼此的实例
+// 适合单副本部署和测试，进程重启后实例需要重新注册
+type MemoryStore struct {
+	mu             sync.Mutex
+	services       map[string]map[string]*ServiceInfo // 服务名称 -> <address>:<port> -> 实例
+	leases         map[string]*memoryLease
+	instanceLeases map[string]string // <service_name>/<address>:<port> -> 实例当前的租约
+	subMu          sync.RWMutex
+	subscribers    map[*memorySubscriber]struct{}
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		services:       make(map[string]map[string]*ServiceInfo),
+		leases:         make(map[string]*memoryLease),
+		instanceLeases: make(map[string]string),
+		subscribers:    make(map[*memorySubscriber]struct{}),
+	}
+}
+
+func (m *MemoryStore) Register(ctx context.Context, serviceName string, info *ServiceInfo, leaseId string, ttl int64, expireAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	field := instanceField(serviceName, info.ServiceAddress, info.ServicePort)
+	if old, ok := m.instanceLeases[field]; ok {
+		delete(m.leases, old)
+	}
+	if m.services[serviceName] == nil {
+		m.services[serviceName] = make(map[string]*ServiceInfo)
+	}
+	member := info.ServiceAddress + ":" + info.ServicePort
+	_, exists := m.services[serviceName][member]
+	info = proto.Clone(info).(*ServiceInfo)
+	info.Weight = weightOf(info.Weight)
+	m.services[serviceName][member] = info
+	m.leases[leaseId] = &memoryLease{
+		serviceName: serviceName,
+		address:     info.ServiceAddress,
+		port:        info.ServicePort,
+		ttl:         ttl,
+		expireAt:    expireAt,
+	}
+	m.instanceLeases[field] = leaseId
+	return !exists, nil
+}
+
+func (m *MemoryStore) KeepAlive(ctx context.Context, leaseId string, load int64, now time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lease, ok := m.leases[leaseId]
+	if !ok || lease.expireAt.Before(now) {
+		return 0, errLeaseNotFound
+	}
+	lease.expireAt = now.Add(time.Duration(lease.ttl) * time.Second)
+	if info, ok := m.services[lease.serviceName][lease.address+":"+lease.port]; ok {
+		info.Load = load
+	}
+	return lease.ttl, nil
+}
+
+func (m *MemoryStore) Deregister(ctx context.Context, serviceName string, address string, port string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	field := instanceField(serviceName, address, port)
+	if leaseId, ok := m.instanceLeases[field]; ok {
+		delete(m.instanceLeases, field)
+		delete(m.leases, leaseId)
+	}
+	return m.removeInstance(serviceName, address+":"+port), nil
+}
+
+func (m *MemoryStore) removeInstance(serviceName string, member string) bool {
+	instances, ok := m.services[serviceName]
+	if !ok {
+		return false
+	}
+	if _, ok := instances[member]; !ok {
+		return false
+	}
+	delete(instances, member)
+	if len(instances) == 0 {
+		delete(m.services, serviceName)
+	}
+	return true
+}
+
+func (m *MemoryStore) ExpireLeases(ctx context.Context, now time.Time) ([]*WatchEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var events []*WatchEvent
+	for leaseId, lease := range m.leases {
+		if lease.expireAt.After(now) {
+			continue
+		}
+		delete(m.leases, leaseId)
+		field := instanceField(lease.serviceName, lease.address, lease.port)
+		if m.instanceLeases[field] != leaseId {
+			continue
+		}
+		delete(m.instanceLeases, field)
+		m.removeInstance(lease.serviceName, lease.address+":"+lease.port)
+		events = append(events, &WatchEvent{
+			Type:        EventType_EVENT_TYPE_REMOVE,
+			ServiceName: lease.serviceName,
+			ServiceInfo: []*ServiceInfo{{ServiceAddress: lease.address, ServicePort: lease.port}},
+		})
+	}
+	return events, nil
+}
+
+func (m *MemoryStore) Services(ctx context.Context) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, 0, len(m.services))
+	for name := range m.services {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (m *MemoryStore) Instances(ctx context.Context, serviceName string) ([]*ServiceInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	arr := make([]*ServiceInfo, 0, len(m.services[serviceName]))
+	for _, info := range m.services[serviceName] {
+		// 返回副本，调用方持有的实例不受之后的续约影响
+		arr = append(arr, proto.Clone(info).(*ServiceInfo))
+	}
+	slices.SortFunc(arr, func(a, b *ServiceInfo) int {
+		return strings.Compare(a.ServiceAddress+":"+a.ServicePort, b.ServiceAddress+":"+b.ServicePort)
+	})
+	return arr, nil
+}
+
+// Publish 依次推送给所有订阅者，订阅者的缓存写满时等待
+func (m *MemoryStore) Publish(ctx context.Context, event *WatchEvent) error {
+	m.subMu.RLock()
+	defer m.subMu.RUnlock()
+	for sub := range m.subscribers {
+		select {
+		case sub.events <- event:
+		case <-sub.ctx.Done():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) Subscribe(ctx context.Context) (<-chan *WatchEvent, error) {
+	sub := &memorySubscriber{
+		events: make(chan *WatchEvent, watchBufferSize),
+		ctx:    ctx,
+	}
+	m.subMu.Lock()
+	m.subscribers[sub] = struct{}{}
+	m.subMu.Unlock()
+	go func() {
+		<-ctx.Done()
+		// 持有写锁时没有正在进行的Publish，可以安全关闭
+		m.subMu.Lock()
+		defer m.subMu.Unlock()
+		delete(m.subscribers, sub)
+		close(sub.events)
+	}()
+	return sub.events, nil
+}
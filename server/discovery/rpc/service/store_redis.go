@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"maps"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+)
+
+// Redis key
+// im:discovery:<service_name> 服务实例集合 <address>:<port>，IPv6地址带方括号
+// im:discovery-services 有实例的服务名称集合，代替KEYS扫描
+// im:lease:<lease_id> 租约详情哈希，im:lease:expiry 租约到期时间(毫秒)有序集合
+// im:lease:instances 实例当前的租约 <service_name>/<address>:<port> -> lease_id
+// im:instance:<service_name>/<address>:<port> 实例状态哈希 权重、负载和meta:<key>元数据，随实例一起删除
+const (
+	serviceKeyPrefix  = "im:discovery:"
+	serviceIndexKey   = "im:discovery-services"
+	leaseKeyPrefix    = "im:lease:"
+	leaseExpiryKey    = "im:lease:expiry"
+	leaseInstancesKey = "im:lease:instances"
+	instanceKeyPrefix = "im:instance:"
+	// 发现服务副本之间同步实例变更的频道 消息内容为序列化的WatchEvent
+	eventChannel = "im:discovery-events"
+)
+
+// 实例状态哈希中元数据字段的前缀
+const metadataFieldPrefix = "meta:"
+
+// 注册实例并替换实例原有的租约和元数据，返回实例是否为新增
+var registerScript = redis.NewScript(`
+local old = redis.call('HGET', KEYS[4], ARGV[8])
+if old then
+	redis.call('DEL', ARGV[9] .. old)
+	redis.call('ZREM', KEYS[3], old)
+end
+local added = redis.call('SADD', KEYS[1], ARGV[1])
+redis.call('SADD', KEYS[6], ARGV[3])
+redis.call('HSET', KEYS[2], 'service_name', ARGV[3], 'service_address', ARGV[4], 'service_port', ARGV[5], 'ttl', ARGV[6])
+redis.call('ZADD', KEYS[3], ARGV[7], ARGV[2])
+redis.call('HSET', KEYS[4], ARGV[8], ARGV[2])
+redis.call('DEL', KEYS[5])
+redis.call('HSET', KEYS[5], 'weight', ARGV[10], 'load', ARGV[11])
+for i = 12, #ARGV, 2 do
+	redis.call('HSET', KEYS[5], 'meta:' .. ARGV[i], ARGV[i + 1])
+end
+return added
+`)
+
+// 续约未到期的租约并更新实例负载，租约不存在或已到期时返回nil
+var keepAliveScript = redis.NewScript(`
+local ttl = redis.call('HGET', KEYS[1], 'ttl')
+local score = redis.call('ZSCORE', KEYS[2], ARGV[1])
+if not ttl or not score or tonumber(score) < tonumber(ARGV[2]) then
+	return false
+end
+redis.call('ZADD', KEYS[2], string.format('%d', tonumber(ARGV[2]) + tonumber(ttl) * 1000), ARGV[1])
+local lease = redis.call('HMGET', KEYS[1], 'service_name', 'service_address', 'service_port')
+redis.call('HSET', ARGV[4] .. lease[1] .. '/' .. lease[2] .. ':' .. lease[3], 'load', ARGV[3])
+return tonumber(ttl)
+`)
+
+// 清除已到期的租约，租约仍是实例当前租约时同时移除实例，返回被移除的实例
+var expireScript = redis.NewScript(`
+local score = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if not score or tonumber(score) > tonumber(ARGV[2]) then
+	return false
+end
+local lease = redis.call('HMGET', KEYS[2], 'service_name', 'service_address', 'service_port')
+redis.call('ZREM', KEYS[1], ARGV[1])
+redis.call('DEL', KEYS[2])
+if not lease[1] then
+	return false
+end
+local field = lease[1] .. '/' .. lease[2] .. ':' .. lease[3]
+if redis.call('HGET', KEYS[3], field) ~= ARGV[1] then
+	return false
+end
+redis.call('HDEL', KEYS[3], field)
+-- 与net.JoinHostPort一致，IPv6地址加方括号
+local member = lease[2] .. ':' .. lease[3]
+if string.find(lease[2], ':', 1, true) then
+	member = '[' .. lease[2] .. ']:' .. lease[3]
+end
+redis.call('SREM', ARGV[3] .. lease[1], member)
+if redis.call('SCARD', ARGV[3] .. lease[1]) == 0 then
+	redis.call('SREM', KEYS[4], lease[1])
+end
+redis.call('DEL', ARGV[4] .. field)
+return lease
+`)
+
+// 注销实例并删除其租约，返回实例是否存在
+var deregisterScript = redis.NewScript(`
+local removed = redis.call('SREM', KEYS[1], ARGV[1])
+if redis.call('SCARD', KEYS[1]) == 0 then
+	redis.call('SREM', KEYS[5], ARGV[4])
+end
+redis.call('DEL', KEYS[4])
+local lease = redis.call('HGET', KEYS[3], ARGV[2])
+if lease then
+	redis.call('HDEL', KEYS[3], ARGV[2])
+	redis.call('ZREM', KEYS[2], lease)
+	redis.call('DEL', ARGV[3] .. lease)
+end
+return removed
+`)
+
+// RedisStore 注册信息保存在Redis中，变更通过发布订阅同步到所有副本
+type RedisStore struct {
+	client *redis.Client
+	logger *slog.Logger
+}
+
+func NewRedisStore(client *redis.Client, logger *slog.Logger) *RedisStore {
+	return &RedisStore{client: client, logger: logger}
+}
+
+// RebuildIndex 扫描已有的服务实例集合补全服务名称索引，兼容没有索引时写入的数据
+func (r *RedisStore) RebuildIndex(ctx context.Context) error {
+	iter := r.client.Scan(ctx, 0, serviceKeyPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		if err := r.client.SAdd(ctx, serviceIndexKey, strings.TrimPrefix(iter.Val(), serviceKeyPrefix)).Err(); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
+
+func instanceKey(serviceName string, address string, port string) string {
+	return instanceKeyPrefix + instanceField(serviceName, address, port)
+}
+
+func (r *RedisStore) Register(ctx context.Context, serviceName string, info *ServiceInfo, leaseId string, ttl int64, expireAt time.Time) (bool, error) {
+	args := []any{
+		net.JoinHostPort(info.ServiceAddress, info.ServicePort), leaseId, serviceName, info.ServiceAddress, info.ServicePort, ttl, expireAt.UnixMilli(),
+		instanceField(serviceName, info.ServiceAddress, info.ServicePort), leaseKeyPrefix, weightOf(info.Weight), info.Load,
+	}
+	for _, key := range slices.Sorted(maps.Keys(info.Metadata)) {
+		args = append(args, key, info.Metadata[key])
+	}
+	added, err := registerScript.Run(ctx, r.client,
+		[]string{serviceKeyPrefix + serviceName, leaseKeyPrefix + leaseId, leaseExpiryKey, leaseInstancesKey, instanceKey(serviceName, info.ServiceAddress, info.ServicePort), serviceIndexKey},
+		args...,
+	).Int64()
+	return added > 0, err
+}
+
+func (r *RedisStore) KeepAlive(ctx context.Context, leaseId string, load int64, now time.Time) (int64, error) {
+	ttl, err := keepAliveScript.Run(ctx, r.client, []string{leaseKeyPrefix + leaseId, leaseExpiryKey}, leaseId, now.UnixMilli(), load, instanceKeyPrefix).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, errLeaseNotFound
+	}
+	return ttl, err
+}
+
+func (r *RedisStore) Deregister(ctx context.Context, serviceName string, address string, port string) (bool, error) {
+	removed, err := deregisterScript.Run(ctx, r.client,
+		[]string{serviceKeyPrefix + serviceName, leaseExpiryKey, leaseInstancesKey, instanceKey(serviceName, address, port), serviceIndexKey},
+		net.JoinHostPort(address, port), instanceField(serviceName, address, port), leaseKeyPrefix, serviceName,
+	).Int64()
+	return removed > 0, err
+}
+
+func (r *RedisStore) ExpireLeases(ctx context.Context, now time.Time) ([]*WatchEvent, error) {
+	leaseIds, err := r.client.ZRangeByScore(ctx, leaseExpiryKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now.UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	var events []*WatchEvent
+	for _, leaseId := range leaseIds {
+		lease, err := expireScript.Run(ctx, r.client,
+			[]string{leaseExpiryKey, leaseKeyPrefix + leaseId, leaseInstancesKey, serviceIndexKey},
+			leaseId, now.UnixMilli(), serviceKeyPrefix, instanceKeyPrefix,
+		).StringSlice()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return events, err
+		}
+		events = append(events, &WatchEvent{
+			Type:        EventType_EVENT_TYPE_REMOVE,
+			ServiceName: lease[0],
+			ServiceInfo: []*ServiceInfo{{ServiceAddress: lease[1], ServicePort: lease[2]}},
+		})
+	}
+	return events, nil
+}
+
+func (r *RedisStore) Services(ctx context.Context) ([]string, error) {
+	return r.client.SMembers(ctx, serviceIndexKey).Result()
+}
+
+func (r *RedisStore) Instances(ctx context.Context, serviceName string) ([]*ServiceInfo, error) {
+	members, err := r.client.SMembers(ctx, serviceKeyPrefix+serviceName).Result()
+	if err != nil {
+		return nil, err
+	}
+	arr := make([]*ServiceInfo, 0, len(members))
+	for _, member := range members {
+		address, port, err := net.SplitHostPort(member)
+		if err != nil {
+			r.logger.Warn("invalid instance member", "service", serviceName, "member", member, "error", err)
+			continue
+		}
+		arr = append(arr, &ServiceInfo{
+			ServiceAddress: address,
+			ServicePort:    port,
+		})
+	}
+	if len(arr) == 0 {
+		return arr, nil
+	}
+	// 读取实例的权重、负载和元数据
+	pipe := r.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, 0, len(arr))
+	for _, info := range arr {
+		cmds = append(cmds, pipe.HGetAll(ctx, instanceKey(serviceName, info.ServiceAddress, info.ServicePort)))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	for i, cmd := range cmds {
+		parseInstanceState(arr[i], cmd.Val())
+	}
+	return arr, nil
+}
+
+// 解析实例状态哈希
+func parseInstanceState(info *ServiceInfo, state map[string]string) {
+	weight, _ := strconv.ParseInt(state["weight"], 10, 32)
+	info.Weight = weightOf(int32(weight))
+	info.Load, _ = strconv.ParseInt(state["load"], 10, 64)
+	for field, value := range state {
+		if key, ok := strings.CutPrefix(field, metadataFieldPrefix); ok {
+			if info.Metadata == nil {
+				info.Metadata = make(map[string]string)
+			}
+			info.Metadata[key] = value
+		}
+	}
+}
+
+func (r *RedisStore) Publish(ctx context.Context, event *WatchEvent) error {
+	data, err := proto.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return r.client.Publish(ctx, eventChannel, data).Err()
+}
+
+func (r *RedisStore) Subscribe(ctx context.Context) (<-chan *WatchEvent, error) {
+	pubsub := r.client.Subscribe(ctx, eventChannel)
+	// 等待订阅生效，避免订阅前发布的事件丢失
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+	events := make(chan *WatchEvent, watchBufferSize)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+		ch := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				event := &WatchEvent{}
+				if err := proto.Unmarshal([]byte(msg.Payload), event); err != nil {
+					r.logger.Error("failed to unmarshal watch event", "error", err)
+					continue
+				}
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
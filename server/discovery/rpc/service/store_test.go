@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestStore(t *testing.T) {
+	runWithStores(t, testStore)
+}
+
+func testStore(t *testing.T, connect func() Store) {
+	store := connect()
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	info := &ServiceInfo{ServiceAddress: "10.0.0.1", ServicePort: "8086", Metadata: map[string]string{MetadataZone: "a"}}
+
+	added, err := store.Register(ctx, "imgateway", info, "lease-1", 10, now.Add(10*time.Second))
+	if err != nil || !added {
+		t.Fatalf("expected instance added, got %v %v", added, err)
+	}
+	if services, err := store.Services(ctx); err != nil || !slices.Equal(services, []string{"imgateway"}) {
+		t.Fatalf("unexpected services %v %v", services, err)
+	}
+	instances, err := store.Instances(ctx, "imgateway")
+	if err != nil || len(instances) != 1 || instances[0].Weight != 1 || instances[0].Metadata[MetadataZone] != "a" {
+		t.Fatalf("unexpected instances %v %v", instances, err)
+	}
+	if instances, err := store.Instances(ctx, "apigateway"); err != nil || len(instances) != 0 {
+		t.Fatalf("expected no instance, got %v %v", instances, err)
+	}
+
+	// 续约更新负载
+	if ttl, err := store.KeepAlive(ctx, "lease-1", 5, now.Add(8*time.Second)); err != nil || ttl != 10 {
+		t.Fatalf("failed to keep alive: %d %v", ttl, err)
+	}
+	if instances, _ := store.Instances(ctx, "imgateway"); instances[0].Load != 5 {
+		t.Fatalf("expected load updated, got %v", instances)
+	}
+	if _, err := store.KeepAlive(ctx, "lease-1", 5, now.Add(19*time.Second)); !errors.Is(err, errLeaseNotFound) {
+		t.Fatalf("expected expired lease not found, got %v", err)
+	}
+
+	// 重新注册替换租约，旧租约到期不影响实例
+	added, err = store.Register(ctx, "imgateway", &ServiceInfo{ServiceAddress: "10.0.0.1", ServicePort: "8086"}, "lease-2", 10, now.Add(30*time.Second))
+	if err != nil || added {
+		t.Fatalf("expected instance updated, got %v %v", added, err)
+	}
+	if events, err := store.ExpireLeases(ctx, now.Add(20*time.Second)); err != nil || len(events) != 0 {
+		t.Fatalf("expected no instance expired, got %v %v", events, err)
+	}
+	if instances, _ := store.Instances(ctx, "imgateway"); len(instances) != 1 || len(instances[0].Metadata) != 0 {
+		t.Fatalf("expected metadata replaced, got %v", instances)
+	}
+	events, err := store.ExpireLeases(ctx, now.Add(30*time.Second))
+	if err != nil || len(events) != 1 || events[0].ServiceName != "imgateway" || events[0].ServiceInfo[0].ServiceAddress != "10.0.0.1" {
+		t.Fatalf("expected instance expired, got %v %v", events, err)
+	}
+	if services, _ := store.Services(ctx); len(services) != 0 {
+		t.Fatalf("expected no service, got %v", services)
+	}
+
+	// 注销
+	if _, err := store.Register(ctx, "imgateway", info, "lease-3", 10, now.Add(40*time.Second)); err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	if removed, err := store.Deregister(ctx, "imgateway", "10.0.0.1", "8086"); err != nil || !removed {
+		t.Fatalf("expected instance removed, got %v %v", removed, err)
+	}
+	if removed, _ := store.Deregister(ctx, "imgateway", "10.0.0.1", "8086"); removed {
+		t.Fatalf("expected instance already removed")
+	}
+	if _, err := store.KeepAlive(ctx, "lease-3", 0, now); !errors.Is(err, errLeaseNotFound) {
+		t.Fatalf("expected lease removed with instance, got %v", err)
+	}
+	if services, _ := store.Services(ctx); len(services) != 0 {
+		t.Fatalf("expected no service, got %v", services)
+	}
+}
+
+// IPv6地址中的冒号不影响解析实例地址和端口
+func TestStoreIPv6(t *testing.T) {
+	runWithStores(t, testStoreIPv6)
+}
+
+func testStoreIPv6(t *testing.T, connect func() Store) {
+	store := connect()
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	for i, address := range []string{"::1", "fe80::1"} {
+		if _, err := store.Register(ctx, "imgateway", &ServiceInfo{ServiceAddress: address, ServicePort: "8086", Load: int64(i)}, "lease-"+address, 10, now.Add(10*time.Second)); err != nil {
+			t.Fatalf("failed to register %s: %v", address, err)
+		}
+	}
+	instances, err := store.Instances(ctx, "imgateway")
+	if err != nil || len(instances) != 2 {
+		t.Fatalf("unexpected instances %v %v", instances, err)
+	}
+	for _, instance := range instances {
+		if (instance.ServiceAddress != "::1" && instance.ServiceAddress != "fe80::1") || instance.ServicePort != "8086" {
+			t.Fatalf("unexpected instance %v", instance)
+		}
+	}
+	if ttl, err := store.KeepAlive(ctx, "lease-::1", 5, now.Add(5*time.Second)); err != nil || ttl != 10 {
+		t.Fatalf("failed to keep alive: %d %v", ttl, err)
+	}
+
+	if removed, err := store.Deregister(ctx, "imgateway", "::1", "8086"); err != nil || !removed {
+		t.Fatalf("expected instance removed, got %v %v", removed, err)
+	}
+	events, err := store.ExpireLeases(ctx, now.Add(10*time.Second))
+	if err != nil || len(events) != 1 || events[0].ServiceInfo[0].ServiceAddress != "fe80::1" || events[0].ServiceInfo[0].ServicePort != "8086" {
+		t.Fatalf("expected instance expired, got %v %v", events, err)
+	}
+	if instances, _ := store.Instances(ctx, "imgateway"); len(instances) != 0 {
+		t.Fatalf("expected no instance, got %v", instances)
+	}
+	if services, _ := store.Services(ctx); len(services) != 0 {
+		t.Fatalf("expected no service, got %v", services)
+	}
+}
+
+func TestStorePubSub(t *testing.T) {
+	runWithStores(t, func(t *testing.T, connect func() Store) {
+		publisher, subscriber := connect(), connect()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		subCtx, subCancel := context.WithCancel(ctx)
+		events, err := subscriber.Subscribe(subCtx)
+		if err != nil {
+			t.Fatalf("failed to subscribe: %v", err)
+		}
+		if err := publisher.Publish(ctx, &WatchEvent{Type: EventType_EVENT_TYPE_ADD, ServiceName: "imgateway"}); err != nil {
+			t.Fatalf("failed to publish: %v", err)
+		}
+		select {
+		case event := <-events:
+			if event.Type != EventType_EVENT_TYPE_ADD || event.ServiceName != "imgateway" {
+				t.Fatalf("unexpected event %v", event)
+			}
+		case <-ctx.Done():
+			t.Fatalf("event not received")
+		}
+
+		// 取消订阅后关闭channel
+		subCancel()
+		for {
+			select {
+			case _, ok := <-events:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				t.Fatalf("events channel not closed")
+			}
+		}
+	})
+}
+
+// 没有服务名称索引时写入的数据在启动时补全索引
+func TestRedisStoreRebuildIndex(t *testing.T) {
+	redisServer := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: redisServer.Addr()})
+	defer redisClient.Close()
+	ctx := context.Background()
+	redisClient.SAdd(ctx, serviceKeyPrefix+"imgateway", "10.0.0.1:8086")
+	redisClient.SAdd(ctx, serviceKeyPrefix+"apigateway", "10.0.0.2:8088")
+
+	store := NewRedisStore(redisClient, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	if err := store.RebuildIndex(ctx); err != nil {
+		t.Fatalf("failed to rebuild index: %v", err)
+	}
+	services, err := store.Services(ctx)
+	if err != nil {
+		t.Fatalf("failed to get services: %v", err)
+	}
+	slices.Sort(services)
+	if !slices.Equal(services, []string{"apigateway", "imgateway"}) {
+		t.Fatalf("unexpected services %v", services)
+	}
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 每个订阅者缓存的事件数，写满时断开订阅者，由其重新订阅获取快照
+const watchBufferSize = 64
+
+// 一个Watch请求
+type watcher struct {
+	events chan *WatchEvent
+	done   chan struct{} // 订阅被服务端结束
+	err    error         // 结束原因
+	once   sync.Once
+}
+
+func (w *watcher) stop(err error) {
+	w.once.Do(func() {
+		w.err = err
+		close(w.done)
+	})
+}
+
+// Watch 先发送服务的全部实例，之后推送实例变更，直到客户端取消
+func (s *DiscoveryService) Watch(req *WatchRequest, stream Discovery_WatchServer) error {
+	if !s.ready.Load() {
+		return status.Errorf(codes.Unavailable, "service not ready")
+	}
+	if len(req.ServiceName) == 0 {
+		return status.Errorf(codes.InvalidArgument, "service name is required")
+	}
+	// 先加入订阅再读取快照，快照之后的变更不会丢失，快照之前的变更可能重复推送
+	w := s.addWatcher(req.ServiceName)
+	defer s.removeWatcher(req.ServiceName, w)
+	services, err := s.store.Instances(stream.Context(), req.ServiceName)
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(&WatchEvent{
+		Type:        EventType_EVENT_TYPE_SNAPSHOT,
+		ServiceName: req.ServiceName,
+		ServiceInfo: s.withHealth(req.ServiceName, services),
+	}); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-w.done:
+			return w.err
+		case event := <-w.events:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// CloseWatchers 结束所有订阅，退出前调用，客户端会重新订阅其他副本
+func (s *DiscoveryService) CloseWatchers() {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+	for name, watchers := range s.watchers {
+		for w := range watchers {
+			w.stop(status.Errorf(codes.Unavailable, "discovery server shutting down"))
+		}
+		delete(s.watchers, name)
+	}
+}
+
+func (s *DiscoveryService) addWatcher(serviceName string) *watcher {
+	w := &watcher{
+		events: make(chan *WatchEvent, watchBufferSize),
+		done:   make(chan struct{}),
+	}
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+	if s.watchers[serviceName] == nil {
+		s.watchers[serviceName] = make(map[*watcher]struct{})
+	}
+	s.watchers[serviceName][w] = struct{}{}
+	return w
+}
+
+func (s *DiscoveryService) removeWatcher(serviceName string, w *watcher) {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+	delete(s.watchers[serviceName], w)
+	if len(s.watchers[serviceName]) == 0 {
+		delete(s.watchers, serviceName)
+	}
+}
+
+// 推送事件给本副本的订阅者，不阻塞
+func (s *DiscoveryService) broadcast(event *WatchEvent) {
+	if event.Type != EventType_EVENT_TYPE_REMOVE {
+		event = &WatchEvent{
+			Type:        event.Type,
+			ServiceName: event.ServiceName,
+			ServiceInfo: s.withHealth(event.ServiceName, event.ServiceInfo),
+		}
+	}
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+	for w := range s.watchers[event.ServiceName] {
+		select {
+		case w.events <- event:
+		default:
+			s.logger.Warn("watcher too slow, disconnect", "service", event.ServiceName)
+			delete(s.watchers[event.ServiceName], w)
+			w.stop(status.Errorf(codes.ResourceExhausted, "watcher too slow"))
+		}
+	}
+}
+
+// 本副本产生的变更先更新本地缓存并推送给本副本的订阅者，再广播给其他副本
+// 本副本收到自己发布的事件时缓存已是最新，不会重复推送
+func (s *DiscoveryService) applyLocal(ctx context.Context, eventType EventType, serviceName string, info *ServiceInfo) {
+	s.applyEvent(&WatchEvent{
+		Type:        eventType,
+		ServiceName: serviceName,
+		ServiceInfo: []*ServiceInfo{info},
+	})
+	s.publishEvent(ctx, eventType, serviceName, info)
+}
+
+// 广播实例变更给所有副本，包括本副本，发布失败不影响注册结果
+func (s *DiscoveryService) publishEvent(ctx context.Context, eventType EventType, serviceName string, info *ServiceInfo) {
+	err := s.store.Publish(ctx, &WatchEvent{
+		Type:        eventType,
+		ServiceName: serviceName,
+		ServiceInfo: []*ServiceInfo{info},
+	})
+	if err != nil {
+		s.logger.Error("failed to publish watch event", "error", err, "service", serviceName)
+	}
+}
+
+// 更新本地缓存，只把缓存确实变化的实例推送给订阅者
+// 定期校正可能先于事件应用了同一变更，此时不再重复推送
+func (s *DiscoveryService) applyEvent(event *WatchEvent) {
+	changed := make([]*ServiceInfo, 0, len(event.ServiceInfo))
+	for _, service := range event.ServiceInfo {
+		var ok bool
+		switch event.Type {
+		case EventType_EVENT_TYPE_ADD, EventType_EVENT_TYPE_UPDATE:
+			ok = s.addServiceLocal(event.ServiceName, service)
+		case EventType_EVENT_TYPE_REMOVE:
+			ok = s.removeServiceLocal(event.ServiceName, service.ServiceAddress, service.ServicePort)
+		}
+		if ok {
+			changed = append(changed, service)
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+	s.broadcast(&WatchEvent{
+		Type:        event.Type,
+		ServiceName: event.ServiceName,
+		ServiceInfo: changed,
+	})
+}
+
+// 订阅持续到服务退出
+func (s *DiscoveryService) subscribeEvents() (<-chan *WatchEvent, error) {
+	return s.store.Subscribe(s.ctx)
+}
+
+// 接收所有副本发布的实例变更，订阅失败或断开时每秒重试
+func (s *DiscoveryService) receiveEvents(events <-chan *WatchEvent) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		for events == nil {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			var err error
+			if events, err = s.subscribeEvents(); err != nil {
+				s.logger.Error("failed to subscribe discovery events", "error", err)
+			}
+		}
+		select {
+		case <-s.ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				s.logger.Warn("discovery events subscription closed, resubscribe")
+				events = nil
+				continue
+			}
+			s.applyEvent(event)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func serveTestReplica(t *testing.T, serv *DiscoveryService) DiscoveryClient {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := grpc.NewServer()
+	RegisterDiscoveryServer(server, serv)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewDiscoveryClient(conn)
+}
+
+func recvEvent(t *testing.T, stream Discovery_WatchClient, eventType EventType, instances int) *WatchEvent {
+	t.Helper()
+	event, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("failed to receive event: %v", err)
+	}
+	if event.Type != eventType || len(event.ServiceInfo) != instances {
+		t.Fatalf("expected %s with %d instances, got %v", eventType, instances, event)
+	}
+	return event
+}
+
+// 注册到一个副本，订阅另一个副本
+func TestWatch(t *testing.T) {
+	runWithStores(t, testWatch)
+}
+
+func testWatch(t *testing.T, connect func() Store) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	writer := newTestReplica(t, connect(), clock)
+	reader := newTestReplica(t, connect(), clock)
+	client := serveTestReplica(t, reader)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	first := &RegisterRequest{ServiceName: "imgateway", ServiceAddress: "10.0.0.1", ServicePort: "8086", Ttl: 10}
+	if _, err := writer.Register(ctx, first); err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	stream, err := client.Watch(ctx, &WatchRequest{ServiceName: "imgateway"})
+	if err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+	snapshot := recvEvent(t, stream, EventType_EVENT_TYPE_SNAPSHOT, 1)
+	if snapshot.ServiceInfo[0].ServiceAddress != "10.0.0.1" {
+		t.Fatalf("unexpected snapshot %v", snapshot)
+	}
+
+	second := &RegisterRequest{ServiceName: "imgateway", ServiceAddress: "10.0.0.2", ServicePort: "8086", Ttl: 10}
+	if _, err := writer.Register(ctx, second); err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	event := recvEvent(t, stream, EventType_EVENT_TYPE_ADD, 1)
+	if event.ServiceInfo[0].ServiceAddress != "10.0.0.2" {
+		t.Fatalf("unexpected event %v", event)
+	}
+	// 订阅副本的本地缓存随事件更新
+	if services, _ := reader.getServiceLocal("imgateway"); len(services) != 2 {
+		t.Fatalf("expected 2 instances in reader, got %v", services)
+	}
+
+	// 权重或元数据变化时推送UPDATE
+	first.Metadata = map[string]string{MetadataZone: "b"}
+	if _, err := writer.Register(ctx, first); err != nil {
+		t.Fatalf("failed to register again: %v", err)
+	}
+	recvEvent(t, stream, EventType_EVENT_TYPE_UPDATE, 1)
+
+	if _, err := writer.Deregister(ctx, &DeregisterRequest{ServiceName: "imgateway", ServiceAddress: "10.0.0.1", ServicePort: "8086"}); err != nil {
+		t.Fatalf("failed to deregister: %v", err)
+	}
+	event = recvEvent(t, stream, EventType_EVENT_TYPE_REMOVE, 1)
+	if event.ServiceInfo[0].ServiceAddress != "10.0.0.1" {
+		t.Fatalf("unexpected event %v", event)
+	}
+
+	// 租约过期
+	clock.Advance(11 * time.Second)
+	event = recvEvent(t, stream, EventType_EVENT_TYPE_REMOVE, 1)
+	if event.ServiceInfo[0].ServiceAddress != "10.0.0.2" {
+		t.Fatalf("unexpected event %v", event)
+	}
+
+	// 退出时结束订阅
+	reader.CloseWatchers()
+	if _, err := stream.Recv(); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected unavailable, got %v", err)
+	}
+}
+
+func TestWatchSlowConsumer(t *testing.T) {
+	serv, _ := newTestService(t, newTestStore(t, StorageMemory))
+	w := serv.addWatcher("imgateway")
+	for i := 0; i <= watchBufferSize; i++ {
+		serv.broadcast(&WatchEvent{Type: EventType_EVENT_TYPE_ADD, ServiceName: "imgateway"})
+	}
+	select {
+	case <-w.done:
+	default:
+		t.Fatalf("expected slow watcher stopped")
+	}
+	if status.Code(w.err) != codes.ResourceExhausted {
+		t.Fatalf("expected resource exhausted, got %v", w.err)
+	}
+	serv.watchMu.Lock()
+	defer serv.watchMu.Unlock()
+	if len(serv.watchers["imgateway"]) != 0 {
+		t.Fatalf("expected slow watcher removed")
+	}
+}
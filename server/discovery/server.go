@@ -3,7 +3,9 @@ package discovery
 import (
 	"context"
 	"im/pkg/config"
+	"im/pkg/graceful"
 	"im/pkg/grpcmiddreware"
+	"im/pkg/xtls"
 	"im/server/discovery/rpc/service"
 	"log"
 	"log/slog"
@@ -18,7 +20,8 @@ import (
 //go:generate protoc --go_out=rpc/service --go-grpc_out=rpc/service rpc/service/discovery.proto
 
 func Run() {
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 	conf := config.NewConf().GetDiscoveryConfig()
 
 	level := slog.LevelInfo
@@ -36,21 +39,43 @@ func Run() {
 	fr.Start()
 	defer fr.Stop()
 
+	creds, err := xtls.ServerOption(ctx, conf.TLSConfig, logger)
+	if err != nil {
+		log.Fatalf("failed to load tls: %v", err)
+	}
 	server := grpc.NewServer(
+		creds,
 		grpc.ChainUnaryInterceptor(grpcmiddreware.MonitorUnaryInterceptor(ctx,fr,logger), grpcmiddreware.TraceUnaryInterceptor(), grpcmiddreware.LogUnaryInterceptor(logger)),
 	)
 
-	service.RegisterDiscoveryServer(server, service.NewDiscoveryService(ctx, logger, conf))
+	// 健康检查连接实例时使用
+	dialOption, err := xtls.DialOption(ctx, conf.TLSConfig, logger)
+	if err != nil {
+		log.Fatalf("failed to load tls: %v", err)
+	}
+	discoveryService := service.NewDiscoveryService(ctx, logger, conf, dialOption)
+	service.RegisterDiscoveryServer(server, discoveryService)
+	healthServer := graceful.RegisterHealth(server)
 
 	listener, err := net.Listen("tcp", conf.Addr)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 	logger.Info("discovery server listening", "address", conf.Addr)
-	if err := server.Serve(listener); err != nil {
-		log.Fatalf("failed to serve: %v", err)
-	}
-
-	// TODO	处理信号
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			log.Fatalf("failed to serve: %v", err)
+		}
+	}()
 
+	signalCtx, stop := graceful.NotifyContext(ctx)
+	defer stop()
+	<-signalCtx.Done()
+	logger.Info("discovery server shutting down")
+	healthServer.Shutdown()
+	// Watch请求不会自行结束，先断开订阅者
+	discoveryService.CloseWatchers()
+	if !graceful.StopGRPC(server, time.Duration(conf.ShutdownTimeout)*time.Second) {
+		logger.Warn("discovery server shutdown timeout, force stopped")
+	}
 }
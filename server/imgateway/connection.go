@@ -1,71 +1,318 @@
 package imgateway
 
 import (
+	"im/pkg/plato"
+	"im/server/imgateway/rpc/service"
 	"net"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Session struct {
 	user_uuids []string
+	expireAt   time.Time
+}
+
+// 统计收发字节数的连接
+type statConn struct {
+	net.Conn
+	bytesIn  atomic.Int64
+	bytesOut atomic.Int64
+}
+
+func newStatConn(conn net.Conn) *statConn {
+	return &statConn{Conn: conn}
+}
+
+func (c *statConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.bytesIn.Add(int64(n))
+	return n, err
+}
+
+func (c *statConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.bytesOut.Add(int64(n))
+	return n, err
 }
 
 type Connection struct {
-	user_uuid string
-	conn      net.Conn
+	conn_uuid   string
+	user_uuid   string
+	device_id   string
+	platform    string
+	token       string // 认证时的令牌，代表用户调用API网关
+	conn        *statConn
+	writer      *connWriter
+	encoder     *plato.Encoder // 写入writer的队列，不会阻塞调用方
+	connectedAt time.Time
+	lastActive  atomic.Int64 // 最后一次收到数据的时间 UnixNano
+
+	pendingLocker sync.Mutex
+	pending       map[string]*PendingMessage // 等待客户端ACK的下行消息 message_uuid -> 消息
+	resync        map[string]int64           // 写队列满时丢弃的消息 会话UUID -> 最小序列号
+}
+
+// PendingMessage 已下发但未被确认的消息
+type PendingMessage struct {
+	msg     *plato.MessageDownLink
+	retries int
+}
+
+// AddPending 加入待确认窗口，窗口已满时返回false
+func (c *Connection) AddPending(msg *plato.MessageDownLink, window int) bool {
+	c.pendingLocker.Lock()
+	defer c.pendingLocker.Unlock()
+	if _, ok := c.pending[msg.GetMessageUuid()]; ok {
+		return true
+	}
+	if len(c.pending) >= window {
+		return false
+	}
+	c.pending[msg.GetMessageUuid()] = &PendingMessage{msg: msg}
+	return true
+}
+
+// Ack 确认消息，返回消息是否在窗口中
+func (c *Connection) Ack(message_uuid string) bool {
+	c.pendingLocker.Lock()
+	defer c.pendingLocker.Unlock()
+	_, ok := c.pending[message_uuid]
+	delete(c.pending, message_uuid)
+	return ok
+}
+
+// Retry 记录一次重传，返回待重传的消息及已重传次数，消息已确认时返回nil
+func (c *Connection) Retry(message_uuid string) (*plato.MessageDownLink, int) {
+	c.pendingLocker.Lock()
+	defer c.pendingLocker.Unlock()
+	pending, ok := c.pending[message_uuid]
+	if !ok {
+		return nil, 0
+	}
+	pending.retries++
+	return pending.msg, pending.retries
+}
+
+// MarkResync 记录被丢弃的消息，返回是否为第一条待补拉记录
+func (c *Connection) MarkResync(session_uuid string, seq_id int64) bool {
+	c.pendingLocker.Lock()
+	defer c.pendingLocker.Unlock()
+	first := len(c.resync) == 0
+	if last, ok := c.resync[session_uuid]; !ok || seq_id < last {
+		c.resync[session_uuid] = seq_id
+	}
+	return first
+}
+
+// TakeResync 取出所有待补拉记录
+func (c *Connection) TakeResync() map[string]int64 {
+	c.pendingLocker.Lock()
+	defer c.pendingLocker.Unlock()
+	resync := c.resync
+	c.resync = make(map[string]int64)
+	return resync
+}
+
+// PendingCount 待确认消息数
+func (c *Connection) PendingCount() int {
+	c.pendingLocker.Lock()
+	defer c.pendingLocker.Unlock()
+	return len(c.pending)
+}
+
+// Info 连接详情快照
+func (c *Connection) Info() *service.ConnectionInfo {
+	return &service.ConnectionInfo{
+		ConnUuid:     c.conn_uuid,
+		UserUuid:     c.user_uuid,
+		DeviceId:     c.device_id,
+		Platform:     c.platform,
+		RemoteAddr:   c.conn.RemoteAddr().String(),
+		ConnectedAt:  c.connectedAt.UnixMilli(),
+		LastActiveAt: time.Unix(0, c.lastActive.Load()).UnixMilli(),
+		BytesIn:      c.conn.bytesIn.Load(),
+		BytesOut:     c.conn.bytesOut.Load(),
+		Pending:      int64(c.PendingCount()),
+		Queued:       int64(c.writer.Len()),
+	}
+}
+
+// Touch 刷新连接活跃时间
+func (c *Connection) Touch() {
+	c.lastActive.Store(time.Now().UnixNano())
+}
+
+// IdleDuration 距离最后一次收到数据的时长
+func (c *Connection) IdleDuration() time.Duration {
+	return time.Since(time.Unix(0, c.lastActive.Load()))
 }
 
 type ConnManager struct {
 	locker        sync.RWMutex
 	sessions      map[string]*Session
 	connections   map[string]*Connection
-	user_conn_map map[string]string
+	user_conn_map map[string]map[string]struct{} // 用户UUID -> 该用户所有设备的连接UUID
+
+	sessionTTL    time.Duration // 会话成员缓存有效期
+	sessionEpoch  int64         // 每次失效时递增，防止失效前查询到的旧成员列表写回缓存
+	sessionHits   atomic.Int64
+	sessionMisses atomic.Int64
 }
 
-func NewConnManager() *ConnManager {
+func NewConnManager(sessionTTL time.Duration) *ConnManager {
 	return &ConnManager{
+		sessionTTL:    sessionTTL,
 		sessions:      make(map[string]*Session),
 		connections:   make(map[string]*Connection),
-		user_conn_map: make(map[string]string),
+		user_conn_map: make(map[string]map[string]struct{}),
 	}
 }
 
-func (c *ConnManager) AddConnection(user_uuid string, conn net.Conn) string {
+func (c *ConnManager) AddConnection(user_uuid string, device_id string, platform string, token string, conn *statConn, writer *connWriter) *Connection {
 	conn_uuid := uuid.New().String()
+	connection := &Connection{
+		conn_uuid:   conn_uuid,
+		user_uuid:   user_uuid,
+		device_id:   device_id,
+		platform:    platform,
+		token:       token,
+		conn:        conn,
+		writer:      writer,
+		encoder:     plato.NewEncoder(writer),
+		connectedAt: time.Now(),
+		pending:     make(map[string]*PendingMessage),
+		resync:      make(map[string]int64),
+	}
+	connection.Touch()
 	c.locker.Lock()
 	defer c.locker.Unlock()
-	c.connections[conn_uuid] = &Connection{
-		user_uuid: user_uuid,
-		conn:      conn,
+	c.connections[conn_uuid] = connection
+	conn_uuids, ok := c.user_conn_map[user_uuid]
+	if !ok {
+		conn_uuids = make(map[string]struct{})
+		c.user_conn_map[user_uuid] = conn_uuids
 	}
-	c.user_conn_map[user_uuid] = conn_uuid
-	return conn_uuid
+	conn_uuids[conn_uuid] = struct{}{}
+	return connection
 }
+
+// RemoveConnection 移除连接，用户没有其他连接时删除用户映射
+func (c *ConnManager) RemoveConnection(conn_uuid string) *Connection {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+	connection, ok := c.connections[conn_uuid]
+	if !ok {
+		return nil
+	}
+	delete(c.connections, conn_uuid)
+	if conn_uuids, ok := c.user_conn_map[connection.user_uuid]; ok {
+		delete(conn_uuids, conn_uuid)
+		if len(conn_uuids) == 0 {
+			delete(c.user_conn_map, connection.user_uuid)
+		}
+	}
+	return connection
+}
+
 func (c *ConnManager) GetConnection(conn_uuid string) *Connection {
 	c.locker.RLock()
 	defer c.locker.RUnlock()
 	return c.connections[conn_uuid]
 }
 
-func (c *ConnManager) AddSession(session_uuid string, user_uuids []string) *Session {
+// ListConnections 获取所有连接
+func (c *ConnManager) ListConnections() []*Connection {
+	c.locker.RLock()
+	defer c.locker.RUnlock()
+	connections := make([]*Connection, 0, len(c.connections))
+	for _, connection := range c.connections {
+		connections = append(connections, connection)
+	}
+	return connections
+}
+
+// Count 当前连接数
+func (c *ConnManager) Count() int {
+	c.locker.RLock()
+	defer c.locker.RUnlock()
+	return len(c.connections)
+}
+
+// SessionEpoch 查询会话成员前记录，写回缓存时校验期间没有发生失效
+func (c *ConnManager) SessionEpoch() int64 {
+	c.locker.RLock()
+	defer c.locker.RUnlock()
+	return c.sessionEpoch
+}
+
+// AddSession 缓存会话成员，epoch已过期时只返回不缓存
+func (c *ConnManager) AddSession(session_uuid string, user_uuids []string, epoch int64) *Session {
 	c.locker.Lock()
 	defer c.locker.Unlock()
 	session := &Session{
 		user_uuids: user_uuids,
+		expireAt:   time.Now().Add(c.sessionTTL),
+	}
+	if epoch == c.sessionEpoch {
+		c.sessions[session_uuid] = session
 	}
-	c.sessions[session_uuid] = session
 	return session
 }
 
+// GetSession 获取缓存的会话成员，不存在或已过期时返回nil
 func (c *ConnManager) GetSession(session_uuid string) *Session {
+	c.locker.RLock()
+	session, ok := c.sessions[session_uuid]
+	c.locker.RUnlock()
+	if !ok || time.Now().After(session.expireAt) {
+		c.sessionMisses.Add(1)
+		return nil
+	}
+	c.sessionHits.Add(1)
+	return session
+}
+
+// RemoveSession 会话成员变更时使缓存失效
+func (c *ConnManager) RemoveSession(session_uuid string) {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+	delete(c.sessions, session_uuid)
+	c.sessionEpoch++
+}
+
+// PurgeExpiredSessions 清理过期的会话缓存，返回清理数量
+func (c *ConnManager) PurgeExpiredSessions() int {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+	now := time.Now()
+	purged := 0
+	for session_uuid, session := range c.sessions {
+		if now.After(session.expireAt) {
+			delete(c.sessions, session_uuid)
+			purged++
+		}
+	}
+	return purged
+}
+
+// SessionCacheStats 会话缓存命中、未命中次数及当前缓存数
+func (c *ConnManager) SessionCacheStats() (int64, int64, int) {
 	c.locker.RLock()
 	defer c.locker.RUnlock()
-	return c.sessions[session_uuid]
+	return c.sessionHits.Load(), c.sessionMisses.Load(), len(c.sessions)
 }
 
-func (c *ConnManager) GetUserConnUUID(user_uuid string) string {
+// GetUserConnections 获取用户所有设备的连接
+func (c *ConnManager) GetUserConnections(user_uuid string) []*Connection {
 	c.locker.RLock()
 	defer c.locker.RUnlock()
-	return c.user_conn_map[user_uuid]
+	connections := make([]*Connection, 0, len(c.user_conn_map[user_uuid]))
+	for conn_uuid := range c.user_conn_map[user_uuid] {
+		connections = append(connections, c.connections[conn_uuid])
+	}
+	return connections
 }
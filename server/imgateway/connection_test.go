@@ -0,0 +1,43 @@
+package imgateway
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionCache(t *testing.T) {
+	manager := NewConnManager(50 * time.Millisecond)
+
+	if manager.GetSession("session-1") != nil {
+		t.Fatalf("expected miss")
+	}
+	manager.AddSession("session-1", []string{"user-a"}, manager.SessionEpoch())
+	if session := manager.GetSession("session-1"); session == nil || len(session.user_uuids) != 1 {
+		t.Fatalf("expected hit, got %v", session)
+	}
+
+	// 查询期间发生失效，旧的成员列表不写回缓存
+	epoch := manager.SessionEpoch()
+	manager.RemoveSession("session-1")
+	if session := manager.AddSession("session-1", []string{"user-a"}, epoch); session == nil {
+		t.Fatalf("expected session returned")
+	}
+	if manager.GetSession("session-1") != nil {
+		t.Fatalf("expected stale session not cached")
+	}
+
+	// 过期后视为未命中并可被清理
+	manager.AddSession("session-2", []string{"user-b"}, manager.SessionEpoch())
+	time.Sleep(60 * time.Millisecond)
+	if manager.GetSession("session-2") != nil {
+		t.Fatalf("expected session expired")
+	}
+	if purged := manager.PurgeExpiredSessions(); purged != 1 {
+		t.Fatalf("expected 1 purged, got %d", purged)
+	}
+
+	hits, misses, size := manager.SessionCacheStats()
+	if hits != 1 || misses != 3 || size != 0 {
+		t.Fatalf("unexpected stats: hits %d misses %d size %d", hits, misses, size)
+	}
+}
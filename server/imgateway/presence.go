@@ -0,0 +1,55 @@
+package imgateway
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	PresenceStatusOnline  = "online"
+	PresenceStatusOffline = "offline"
+)
+
+// PresenceEvent 用户上下线事件
+type PresenceEvent struct {
+	UserUUID string `json:"user_uuid"`
+	ConnUUID string `json:"conn_uuid"`
+	Status   string `json:"status"`
+}
+
+// Presence 用户在线状态通知
+type Presence interface {
+	Online(ctx context.Context, user_uuid string, conn_uuid string) error
+	Offline(ctx context.Context, user_uuid string, conn_uuid string) error
+}
+
+// 通过Redis发布订阅广播上下线事件
+type redisPresence struct {
+	redisClient *redis.Client
+	channel     string
+}
+
+func NewRedisPresence(redisClient *redis.Client) Presence {
+	return &redisPresence{
+		redisClient: redisClient,
+		channel:     "im:presence",
+	}
+}
+
+func (p *redisPresence) Online(ctx context.Context, user_uuid string, conn_uuid string) error {
+	return p.publish(ctx, &PresenceEvent{UserUUID: user_uuid, ConnUUID: conn_uuid, Status: PresenceStatusOnline})
+}
+
+func (p *redisPresence) Offline(ctx context.Context, user_uuid string, conn_uuid string) error {
+	return p.publish(ctx, &PresenceEvent{UserUUID: user_uuid, ConnUUID: conn_uuid, Status: PresenceStatusOffline})
+}
+
+func (p *redisPresence) publish(ctx context.Context, event *PresenceEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return p.redisClient.Publish(ctx, p.channel, data).Err()
+}
@@ -0,0 +1,103 @@
+package imgateway
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RouteStore 用户连接所在网关的路由表
+type RouteStore interface {
+	// Add 记录连接所在网关的RPC地址，ttl内未再次调用时路由过期，网关崩溃后残留的路由自动失效
+	Add(ctx context.Context, user_uuid string, conn_uuid string, gateway_addr string, ttl time.Duration) error
+	// Remove 删除连接的路由
+	Remove(ctx context.Context, user_uuid string, conn_uuid string) error
+	// Lookup 批量查询用户未过期的路由 用户UUID -> 连接UUID -> 网关RPC地址
+	Lookup(ctx context.Context, user_uuids []string) (map[string]map[string]string, error)
+}
+
+// 基于Redis哈希的路由表，每个用户一个key，field为连接UUID，value为网关RPC地址和过期时间
+// 哈希的field不能单独设置过期时间，过期时间记录在value中，查询时过滤；key整体随最后一次续期过期
+type redisRouteStore struct {
+	redisClient *redis.Client
+	now         func() time.Time
+}
+
+func NewRedisRouteStore(redisClient *redis.Client) RouteStore {
+	return &redisRouteStore{redisClient: redisClient, now: time.Now}
+}
+
+func routeKey(user_uuid string) string {
+	return fmt.Sprintf("im:route:%s", user_uuid)
+}
+
+// 路由值 网关RPC地址|过期时间戳(毫秒)
+func routeValue(gateway_addr string, expire_at time.Time) string {
+	return fmt.Sprintf("%s|%d", gateway_addr, expire_at.UnixMilli())
+}
+
+// 解析路由值，兼容不带过期时间的旧值
+func parseRouteValue(value string) (string, time.Time) {
+	index := strings.LastIndex(value, "|")
+	if index < 0 {
+		return value, time.Time{}
+	}
+	expire_at, err := strconv.ParseInt(value[index+1:], 10, 64)
+	if err != nil {
+		return value, time.Time{}
+	}
+	return value[:index], time.UnixMilli(expire_at)
+}
+
+func (r *redisRouteStore) Add(ctx context.Context, user_uuid string, conn_uuid string, gateway_addr string, ttl time.Duration) error {
+	key := routeKey(user_uuid)
+	pipe := r.redisClient.TxPipeline()
+	pipe.HSet(ctx, key, conn_uuid, routeValue(gateway_addr, r.now().Add(ttl)))
+	pipe.Expire(ctx, key, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *redisRouteStore) Remove(ctx context.Context, user_uuid string, conn_uuid string) error {
+	return r.redisClient.HDel(ctx, routeKey(user_uuid), conn_uuid).Err()
+}
+
+func (r *redisRouteStore) Lookup(ctx context.Context, user_uuids []string) (map[string]map[string]string, error) {
+	pipe := r.redisClient.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, 0, len(user_uuids))
+	for _, user_uuid := range user_uuids {
+		cmds = append(cmds, pipe.HGetAll(ctx, routeKey(user_uuid)))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	now := r.now()
+	routes := make(map[string]map[string]string, len(user_uuids))
+	expired := make(map[string][]string)
+	for i, cmd := range cmds {
+		for conn_uuid, value := range cmd.Val() {
+			gateway_addr, expire_at := parseRouteValue(value)
+			if !expire_at.IsZero() && !now.Before(expire_at) {
+				expired[user_uuids[i]] = append(expired[user_uuids[i]], conn_uuid)
+				continue
+			}
+			if _, ok := routes[user_uuids[i]]; !ok {
+				routes[user_uuids[i]] = make(map[string]string)
+			}
+			routes[user_uuids[i]][conn_uuid] = gateway_addr
+		}
+	}
+	// 顺带清理过期的路由，失败不影响查询结果
+	if len(expired) > 0 {
+		pipe := r.redisClient.Pipeline()
+		for user_uuid, conn_uuids := range expired {
+			pipe.HDel(ctx, routeKey(user_uuid), conn_uuids...)
+		}
+		pipe.Exec(ctx)
+	}
+	return routes, nil
+}
@@ -24,6 +24,7 @@ const (
 type DelConnRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ConnId        string                 `protobuf:"bytes,1,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"` // 连接ID
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`               // 踢下线原因，下发给客户端
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -65,6 +66,13 @@ func (x *DelConnRequest) GetConnId() string {
 	return ""
 }
 
+func (x *DelConnRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
 type DelConnResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -101,16 +109,643 @@ func (*DelConnResponse) Descriptor() ([]byte, []int) {
 	return file_rpc_service_imgateway_proto_rawDescGZIP(), []int{1}
 }
 
+// 投递消息到本网关持有的连接
+type PushMessageRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserUuids       []string               `protobuf:"bytes,1,rep,name=user_uuids,json=userUuids,proto3" json:"user_uuids,omitempty"`                     // 接收者UUID列表
+	ExcludeConnUuid string                 `protobuf:"bytes,2,opt,name=exclude_conn_uuid,json=excludeConnUuid,proto3" json:"exclude_conn_uuid,omitempty"` // 不投递的连接，通常为发送者当前连接
+	SessionUuid     string                 `protobuf:"bytes,3,opt,name=session_uuid,json=sessionUuid,proto3" json:"session_uuid,omitempty"`               // 会话UUID
+	SenderUserUuid  string                 `protobuf:"bytes,4,opt,name=sender_user_uuid,json=senderUserUuid,proto3" json:"sender_user_uuid,omitempty"`    // 发送者UUID
+	Payload         string                 `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"`                                          // 消息
+	SeqId           int64                  `protobuf:"varint,6,opt,name=seq_id,json=seqId,proto3" json:"seq_id,omitempty"`                                // 序列号ID
+	MessageUuid     string                 `protobuf:"bytes,7,opt,name=message_uuid,json=messageUuid,proto3" json:"message_uuid,omitempty"`               // 消息UUID
+	MessageType     int64                  `protobuf:"varint,8,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`              // 消息类型
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PushMessageRequest) Reset() {
+	*x = PushMessageRequest{}
+	mi := &file_rpc_service_imgateway_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushMessageRequest) ProtoMessage() {}
+
+func (x *PushMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_imgateway_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushMessageRequest.ProtoReflect.Descriptor instead.
+func (*PushMessageRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_imgateway_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PushMessageRequest) GetUserUuids() []string {
+	if x != nil {
+		return x.UserUuids
+	}
+	return nil
+}
+
+func (x *PushMessageRequest) GetExcludeConnUuid() string {
+	if x != nil {
+		return x.ExcludeConnUuid
+	}
+	return ""
+}
+
+func (x *PushMessageRequest) GetSessionUuid() string {
+	if x != nil {
+		return x.SessionUuid
+	}
+	return ""
+}
+
+func (x *PushMessageRequest) GetSenderUserUuid() string {
+	if x != nil {
+		return x.SenderUserUuid
+	}
+	return ""
+}
+
+func (x *PushMessageRequest) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *PushMessageRequest) GetSeqId() int64 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *PushMessageRequest) GetMessageUuid() string {
+	if x != nil {
+		return x.MessageUuid
+	}
+	return ""
+}
+
+func (x *PushMessageRequest) GetMessageType() int64 {
+	if x != nil {
+		return x.MessageType
+	}
+	return 0
+}
+
+type PushMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Delivered     int64                  `protobuf:"varint,1,opt,name=delivered,proto3" json:"delivered,omitempty"` // 成功投递的连接数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushMessageResponse) Reset() {
+	*x = PushMessageResponse{}
+	mi := &file_rpc_service_imgateway_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushMessageResponse) ProtoMessage() {}
+
+func (x *PushMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_imgateway_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushMessageResponse.ProtoReflect.Descriptor instead.
+func (*PushMessageResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_imgateway_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PushMessageResponse) GetDelivered() int64 {
+	if x != nil {
+		return x.Delivered
+	}
+	return 0
+}
+
+// 连接详情
+type ConnectionInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConnUuid      string                 `protobuf:"bytes,1,opt,name=conn_uuid,json=connUuid,proto3" json:"conn_uuid,omitempty"`                // 连接UUID
+	UserUuid      string                 `protobuf:"bytes,2,opt,name=user_uuid,json=userUuid,proto3" json:"user_uuid,omitempty"`                // 用户UUID
+	DeviceId      string                 `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`                // 设备ID
+	Platform      string                 `protobuf:"bytes,4,opt,name=platform,proto3" json:"platform,omitempty"`                                // 平台
+	RemoteAddr    string                 `protobuf:"bytes,5,opt,name=remote_addr,json=remoteAddr,proto3" json:"remote_addr,omitempty"`          // 客户端地址
+	ConnectedAt   int64                  `protobuf:"varint,6,opt,name=connected_at,json=connectedAt,proto3" json:"connected_at,omitempty"`      // 建立连接时间 毫秒
+	LastActiveAt  int64                  `protobuf:"varint,7,opt,name=last_active_at,json=lastActiveAt,proto3" json:"last_active_at,omitempty"` // 最后一次收到数据(含心跳)的时间 毫秒
+	BytesIn       int64                  `protobuf:"varint,8,opt,name=bytes_in,json=bytesIn,proto3" json:"bytes_in,omitempty"`                  // 接收字节数
+	BytesOut      int64                  `protobuf:"varint,9,opt,name=bytes_out,json=bytesOut,proto3" json:"bytes_out,omitempty"`               // 发送字节数
+	Pending       int64                  `protobuf:"varint,10,opt,name=pending,proto3" json:"pending,omitempty"`                                // 待确认的下行消息数
+	Queued        int64                  `protobuf:"varint,11,opt,name=queued,proto3" json:"queued,omitempty"`                                  // 写队列中等待发送的帧数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConnectionInfo) Reset() {
+	*x = ConnectionInfo{}
+	mi := &file_rpc_service_imgateway_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConnectionInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnectionInfo) ProtoMessage() {}
+
+func (x *ConnectionInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_imgateway_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnectionInfo.ProtoReflect.Descriptor instead.
+func (*ConnectionInfo) Descriptor() ([]byte, []int) {
+	return file_rpc_service_imgateway_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ConnectionInfo) GetConnUuid() string {
+	if x != nil {
+		return x.ConnUuid
+	}
+	return ""
+}
+
+func (x *ConnectionInfo) GetUserUuid() string {
+	if x != nil {
+		return x.UserUuid
+	}
+	return ""
+}
+
+func (x *ConnectionInfo) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *ConnectionInfo) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+func (x *ConnectionInfo) GetRemoteAddr() string {
+	if x != nil {
+		return x.RemoteAddr
+	}
+	return ""
+}
+
+func (x *ConnectionInfo) GetConnectedAt() int64 {
+	if x != nil {
+		return x.ConnectedAt
+	}
+	return 0
+}
+
+func (x *ConnectionInfo) GetLastActiveAt() int64 {
+	if x != nil {
+		return x.LastActiveAt
+	}
+	return 0
+}
+
+func (x *ConnectionInfo) GetBytesIn() int64 {
+	if x != nil {
+		return x.BytesIn
+	}
+	return 0
+}
+
+func (x *ConnectionInfo) GetBytesOut() int64 {
+	if x != nil {
+		return x.BytesOut
+	}
+	return 0
+}
+
+func (x *ConnectionInfo) GetPending() int64 {
+	if x != nil {
+		return x.Pending
+	}
+	return 0
+}
+
+func (x *ConnectionInfo) GetQueued() int64 {
+	if x != nil {
+		return x.Queued
+	}
+	return 0
+}
+
+// 查询本网关的连接
+type ListConnectionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserUuid      string                 `protobuf:"bytes,1,opt,name=user_uuid,json=userUuid,proto3" json:"user_uuid,omitempty"`  // 按用户过滤，为空时返回全部
+	Page          int64                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`                         // 页码 从1开始
+	PageSize      int64                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"` // 每页数量 默认20
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListConnectionsRequest) Reset() {
+	*x = ListConnectionsRequest{}
+	mi := &file_rpc_service_imgateway_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListConnectionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConnectionsRequest) ProtoMessage() {}
+
+func (x *ListConnectionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_imgateway_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConnectionsRequest.ProtoReflect.Descriptor instead.
+func (*ListConnectionsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_imgateway_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListConnectionsRequest) GetUserUuid() string {
+	if x != nil {
+		return x.UserUuid
+	}
+	return ""
+}
+
+func (x *ListConnectionsRequest) GetPage() int64 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListConnectionsRequest) GetPageSize() int64 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListConnectionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Connections   []*ConnectionInfo      `protobuf:"bytes,1,rep,name=connections,proto3" json:"connections,omitempty"` // 连接列表 按建立时间排序
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`            // 总数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListConnectionsResponse) Reset() {
+	*x = ListConnectionsResponse{}
+	mi := &file_rpc_service_imgateway_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListConnectionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConnectionsResponse) ProtoMessage() {}
+
+func (x *ListConnectionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_imgateway_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConnectionsResponse.ProtoReflect.Descriptor instead.
+func (*ListConnectionsResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_imgateway_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListConnectionsResponse) GetConnections() []*ConnectionInfo {
+	if x != nil {
+		return x.Connections
+	}
+	return nil
+}
+
+func (x *ListConnectionsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type GetConnectionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConnUuid      string                 `protobuf:"bytes,1,opt,name=conn_uuid,json=connUuid,proto3" json:"conn_uuid,omitempty"` // 连接UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConnectionRequest) Reset() {
+	*x = GetConnectionRequest{}
+	mi := &file_rpc_service_imgateway_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConnectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConnectionRequest) ProtoMessage() {}
+
+func (x *GetConnectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_imgateway_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConnectionRequest.ProtoReflect.Descriptor instead.
+func (*GetConnectionRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_imgateway_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetConnectionRequest) GetConnUuid() string {
+	if x != nil {
+		return x.ConnUuid
+	}
+	return ""
+}
+
+type GetConnectionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Connection    *ConnectionInfo        `protobuf:"bytes,1,opt,name=connection,proto3" json:"connection,omitempty"` // 连接详情
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConnectionResponse) Reset() {
+	*x = GetConnectionResponse{}
+	mi := &file_rpc_service_imgateway_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConnectionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConnectionResponse) ProtoMessage() {}
+
+func (x *GetConnectionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_imgateway_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConnectionResponse.ProtoReflect.Descriptor instead.
+func (*GetConnectionResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_imgateway_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetConnectionResponse) GetConnection() *ConnectionInfo {
+	if x != nil {
+		return x.Connection
+	}
+	return nil
+}
+
+// 踢掉用户的所有连接
+type KickUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserUuid      string                 `protobuf:"bytes,1,opt,name=user_uuid,json=userUuid,proto3" json:"user_uuid,omitempty"`     // 用户UUID
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`                         // 原因，下发给客户端
+	LocalOnly     bool                   `protobuf:"varint,3,opt,name=local_only,json=localOnly,proto3" json:"local_only,omitempty"` // 只处理本网关的连接，为false时转发给用户连接所在的其他网关
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KickUserRequest) Reset() {
+	*x = KickUserRequest{}
+	mi := &file_rpc_service_imgateway_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KickUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KickUserRequest) ProtoMessage() {}
+
+func (x *KickUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_imgateway_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KickUserRequest.ProtoReflect.Descriptor instead.
+func (*KickUserRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_service_imgateway_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *KickUserRequest) GetUserUuid() string {
+	if x != nil {
+		return x.UserUuid
+	}
+	return ""
+}
+
+func (x *KickUserRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *KickUserRequest) GetLocalOnly() bool {
+	if x != nil {
+		return x.LocalOnly
+	}
+	return false
+}
+
+type KickUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kicked        int64                  `protobuf:"varint,1,opt,name=kicked,proto3" json:"kicked,omitempty"` // 被踢掉的连接数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KickUserResponse) Reset() {
+	*x = KickUserResponse{}
+	mi := &file_rpc_service_imgateway_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KickUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KickUserResponse) ProtoMessage() {}
+
+func (x *KickUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_service_imgateway_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KickUserResponse.ProtoReflect.Descriptor instead.
+func (*KickUserResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_service_imgateway_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *KickUserResponse) GetKicked() int64 {
+	if x != nil {
+		return x.Kicked
+	}
+	return 0
+}
+
 var File_rpc_service_imgateway_proto protoreflect.FileDescriptor
 
 const file_rpc_service_imgateway_proto_rawDesc = "" +
 	"\n" +
-	"\x1brpc/service/imgateway.proto\x12\timgateway\")\n" +
+	"\x1brpc/service/imgateway.proto\x12\timgateway\"A\n" +
 	"\x0eDelConnRequest\x12\x17\n" +
-	"\aconn_id\x18\x01 \x01(\tR\x06connId\"\x11\n" +
-	"\x0fDelConnResponse2M\n" +
+	"\aconn_id\x18\x01 \x01(\tR\x06connId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\x11\n" +
+	"\x0fDelConnResponse\"\xa3\x02\n" +
+	"\x12PushMessageRequest\x12\x1d\n" +
+	"\n" +
+	"user_uuids\x18\x01 \x03(\tR\tuserUuids\x12*\n" +
+	"\x11exclude_conn_uuid\x18\x02 \x01(\tR\x0fexcludeConnUuid\x12!\n" +
+	"\fsession_uuid\x18\x03 \x01(\tR\vsessionUuid\x12(\n" +
+	"\x10sender_user_uuid\x18\x04 \x01(\tR\x0esenderUserUuid\x12\x18\n" +
+	"\apayload\x18\x05 \x01(\tR\apayload\x12\x15\n" +
+	"\x06seq_id\x18\x06 \x01(\x03R\x05seqId\x12!\n" +
+	"\fmessage_uuid\x18\a \x01(\tR\vmessageUuid\x12!\n" +
+	"\fmessage_type\x18\b \x01(\x03R\vmessageType\"3\n" +
+	"\x13PushMessageResponse\x12\x1c\n" +
+	"\tdelivered\x18\x01 \x01(\x03R\tdelivered\"\xd7\x02\n" +
+	"\x0eConnectionInfo\x12\x1b\n" +
+	"\tconn_uuid\x18\x01 \x01(\tR\bconnUuid\x12\x1b\n" +
+	"\tuser_uuid\x18\x02 \x01(\tR\buserUuid\x12\x1b\n" +
+	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\x12\x1a\n" +
+	"\bplatform\x18\x04 \x01(\tR\bplatform\x12\x1f\n" +
+	"\vremote_addr\x18\x05 \x01(\tR\n" +
+	"remoteAddr\x12!\n" +
+	"\fconnected_at\x18\x06 \x01(\x03R\vconnectedAt\x12$\n" +
+	"\x0elast_active_at\x18\a \x01(\x03R\flastActiveAt\x12\x19\n" +
+	"\bbytes_in\x18\b \x01(\x03R\abytesIn\x12\x1b\n" +
+	"\tbytes_out\x18\t \x01(\x03R\bbytesOut\x12\x18\n" +
+	"\apending\x18\n" +
+	" \x01(\x03R\apending\x12\x16\n" +
+	"\x06queued\x18\v \x01(\x03R\x06queued\"f\n" +
+	"\x16ListConnectionsRequest\x12\x1b\n" +
+	"\tuser_uuid\x18\x01 \x01(\tR\buserUuid\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x03R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x03R\bpageSize\"l\n" +
+	"\x17ListConnectionsResponse\x12;\n" +
+	"\vconnections\x18\x01 \x03(\v2\x19.imgateway.ConnectionInfoR\vconnections\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\"3\n" +
+	"\x14GetConnectionRequest\x12\x1b\n" +
+	"\tconn_uuid\x18\x01 \x01(\tR\bconnUuid\"R\n" +
+	"\x15GetConnectionResponse\x129\n" +
+	"\n" +
+	"connection\x18\x01 \x01(\v2\x19.imgateway.ConnectionInfoR\n" +
+	"connection\"e\n" +
+	"\x0fKickUserRequest\x12\x1b\n" +
+	"\tuser_uuid\x18\x01 \x01(\tR\buserUuid\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"local_only\x18\x03 \x01(\bR\tlocalOnly\"*\n" +
+	"\x10KickUserResponse\x12\x16\n" +
+	"\x06kicked\x18\x01 \x01(\x03R\x06kicked2\x8e\x03\n" +
 	"\tIMGateway\x12@\n" +
-	"\aDelConn\x12\x19.imgateway.DelConnRequest\x1a\x1a.imgateway.DelConnResponseB\fZ\n" +
+	"\aDelConn\x12\x19.imgateway.DelConnRequest\x1a\x1a.imgateway.DelConnResponse\x12L\n" +
+	"\vPushMessage\x12\x1d.imgateway.PushMessageRequest\x1a\x1e.imgateway.PushMessageResponse\x12X\n" +
+	"\x0fListConnections\x12!.imgateway.ListConnectionsRequest\x1a\".imgateway.ListConnectionsResponse\x12R\n" +
+	"\rGetConnection\x12\x1f.imgateway.GetConnectionRequest\x1a .imgateway.GetConnectionResponse\x12C\n" +
+	"\bKickUser\x12\x1a.imgateway.KickUserRequest\x1a\x1b.imgateway.KickUserResponseB\fZ\n" +
 	"./;serviceb\x06proto3"
 
 var (
@@ -125,19 +760,38 @@ func file_rpc_service_imgateway_proto_rawDescGZIP() []byte {
 	return file_rpc_service_imgateway_proto_rawDescData
 }
 
-var file_rpc_service_imgateway_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_service_imgateway_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_rpc_service_imgateway_proto_goTypes = []any{
-	(*DelConnRequest)(nil),  // 0: imgateway.DelConnRequest
-	(*DelConnResponse)(nil), // 1: imgateway.DelConnResponse
+	(*DelConnRequest)(nil),          // 0: imgateway.DelConnRequest
+	(*DelConnResponse)(nil),         // 1: imgateway.DelConnResponse
+	(*PushMessageRequest)(nil),      // 2: imgateway.PushMessageRequest
+	(*PushMessageResponse)(nil),     // 3: imgateway.PushMessageResponse
+	(*ConnectionInfo)(nil),          // 4: imgateway.ConnectionInfo
+	(*ListConnectionsRequest)(nil),  // 5: imgateway.ListConnectionsRequest
+	(*ListConnectionsResponse)(nil), // 6: imgateway.ListConnectionsResponse
+	(*GetConnectionRequest)(nil),    // 7: imgateway.GetConnectionRequest
+	(*GetConnectionResponse)(nil),   // 8: imgateway.GetConnectionResponse
+	(*KickUserRequest)(nil),         // 9: imgateway.KickUserRequest
+	(*KickUserResponse)(nil),        // 10: imgateway.KickUserResponse
 }
 var file_rpc_service_imgateway_proto_depIdxs = []int32{
-	0, // 0: imgateway.IMGateway.DelConn:input_type -> imgateway.DelConnRequest
-	1, // 1: imgateway.IMGateway.DelConn:output_type -> imgateway.DelConnResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	4,  // 0: imgateway.ListConnectionsResponse.connections:type_name -> imgateway.ConnectionInfo
+	4,  // 1: imgateway.GetConnectionResponse.connection:type_name -> imgateway.ConnectionInfo
+	0,  // 2: imgateway.IMGateway.DelConn:input_type -> imgateway.DelConnRequest
+	2,  // 3: imgateway.IMGateway.PushMessage:input_type -> imgateway.PushMessageRequest
+	5,  // 4: imgateway.IMGateway.ListConnections:input_type -> imgateway.ListConnectionsRequest
+	7,  // 5: imgateway.IMGateway.GetConnection:input_type -> imgateway.GetConnectionRequest
+	9,  // 6: imgateway.IMGateway.KickUser:input_type -> imgateway.KickUserRequest
+	1,  // 7: imgateway.IMGateway.DelConn:output_type -> imgateway.DelConnResponse
+	3,  // 8: imgateway.IMGateway.PushMessage:output_type -> imgateway.PushMessageResponse
+	6,  // 9: imgateway.IMGateway.ListConnections:output_type -> imgateway.ListConnectionsResponse
+	8,  // 10: imgateway.IMGateway.GetConnection:output_type -> imgateway.GetConnectionResponse
+	10, // 11: imgateway.IMGateway.KickUser:output_type -> imgateway.KickUserResponse
+	7,  // [7:12] is the sub-list for method output_type
+	2,  // [2:7] is the sub-list for method input_type
+	2,  // [2:2] is the sub-list for extension type_name
+	2,  // [2:2] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_rpc_service_imgateway_proto_init() }
@@ -151,7 +805,7 @@ func file_rpc_service_imgateway_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_service_imgateway_proto_rawDesc), len(file_rpc_service_imgateway_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
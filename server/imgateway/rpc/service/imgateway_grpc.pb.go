@@ -19,7 +19,11 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	IMGateway_DelConn_FullMethodName = "/imgateway.IMGateway/DelConn"
+	IMGateway_DelConn_FullMethodName         = "/imgateway.IMGateway/DelConn"
+	IMGateway_PushMessage_FullMethodName     = "/imgateway.IMGateway/PushMessage"
+	IMGateway_ListConnections_FullMethodName = "/imgateway.IMGateway/ListConnections"
+	IMGateway_GetConnection_FullMethodName   = "/imgateway.IMGateway/GetConnection"
+	IMGateway_KickUser_FullMethodName        = "/imgateway.IMGateway/KickUser"
 )
 
 // IMGatewayClient is the client API for IMGateway service.
@@ -27,6 +31,10 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type IMGatewayClient interface {
 	DelConn(ctx context.Context, in *DelConnRequest, opts ...grpc.CallOption) (*DelConnResponse, error)
+	PushMessage(ctx context.Context, in *PushMessageRequest, opts ...grpc.CallOption) (*PushMessageResponse, error)
+	ListConnections(ctx context.Context, in *ListConnectionsRequest, opts ...grpc.CallOption) (*ListConnectionsResponse, error)
+	GetConnection(ctx context.Context, in *GetConnectionRequest, opts ...grpc.CallOption) (*GetConnectionResponse, error)
+	KickUser(ctx context.Context, in *KickUserRequest, opts ...grpc.CallOption) (*KickUserResponse, error)
 }
 
 type iMGatewayClient struct {
@@ -47,11 +55,55 @@ func (c *iMGatewayClient) DelConn(ctx context.Context, in *DelConnRequest, opts
 	return out, nil
 }
 
+func (c *iMGatewayClient) PushMessage(ctx context.Context, in *PushMessageRequest, opts ...grpc.CallOption) (*PushMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PushMessageResponse)
+	err := c.cc.Invoke(ctx, IMGateway_PushMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iMGatewayClient) ListConnections(ctx context.Context, in *ListConnectionsRequest, opts ...grpc.CallOption) (*ListConnectionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListConnectionsResponse)
+	err := c.cc.Invoke(ctx, IMGateway_ListConnections_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iMGatewayClient) GetConnection(ctx context.Context, in *GetConnectionRequest, opts ...grpc.CallOption) (*GetConnectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetConnectionResponse)
+	err := c.cc.Invoke(ctx, IMGateway_GetConnection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iMGatewayClient) KickUser(ctx context.Context, in *KickUserRequest, opts ...grpc.CallOption) (*KickUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KickUserResponse)
+	err := c.cc.Invoke(ctx, IMGateway_KickUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // IMGatewayServer is the server API for IMGateway service.
 // All implementations must embed UnimplementedIMGatewayServer
 // for forward compatibility.
 type IMGatewayServer interface {
 	DelConn(context.Context, *DelConnRequest) (*DelConnResponse, error)
+	PushMessage(context.Context, *PushMessageRequest) (*PushMessageResponse, error)
+	ListConnections(context.Context, *ListConnectionsRequest) (*ListConnectionsResponse, error)
+	GetConnection(context.Context, *GetConnectionRequest) (*GetConnectionResponse, error)
+	KickUser(context.Context, *KickUserRequest) (*KickUserResponse, error)
 	mustEmbedUnimplementedIMGatewayServer()
 }
 
@@ -65,6 +117,18 @@ type UnimplementedIMGatewayServer struct{}
 func (UnimplementedIMGatewayServer) DelConn(context.Context, *DelConnRequest) (*DelConnResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DelConn not implemented")
 }
+func (UnimplementedIMGatewayServer) PushMessage(context.Context, *PushMessageRequest) (*PushMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PushMessage not implemented")
+}
+func (UnimplementedIMGatewayServer) ListConnections(context.Context, *ListConnectionsRequest) (*ListConnectionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListConnections not implemented")
+}
+func (UnimplementedIMGatewayServer) GetConnection(context.Context, *GetConnectionRequest) (*GetConnectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConnection not implemented")
+}
+func (UnimplementedIMGatewayServer) KickUser(context.Context, *KickUserRequest) (*KickUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KickUser not implemented")
+}
 func (UnimplementedIMGatewayServer) mustEmbedUnimplementedIMGatewayServer() {}
 func (UnimplementedIMGatewayServer) testEmbeddedByValue()                   {}
 
@@ -104,6 +168,78 @@ func _IMGateway_DelConn_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IMGateway_PushMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PushMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IMGatewayServer).PushMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IMGateway_PushMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IMGatewayServer).PushMessage(ctx, req.(*PushMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IMGateway_ListConnections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListConnectionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IMGatewayServer).ListConnections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IMGateway_ListConnections_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IMGatewayServer).ListConnections(ctx, req.(*ListConnectionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IMGateway_GetConnection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConnectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IMGatewayServer).GetConnection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IMGateway_GetConnection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IMGatewayServer).GetConnection(ctx, req.(*GetConnectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IMGateway_KickUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KickUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IMGatewayServer).KickUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IMGateway_KickUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IMGatewayServer).KickUser(ctx, req.(*KickUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // IMGateway_ServiceDesc is the grpc.ServiceDesc for IMGateway service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,6 +251,22 @@ var IMGateway_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DelConn",
 			Handler:    _IMGateway_DelConn_Handler,
 		},
+		{
+			MethodName: "PushMessage",
+			Handler:    _IMGateway_PushMessage_Handler,
+		},
+		{
+			MethodName: "ListConnections",
+			Handler:    _IMGateway_ListConnections_Handler,
+		},
+		{
+			MethodName: "GetConnection",
+			Handler:    _IMGateway_GetConnection_Handler,
+		},
+		{
+			MethodName: "KickUser",
+			Handler:    _IMGateway_KickUser_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "rpc/service/imgateway.proto",
@@ -3,20 +3,118 @@ package service
 import (
 	context "context"
 	"im/pkg/config"
+	"im/pkg/plato"
 	"log/slog"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+const defaultPageSize = 20
+
+// InternalMethods 只供运维和其他网关调用的方法，配置服务令牌后需要携带令牌
+var InternalMethods = []string{
+	IMGateway_DelConn_FullMethodName,
+	IMGateway_PushMessage_FullMethodName,
+	IMGateway_ListConnections_FullMethodName,
+	IMGateway_GetConnection_FullMethodName,
+	IMGateway_KickUser_FullMethodName,
+}
+
+// Pusher 将消息投递到本网关持有的连接，返回成功投递的连接数
+type Pusher interface {
+	Push(user_uuids []string, exclude_conn_uuid string, msg *plato.MessageDownLink) int
+}
+
+// ConnAdmin 网关连接管理
+type ConnAdmin interface {
+	// Kick 踢掉本网关的连接，连接不存在时返回false
+	Kick(conn_uuid string, reason string) bool
+	// KickUser 踢掉用户的所有连接，local_only为false时同时转发给其他网关
+	KickUser(ctx context.Context, user_uuid string, reason string, local_only bool) (int, error)
+	// Connections 本网关的连接，user_uuid为空时返回全部
+	Connections(user_uuid string) []*ConnectionInfo
+	// Connection 本网关的连接详情，不存在时返回nil
+	Connection(conn_uuid string) *ConnectionInfo
+}
+
+// Gateway 由长连接网关实现
+type Gateway interface {
+	Pusher
+	ConnAdmin
+}
+
 type IMGatewayService struct {
 	UnimplementedIMGatewayServer
-	ctx    context.Context
-	logger *slog.Logger
-	conf   *config.IMGatewayConfig
+	ctx     context.Context
+	logger  *slog.Logger
+	conf    *config.IMGatewayConfig
+	gateway Gateway
 }
 
-func NewIMGatewayService(ctx context.Context, logger *slog.Logger, conf *config.IMGatewayConfig) *IMGatewayService {
-	return &IMGatewayService{ctx: ctx, logger: logger, conf: conf}
+func NewIMGatewayService(ctx context.Context, logger *slog.Logger, conf *config.IMGatewayConfig, gateway Gateway) *IMGatewayService {
+	return &IMGatewayService{ctx: ctx, logger: logger, conf: conf, gateway: gateway}
 }
 
 func (s *IMGatewayService) DelConn(ctx context.Context, req *DelConnRequest) (*DelConnResponse, error) {
-	return nil, nil
+	if !s.gateway.Kick(req.ConnId, req.Reason) {
+		return nil, status.Errorf(codes.NotFound, "connection %s not found", req.ConnId)
+	}
+	return &DelConnResponse{}, nil
+}
+
+func (s *IMGatewayService) PushMessage(ctx context.Context, req *PushMessageRequest) (*PushMessageResponse, error) {
+	delivered := s.gateway.Push(req.UserUuids, req.ExcludeConnUuid, &plato.MessageDownLink{
+		SessionUuid:    req.SessionUuid,
+		SenderUserUuid: req.SenderUserUuid,
+		Payload:        req.Payload,
+		SeqId:          req.SeqId,
+		MessageUuid:    req.MessageUuid,
+		MessageType:    req.MessageType,
+	})
+	return &PushMessageResponse{Delivered: int64(delivered)}, nil
+}
+
+func (s *IMGatewayService) ListConnections(ctx context.Context, req *ListConnectionsRequest) (*ListConnectionsResponse, error) {
+	page, pageSize := req.Page, req.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	connections := s.gateway.Connections(req.UserUuid)
+	sort.Slice(connections, func(i, j int) bool {
+		if connections[i].ConnectedAt != connections[j].ConnectedAt {
+			return connections[i].ConnectedAt < connections[j].ConnectedAt
+		}
+		return connections[i].ConnUuid < connections[j].ConnUuid
+	})
+	total := int64(len(connections))
+	start := min((page-1)*pageSize, total)
+	end := min(start+pageSize, total)
+	return &ListConnectionsResponse{
+		Connections: connections[start:end],
+		Total:       total,
+	}, nil
+}
+
+func (s *IMGatewayService) GetConnection(ctx context.Context, req *GetConnectionRequest) (*GetConnectionResponse, error) {
+	connection := s.gateway.Connection(req.ConnUuid)
+	if connection == nil {
+		return nil, status.Errorf(codes.NotFound, "connection %s not found", req.ConnUuid)
+	}
+	return &GetConnectionResponse{Connection: connection}, nil
+}
+
+func (s *IMGatewayService) KickUser(ctx context.Context, req *KickUserRequest) (*KickUserResponse, error) {
+	if len(req.UserUuid) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "user uuid is required")
+	}
+	kicked, err := s.gateway.KickUser(ctx, req.UserUuid, req.Reason, req.LocalOnly)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "kick user %s failed: %v", req.UserUuid, err)
+	}
+	return &KickUserResponse{Kicked: int64(kicked)}, nil
 }
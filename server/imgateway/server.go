@@ -2,30 +2,44 @@ package imgateway
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"im/model"
 	"im/pkg/config"
+	"im/pkg/event"
+	"im/pkg/graceful"
 	"im/pkg/grpcmiddreware"
 	"im/pkg/jwt"
 	"im/pkg/plato"
+	"im/pkg/registry"
+	"im/pkg/timedtask"
+	"im/pkg/xtls"
 	"im/server/imgateway/rpc/service"
 	"io"
 	"log"
 	"log/slog"
 	"net"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	apigatewayService "im/server/apigateway/rpc/service"
 
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 )
 
 //go:generate protoc --go_out=rpc/service --go-grpc_out=rpc/service rpc/service/imgateway.proto
 
 func Run() {
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 	conf := config.NewConf().GetIMGatewayConfig()
 
 	level := slog.LevelDebug
@@ -36,76 +50,276 @@ func Run() {
 		Level: level,
 	}))
 
+	// RPC服务只供内部调用，需要服务令牌或mTLS校验调用方
+	mtls := conf.TLSConfig.Enabled && len(conf.TLSConfig.CAFile) > 0
+	if conf.Mode != "dev" && len(conf.ServiceToken) == 0 && !mtls {
+		log.Fatalf("service token or mtls is required in %s mode", conf.Mode)
+	}
+	creds, err := xtls.ServerOption(ctx, conf.TLSConfig, logger)
+	if err != nil {
+		log.Fatalf("failed to load tls: %v", err)
+	}
 	server := grpc.NewServer(
-		grpc.ChainUnaryInterceptor(grpcmiddreware.TraceUnaryInterceptor(), grpcmiddreware.LogUnaryInterceptor(logger)),
+		creds,
+		grpc.ChainUnaryInterceptor(
+			grpcmiddreware.TraceUnaryInterceptor(),
+			grpcmiddreware.LogUnaryInterceptor(logger),
+			grpcmiddreware.ServiceTokenUnaryInterceptor(conf.ServiceToken, service.InternalMethods...),
+		),
 	)
 
-	service.RegisterIMGatewayServer(server, service.NewIMGatewayService(ctx, logger, conf))
+	dialOption, err := xtls.DialOption(ctx, conf.TLSConfig, logger)
+	if err != nil {
+		log.Fatalf("failed to load tls: %v", err)
+	}
+	discoveryClient, err := registry.Dial(conf.DiscoveryEndpoint, dialOption)
+	if err != nil {
+		log.Fatalf("failed to create discovery client: %v", err)
+	}
+	// 通过发现服务订阅API网关实例，实例变更后自动更新连接
+	apiGatewayConn, err := grpc.NewClient(conf.APIGatewayTarget, dialOption,
+		grpc.WithResolvers(registry.NewResolverBuilder(discoveryClient, logger)),
+		grpc.WithDefaultServiceConfig(registry.ServiceConfig(registry.BalancerRoundRobin)),
+	)
+	if err != nil {
+		log.Fatalf("failed to create client: %v", err)
+	}
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     conf.RedisConfig.Addr,
+		Password: conf.RedisConfig.Password,
+		DB:       conf.RedisConfig.DB,
+	})
+	gateway := NewServer(ctx, conf, logger, apigatewayService.NewAPIGatewayClient(apiGatewayConn), NewRedisPresence(redisClient), NewRedisRouteStore(redisClient))
+	go func() {
+		if err := event.SubscribeSessionMembersChanged(ctx, redisClient, gateway.InvalidateSession); err != nil {
+			logger.Error("failed to subscribe session members changed", "error", err)
+		}
+	}()
+	service.RegisterIMGatewayServer(server, service.NewIMGatewayService(ctx, logger, conf, gateway))
+	healthServer := graceful.RegisterHealth(server)
 
-	go serve(conf, logger)
+	listeners := make([]net.Listener, 0)
+	metadata := registry.Metadata(conf.Zone, conf.Version)
+	for _, transport := range strings.Split(conf.Transports, ",") {
+		switch strings.TrimSpace(transport) {
+		case TransportTCP:
+			tcpListener, err := xtls.Listen(ctx, conf.Addr, conf.TLSConfig, logger)
+			if err != nil {
+				log.Fatalf("failed to listen: %v", err)
+			}
+			listeners = append(listeners, tcpListener)
+			go func() {
+				if err := gateway.Serve(tcpListener); err != nil {
+					log.Fatalf("failed to accept: %v", err)
+				}
+			}()
+			logger.Info("im gateway tcp listening", "address", conf.Addr)
+		case TransportWebSocket:
+			wsListener, err := xtls.Listen(ctx, conf.WebSocketAddr, conf.TLSConfig, logger)
+			if err != nil {
+				log.Fatalf("failed to listen: %v", err)
+			}
+			listeners = append(listeners, wsListener)
+			go func() {
+				if err := gateway.ServeWebSocket(wsListener); err != nil {
+					log.Fatalf("failed to serve websocket: %v", err)
+				}
+			}()
+			logger.Info("im gateway websocket listening", "address", conf.WebSocketAddr, "path", conf.WebSocketPath)
+			if _, port, err := net.SplitHostPort(conf.WebSocketAddr); err == nil {
+				metadata[registry.MetadataWebSocketPort] = port
+				metadata[registry.MetadataWebSocketPath] = conf.WebSocketPath
+			}
+		default:
+			log.Fatalf("unknown transport: %s", transport)
+		}
+	}
 
-	listener, err := net.Listen("tcp", conf.RpcAddr)
+	advertiseAddr, err := registry.AdvertiseAddr(conf.AdvertiseAddr, conf.Addr)
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		log.Fatalf("failed to get advertise address: %v", err)
 	}
-	logger.Info("im gateway server listening", "address", conf.Addr)
-	if err := server.Serve(listener); err != nil {
-		log.Fatalf("failed to serve: %v", err)
+	if _, port, err := net.SplitHostPort(conf.RpcAddr); err == nil {
+		metadata[registry.MetadataRPCPort] = port
+	}
+	// 上报长连接数，发现服务可按连接数选择网关
+	registrar, err := registry.Start(ctx, discoveryClient, logger, registry.Instance{
+		Name:     registry.ServiceIMGateway,
+		Addr:     advertiseAddr,
+		Weight:   conf.Weight,
+		Load:     gateway.ConnectionCount,
+		Metadata: metadata,
+	})
+	if err != nil {
+		log.Fatalf("failed to start registrar: %v", err)
 	}
-}
 
-func serve(conf *config.IMGatewayConfig, logger *slog.Logger) {
-	listener, err := net.Listen("tcp", conf.Addr)
+	listener, err := net.Listen("tcp", conf.RpcAddr)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
-	manager := NewConnManager()
+	logger.Info("im gateway server listening", "address", conf.RpcAddr)
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			log.Fatalf("failed to serve: %v", err)
+		}
+	}()
+
+	signalCtx, stop := graceful.NotifyContext(ctx)
+	defer stop()
+	<-signalCtx.Done()
+	logger.Info("im gateway server shutting down")
+	healthServer.Shutdown()
+	// 先从发现服务注销并停止接收新连接，再通知已有连接重连到其他网关
+	// RPC服务最后停止，排空期间其他网关转发的消息仍可投递
+	timeout := time.Duration(conf.ShutdownTimeout) * time.Second
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), timeout)
+	defer shutdownCancel()
+	if err := registrar.Deregister(shutdownCtx); err != nil {
+		logger.Error("failed to deregister", "error", err)
+	}
+	for _, listener := range listeners {
+		listener.Close()
+	}
+	gateway.Shutdown(shutdownCtx)
+	if !graceful.StopGRPC(server, timeout) {
+		logger.Warn("im gateway server shutdown timeout, force stopped")
+	}
+}
+
+// Server 长连接网关
+type Server struct {
+	ctx              context.Context
+	conf             *config.IMGatewayConfig
+	rpcAddr          string // 写入路由表的本网关RPC地址
+	logger           *slog.Logger
+	manager          *ConnManager
+	apiGatewayClient apigatewayService.APIGatewayClient
+	presence         Presence
+	routes           RouteStore
+	timeWheel        *timedtask.TimeWheel
+	exclusive        map[string]struct{} // 同一用户只允许一个连接的平台
+	draining         atomic.Bool         // 正在下线，不再接受新的认证
 
-	apiGatewayConn, err := grpc.NewClient(conf.APIGatewayAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	gatewayClientsLocker sync.Mutex
+	gatewayClients       map[string]service.IMGatewayClient // 其他网关的RPC客户端 RPC地址 -> 客户端
+	gatewayDialOption    grpc.DialOption                    // 其他网关的传输凭证，首次连接时加载
+}
+
+func NewServer(ctx context.Context, conf *config.IMGatewayConfig, logger *slog.Logger, apiGatewayClient apigatewayService.APIGatewayClient, presence Presence, routes RouteStore) *Server {
+	timeWheel := timedtask.NewTimeWheel(time.Second, 60)
+	go func() {
+		<-ctx.Done()
+		timeWheel.Stop()
+	}()
+	exclusive := make(map[string]struct{})
+	for _, platform := range strings.Split(conf.ExclusivePlatforms, ",") {
+		if platform = strings.TrimSpace(platform); len(platform) > 0 {
+			exclusive[platform] = struct{}{}
+		}
+	}
+	// 路由表中的地址需要其他网关可达，不能使用localhost等监听地址
+	rpcAddr, err := registry.AdvertiseAddr(conf.RpcAdvertiseAddr, conf.RpcAddr)
 	if err != nil {
-		logger.Error("failed to create client", "error", err)
-		return
+		logger.Error("failed to get rpc advertise address, fallback to rpc address", "error", err)
+		rpcAddr = conf.RpcAddr
+	}
+	server := &Server{
+		ctx:              ctx,
+		conf:             conf,
+		rpcAddr:          rpcAddr,
+		logger:           logger,
+		manager:          NewConnManager(time.Duration(conf.SessionCacheTTL) * time.Second),
+		apiGatewayClient: apiGatewayClient,
+		presence:         presence,
+		routes:           routes,
+		timeWheel:        timeWheel,
+		exclusive:        exclusive,
+		gatewayClients:   make(map[string]service.IMGatewayClient),
 	}
-	apiGatewayClient := apigatewayService.NewAPIGatewayClient(apiGatewayConn)
+	go server.monitorSessionCache(time.Minute)
+	return server
+}
 
+// Serve 接收长连接，直到listener关闭
+func (s *Server) Serve(listener net.Listener) error {
 	for {
 		conn, err := listener.Accept()
 		if err != nil {
-			log.Fatalf("failed to accept: %v", err)
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
 		}
-		go accept(manager, apiGatewayClient, conn, logger)
+		go s.accept(conn)
 	}
+}
 
+// 心跳超时时间，超过该时长未收到任何数据的连接将被驱逐
+func (s *Server) heartbeatTimeout() time.Duration {
+	return time.Duration(s.conf.HeartbeatInterval*s.conf.HeartbeatMaxMiss) * time.Second
 }
 
-func accept(manager *ConnManager, apiGatewayClient apigatewayService.APIGatewayClient, conn net.Conn, logger *slog.Logger) {
+// 写入或续期连接的路由，有效期与心跳超时一致，网关崩溃后残留的路由随之过期
+func (s *Server) refreshRoute(user_uuid string, conn_uuid string) error {
+	return s.routes.Add(s.ctx, user_uuid, conn_uuid, s.rpcAddr, s.heartbeatTimeout())
+}
+
+func (s *Server) accept(rawConn net.Conn) {
+	conn := newStatConn(rawConn)
 	conn_uuid := ""
 	user_uuid := ""
+	defer func() {
+		// 认证后连接由writer在写完剩余的帧后关闭
+		if len(conn_uuid) == 0 {
+			conn.Close()
+			return
+		}
+		s.closeConnection(conn_uuid)
+	}()
+	decoder := plato.NewDecoder(conn, plato.WithMaxVarHeaderLen(s.conf.MaxVarHeaderLen), plato.WithMaxBodyLen(s.conf.MaxBodyLen))
+	encoder := plato.NewEncoder(conn)
+	logger := s.logger
 	for {
-		buf := make([]byte, 10)
-		n, err := conn.Read(buf)
+		// 认证前使用读超时淘汰空闲连接，认证后由时间轮统一检测
+		if len(conn_uuid) == 0 {
+			conn.SetReadDeadline(time.Now().Add(s.heartbeatTimeout()))
+		}
+		frame, err := decoder.Decode()
 		if err != nil {
 			if err != io.EOF {
-				logger.Error("failed to read", "error", err)
+				logger.Error("failed to decode frame", "error", err)
 			}
 			break
 		}
-		fixHeader := &plato.FixHeaderProtocol{}
-		if err := fixHeader.Unmarshal(buf[:n]); err != nil {
-			logger.Error("failed to unmarshal", "error", err)
-			break
+		if connection := s.manager.GetConnection(conn_uuid); connection != nil {
+			connection.Touch()
 		}
-		content := make([]byte, fixHeader.GetVarHeaderLen()+fixHeader.GetBodyLen())
-		if _, err := conn.Read(content); err != nil {
-			if err != io.EOF {
-				logger.Error("failed to read content", "error", err)
-				break
+		switch frame.MsgType {
+		case plato.MsgTypePing:
+			msg := plato.MessagePing{}
+			if err := frame.UnmarshalBody(&msg); err != nil {
+				logger.Error("failed to unmarshal ping", "error", err)
+				continue
+			}
+			if err := encoder.EncodeMessage(plato.MsgTypePong, &plato.MessagePong{Timestamp: msg.GetTimestamp()}); err != nil {
+				logger.Error("failed to write pong", "error", err)
+			}
+			if len(conn_uuid) > 0 {
+				if err := s.refreshRoute(user_uuid, conn_uuid); err != nil {
+					logger.Error("failed to refresh route", "error", err, "user_uuid", user_uuid)
+				}
 			}
-		}
-		switch fixHeader.GetMsgType() {
 		case plato.MsgTypeCreateConn:
+			if len(conn_uuid) > 0 {
+				logger.Error("connection already created", "conn_uuid", conn_uuid)
+				continue
+			}
 			msg := plato.MessageCreateConn{}
-			proto.Unmarshal(content[fixHeader.GetVarHeaderLen():], &msg)
+			if err := frame.UnmarshalBody(&msg); err != nil {
+				logger.Error("failed to unmarshal create conn", "error", err)
+				continue
+			}
 			logger.Info("receive create conn", "token", msg.GetToken())
 			claims, err := jwt.ValidateToken(msg.GetToken())
 			if err != nil {
@@ -117,80 +331,502 @@ func accept(manager *ConnManager, apiGatewayClient apigatewayService.APIGatewayC
 				logger.Error("validate claims error", "error", err)
 				continue
 			}
-			conn_uuid = manager.AddConnection(user_uuid, conn)
-			logger.Info("create conn success", "conn_uuid", conn_uuid, "user_uuid", user_uuid)
+			if s.draining.Load() {
+				if err := encoder.EncodeMessage(plato.MsgTypeReconnect, &plato.MessageReconnect{Reason: reconnectReason}); err != nil {
+					logger.Error("failed to write reconnect", "error", err)
+				}
+				return
+			}
+			_, framed := rawConn.(*wsConn)
+			writer := newConnWriter(conn, logger, s.conf.WriteQueueSize, time.Duration(s.conf.WriteTimeout)*time.Second, framed)
+			connection := s.manager.AddConnection(user_uuid, msg.GetDeviceId(), msg.GetPlatform(), msg.GetToken(), conn, writer)
+			conn_uuid = connection.conn_uuid
+			// 认证后所有写操作经过写队列，避免与下行消息交错
+			encoder = connection.encoder
+			s.kickConflicts(connection)
+			conn.SetReadDeadline(time.Time{})
+			s.watchIdle(conn_uuid, s.heartbeatTimeout())
+			if err := s.refreshRoute(user_uuid, conn_uuid); err != nil {
+				logger.Error("failed to add route", "error", err, "user_uuid", user_uuid)
+			}
+			if err := s.presence.Online(s.ctx, user_uuid, conn_uuid); err != nil {
+				logger.Error("failed to notify presence", "error", err, "user_uuid", user_uuid)
+			}
+			logger.Info("create conn success", "conn_uuid", conn_uuid, "user_uuid", user_uuid, "device_id", msg.GetDeviceId(), "platform", msg.GetPlatform())
 		case plato.MsgTypeMessageUpLink:
 			// 发送消息
-			if len(conn_uuid) == 0 || manager.GetConnection(conn_uuid) == nil {
+			if len(conn_uuid) == 0 || s.manager.GetConnection(conn_uuid) == nil {
 				logger.Error("connection not found", "conn_uuid", conn_uuid)
 				continue
 			}
 			msg := plato.MessageUpLink{}
-			proto.Unmarshal(content[fixHeader.GetVarHeaderLen():], &msg)
-			logger.Info("receive msg", "session_uuid", msg.GetSessionUuid(), "payload", msg.GetPayload())
-			session := manager.GetSession(msg.GetSessionUuid())
-			if session == nil {
-				sessionUserList, err := apiGatewayClient.GetSessionUserList(context.Background(), &apigatewayService.GetSessionUserListRequest{
-					SessionUuid: msg.GetSessionUuid(),
-				})
-				if err != nil {
-					logger.Error("failed to get session user list", "error", err)
-					continue
-				}
-				userUuids := make([]string, 0)
-				for _, user := range sessionUserList.Users {
-					userUuids = append(userUuids, user.UserUuid)
-				}
-				session = manager.AddSession(msg.GetSessionUuid(), userUuids)
-				if session == nil {
-					logger.Error("failed to add session", "session_uuid", msg.GetSessionUuid())
-					continue
-				}
+			if err := frame.UnmarshalBody(&msg); err != nil {
+				logger.Error("failed to unmarshal uplink", "error", err)
+				continue
 			}
-			_, err = apiGatewayClient.SendMessage(context.Background(), &apigatewayService.SendMessageRequest{
-				SessionUuid: msg.GetSessionUuid(),
-				Payload:     msg.GetPayload(),
-				SenderUuid:  user_uuid,
-				MessageType: int64(model.MessageTypeText),
-				SeqId:       int64(time.Now().UnixNano()),
-				Timestamp:   int64(time.Now().Unix()),
-			})
-			if err != nil {
-				logger.Error("failed to send message", "error", err)
+			s.handleUpLink(conn_uuid, user_uuid, &msg)
+		case plato.MsgTypeOpenSession:
+			if len(conn_uuid) == 0 || s.manager.GetConnection(conn_uuid) == nil {
+				logger.Error("connection not found", "conn_uuid", conn_uuid)
 				continue
 			}
-			for _, user := range session.user_uuids {
-				if user == user_uuid {
-					continue
-				}
-				connid := manager.GetUserConnUUID(user)
-				if connid == "" {
-					logger.Error("connection not found", "user_uuid", user)
-					continue
-				}
-				if connid == conn_uuid {
-					logger.Error("self send msg", "session_uuid", msg.GetSessionUuid(), "payload", msg.GetPayload(), "user_uuid", user, "conn_uuid", conn_uuid)
-					continue
-				}
-				connection := manager.GetConnection(connid)
-				if connection == nil {
-					logger.Error("connection not found")
-					continue
-				}
-				msg := &plato.MessageDownLink{
-					SessionUuid:    msg.GetSessionUuid(),
-					SenderUserUuid: user_uuid,
-					SeqId:          int64(time.Now().UnixNano()),
-					Payload:        msg.GetPayload(),
+			msg := plato.MessageOpenSession{}
+			if err := frame.UnmarshalBody(&msg); err != nil {
+				logger.Error("failed to unmarshal open session", "error", err)
+				continue
+			}
+			s.handleOpenSession(conn_uuid, user_uuid, &msg)
+		case plato.MsgTypeJoinSession:
+			if len(conn_uuid) == 0 || s.manager.GetConnection(conn_uuid) == nil {
+				logger.Error("connection not found", "conn_uuid", conn_uuid)
+				continue
+			}
+			msg := plato.MessageJoinSession{}
+			if err := frame.UnmarshalBody(&msg); err != nil {
+				logger.Error("failed to unmarshal join session", "error", err)
+				continue
+			}
+			s.handleJoinSession(conn_uuid, user_uuid, &msg)
+		case plato.MsgTypeLeaveSession:
+			if len(conn_uuid) == 0 || s.manager.GetConnection(conn_uuid) == nil {
+				logger.Error("connection not found", "conn_uuid", conn_uuid)
+				continue
+			}
+			msg := plato.MessageLeaveSession{}
+			if err := frame.UnmarshalBody(&msg); err != nil {
+				logger.Error("failed to unmarshal leave session", "error", err)
+				continue
+			}
+			s.handleLeaveSession(conn_uuid, user_uuid, &msg)
+		case plato.MsgTypeAck:
+			connection := s.manager.GetConnection(conn_uuid)
+			if connection == nil {
+				logger.Error("connection not found", "conn_uuid", conn_uuid)
+				continue
+			}
+			msg := plato.MessageAck{}
+			if err := frame.UnmarshalBody(&msg); err != nil {
+				logger.Error("failed to unmarshal ack", "error", err)
+				continue
+			}
+			if !connection.Ack(msg.GetMessageUuid()) {
+				logger.Debug("ack message not pending", "conn_uuid", conn_uuid, "message_uuid", msg.GetMessageUuid())
+			}
+		}
+	}
+	logger.Info("close", "conn_uuid", conn_uuid, "user_uuid", user_uuid)
+}
+
+// 清理连接并通知下线
+func (s *Server) closeConnection(conn_uuid string) {
+	connection := s.manager.RemoveConnection(conn_uuid)
+	if connection == nil {
+		return
+	}
+	// 写完队列中剩余的帧后由writer关闭连接
+	connection.writer.Close()
+	if err := s.routes.Remove(s.ctx, connection.user_uuid, conn_uuid); err != nil {
+		s.logger.Error("failed to remove route", "error", err, "user_uuid", connection.user_uuid)
+	}
+	if err := s.presence.Offline(s.ctx, connection.user_uuid, conn_uuid); err != nil {
+		s.logger.Error("failed to notify presence", "error", err, "user_uuid", connection.user_uuid)
+	}
+}
+
+// 按平台策略踢掉与新连接冲突的旧连接
+// 同一设备重连时旧连接已失效，独占平台上同一用户只保留最新的连接
+func (s *Server) kickConflicts(connection *Connection) {
+	_, exclusive := s.exclusive[connection.platform]
+	for _, other := range s.manager.GetUserConnections(connection.user_uuid) {
+		if other.conn_uuid == connection.conn_uuid {
+			continue
+		}
+		switch {
+		case len(connection.device_id) > 0 && other.device_id == connection.device_id:
+			s.kick(other, "当前设备已重新连接")
+		case exclusive && other.platform == connection.platform:
+			s.kick(other, "账号已在其他设备登录")
+		}
+	}
+}
+
+// 通知客户端被踢下线并关闭连接
+func (s *Server) kick(connection *Connection, reason string) {
+	s.logger.Info("kick connection", "conn_uuid", connection.conn_uuid, "user_uuid", connection.user_uuid, "device_id", connection.device_id, "platform", connection.platform, "reason", reason)
+	if err := connection.encoder.EncodeMessage(plato.MsgTypeKick, &plato.MessageKick{Reason: reason}); err != nil {
+		s.logger.Error("failed to write kick", "error", err, "conn_uuid", connection.conn_uuid)
+	}
+	s.closeConnection(connection.conn_uuid)
+}
+
+// Kick 踢掉本网关的连接
+func (s *Server) Kick(conn_uuid string, reason string) bool {
+	connection := s.manager.GetConnection(conn_uuid)
+	if connection == nil {
+		return false
+	}
+	s.kick(connection, reason)
+	return true
+}
+
+// KickUser 踢掉用户在本网关的连接，local_only为false时转发给用户连接所在的其他网关
+func (s *Server) KickUser(ctx context.Context, user_uuid string, reason string, local_only bool) (int, error) {
+	kicked := 0
+	for _, connection := range s.manager.GetUserConnections(user_uuid) {
+		s.kick(connection, reason)
+		kicked++
+	}
+	if local_only {
+		return kicked, nil
+	}
+	routes, err := s.routes.Lookup(ctx, []string{user_uuid})
+	if err != nil {
+		return kicked, err
+	}
+	gateway_addrs := make(map[string]struct{})
+	for _, gateway_addr := range routes[user_uuid] {
+		if gateway_addr != s.rpcAddr {
+			gateway_addrs[gateway_addr] = struct{}{}
+		}
+	}
+	var errs []error
+	for gateway_addr := range gateway_addrs {
+		client, err := s.gatewayClient(gateway_addr)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		resp, err := client.KickUser(ctx, &service.KickUserRequest{
+			UserUuid:  user_uuid,
+			Reason:    reason,
+			LocalOnly: true,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("kick user on gateway %s: %w", gateway_addr, err))
+			continue
+		}
+		kicked += int(resp.GetKicked())
+	}
+	return kicked, errors.Join(errs...)
+}
+
+// Connections 本网关的连接详情，user_uuid为空时返回全部
+func (s *Server) Connections(user_uuid string) []*service.ConnectionInfo {
+	var connections []*Connection
+	if len(user_uuid) > 0 {
+		connections = s.manager.GetUserConnections(user_uuid)
+	} else {
+		connections = s.manager.ListConnections()
+	}
+	infos := make([]*service.ConnectionInfo, 0, len(connections))
+	for _, connection := range connections {
+		infos = append(infos, connection.Info())
+	}
+	return infos
+}
+
+// ConnectionCount 本网关的连接数
+func (s *Server) ConnectionCount() int64 {
+	return int64(s.manager.Count())
+}
+
+// Connection 本网关的连接详情
+func (s *Server) Connection(conn_uuid string) *service.ConnectionInfo {
+	connection := s.manager.GetConnection(conn_uuid)
+	if connection == nil {
+		return nil
+	}
+	return connection.Info()
+}
+
+// 在时间轮上检测连接是否空闲超时，未超时则按剩余时间重新调度
+// 每个连接同一时刻只占用一个任务，不需要每次心跳重置定时器
+func (s *Server) watchIdle(conn_uuid string, delay time.Duration) {
+	s.timeWheel.AddDelayTask(func() {
+		connection := s.manager.GetConnection(conn_uuid)
+		if connection == nil {
+			return
+		}
+		timeout := s.heartbeatTimeout()
+		idle := connection.IdleDuration()
+		if idle >= timeout {
+			s.logger.Info("evict idle connection", "conn_uuid", conn_uuid, "user_uuid", connection.user_uuid, "idle", idle.String())
+			s.closeConnection(conn_uuid)
+			return
+		}
+		s.watchIdle(conn_uuid, timeout-idle)
+	}, delay)
+}
+
+func (s *Server) handleUpLink(conn_uuid string, user_uuid string, msg *plato.MessageUpLink) {
+	logger := s.logger
+	manager := s.manager
+	logger.Info("receive msg", "session_uuid", msg.GetSessionUuid(), "payload", msg.GetPayload())
+	session := manager.GetSession(msg.GetSessionUuid())
+	if session == nil {
+		epoch := manager.SessionEpoch()
+		sessionUserList, err := s.apiGatewayClient.GetSessionUserList(context.Background(), &apigatewayService.GetSessionUserListRequest{
+			SessionUuid: msg.GetSessionUuid(),
+		})
+		if err != nil {
+			logger.Error("failed to get session user list", "error", err)
+			return
+		}
+		userUuids := make([]string, 0)
+		for _, user := range sessionUserList.Users {
+			userUuids = append(userUuids, user.UserUuid)
+		}
+		session = manager.AddSession(msg.GetSessionUuid(), userUuids, epoch)
+		if session == nil {
+			logger.Error("failed to add session", "session_uuid", msg.GetSessionUuid())
+			return
+		}
+	}
+	connection := manager.GetConnection(conn_uuid)
+	// 兼容未携带client_msg_id的旧客户端，此时无法去重
+	client_msg_id := msg.GetClientMsgId()
+	if len(client_msg_id) == 0 {
+		client_msg_id = uuid.New().String()
+	}
+	resp, err := s.apiGatewayClient.SendMessage(context.Background(), &apigatewayService.SendMessageRequest{
+		SessionUuid: msg.GetSessionUuid(),
+		Payload:     msg.GetPayload(),
+		SenderUuid:  user_uuid,
+		MessageType: int64(model.MessageTypeText),
+		Timestamp:   int64(time.Now().Unix()),
+		ClientMsgId: client_msg_id,
+	})
+	if err != nil {
+		logger.Error("failed to send message", "error", err)
+		s.ackUpLink(connection, &plato.MessageUpLinkAck{
+			ClientMsgId: msg.GetClientMsgId(),
+			SessionUuid: msg.GetSessionUuid(),
+			Error:       err.Error(),
+		})
+		return
+	}
+	s.ackUpLink(connection, &plato.MessageUpLinkAck{
+		ClientMsgId: msg.GetClientMsgId(),
+		SessionUuid: msg.GetSessionUuid(),
+		MessageUuid: resp.GetMessageUuid(),
+		SeqId:       resp.GetSeqId(),
+	})
+	// 重复的上行消息在首次处理时已经投递，不再扇出
+	if resp.GetDuplicate() {
+		logger.Info("duplicate uplink", "client_msg_id", client_msg_id, "message_uuid", resp.GetMessageUuid())
+		return
+	}
+	// 扇出到会话成员的所有设备，包括发送者的其他设备
+	s.route(session.user_uuids, conn_uuid, &plato.MessageDownLink{
+		SessionUuid:    msg.GetSessionUuid(),
+		SenderUserUuid: user_uuid,
+		SeqId:          resp.GetSeqId(),
+		Payload:        msg.GetPayload(),
+		MessageUuid:    resp.GetMessageUuid(),
+		MessageType:    model.MessageTypeText,
+	})
+}
+
+// 按路由表将消息分发到用户连接所在的网关，本网关的连接直接投递
+func (s *Server) route(user_uuids []string, exclude_conn_uuid string, msg *plato.MessageDownLink) {
+	routes, err := s.routes.Lookup(s.ctx, user_uuids)
+	if err != nil {
+		// 路由表不可用时至少保证本网关的连接能收到
+		s.logger.Error("failed to lookup routes", "error", err)
+		s.Push(user_uuids, exclude_conn_uuid, msg)
+		return
+	}
+	gateways := make(map[string]map[string]map[string]struct{}) // 网关RPC地址 -> 用户UUID -> 连接UUID
+	for user_uuid, conns := range routes {
+		for conn_uuid, gateway_addr := range conns {
+			if _, ok := gateways[gateway_addr]; !ok {
+				gateways[gateway_addr] = make(map[string]map[string]struct{})
+			}
+			if _, ok := gateways[gateway_addr][user_uuid]; !ok {
+				gateways[gateway_addr][user_uuid] = make(map[string]struct{})
+			}
+			gateways[gateway_addr][user_uuid][conn_uuid] = struct{}{}
+		}
+	}
+	for gateway_addr, users := range gateways {
+		targets := make([]string, 0, len(users))
+		for user_uuid := range users {
+			targets = append(targets, user_uuid)
+		}
+		if gateway_addr == s.rpcAddr {
+			s.Push(targets, exclude_conn_uuid, msg)
+			continue
+		}
+		if err := s.pushRemote(gateway_addr, targets, exclude_conn_uuid, msg); err != nil {
+			s.logger.Error("failed to push to gateway", "error", err, "gateway_addr", gateway_addr)
+			// 网关已下线，清理残留的路由
+			if status.Code(err) == codes.Unavailable {
+				for user_uuid, conns := range users {
+					for conn_uuid := range conns {
+						if err := s.routes.Remove(s.ctx, user_uuid, conn_uuid); err != nil {
+							s.logger.Error("failed to remove route", "error", err, "user_uuid", user_uuid)
+						}
+					}
 				}
-				downLinkmsg, _ := proto.Marshal(msg)
-				connection.conn.Write(plato.Marshal(1, plato.MsgTypeMessageDownLink, nil, downLinkmsg))
-				logger.Info("send msg", "from_user_uuid", user_uuid, "to_conn_id", connid, "session_uuid", msg.GetSessionUuid(), "payload", msg.GetPayload())
+			}
+		}
+	}
+}
 
+// Push 投递消息到本网关持有的用户连接
+func (s *Server) Push(user_uuids []string, exclude_conn_uuid string, msg *plato.MessageDownLink) int {
+	delivered := 0
+	for _, user := range user_uuids {
+		for _, connection := range s.manager.GetUserConnections(user) {
+			if connection.conn_uuid == exclude_conn_uuid {
+				continue
 			}
+			if err := s.pushDownLink(connection.conn_uuid, connection, msg); err != nil {
+				s.logger.Error("failed to write downlink", "error", err, "to_conn_id", connection.conn_uuid)
+				continue
+			}
+			delivered++
+			s.logger.Info("send msg", "from_user_uuid", msg.GetSenderUserUuid(), "to_conn_id", connection.conn_uuid, "session_uuid", msg.GetSessionUuid(), "payload", msg.GetPayload())
+		}
+	}
+	return delivered
+}
+
+// 通过RPC投递到其他网关
+func (s *Server) pushRemote(gateway_addr string, user_uuids []string, exclude_conn_uuid string, msg *plato.MessageDownLink) error {
+	client, err := s.gatewayClient(gateway_addr)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(s.ctx, 3*time.Second)
+	defer cancel()
+	_, err = client.PushMessage(ctx, &service.PushMessageRequest{
+		UserUuids:       user_uuids,
+		ExcludeConnUuid: exclude_conn_uuid,
+		SessionUuid:     msg.GetSessionUuid(),
+		SenderUserUuid:  msg.GetSenderUserUuid(),
+		Payload:         msg.GetPayload(),
+		SeqId:           msg.GetSeqId(),
+		MessageUuid:     msg.GetMessageUuid(),
+		MessageType:     msg.GetMessageType(),
+	})
+	return err
+}
 
+func (s *Server) gatewayClient(gateway_addr string) (service.IMGatewayClient, error) {
+	s.gatewayClientsLocker.Lock()
+	defer s.gatewayClientsLocker.Unlock()
+	if client, ok := s.gatewayClients[gateway_addr]; ok {
+		return client, nil
+	}
+	if s.gatewayDialOption == nil {
+		dialOption, err := xtls.DialOption(s.ctx, s.conf.TLSConfig, s.logger)
+		if err != nil {
+			return nil, err
 		}
+		s.gatewayDialOption = dialOption
+	}
+	conn, err := grpc.NewClient(gateway_addr, s.gatewayDialOption, grpc.WithUnaryInterceptor(grpcmiddreware.ServiceTokenClientInterceptor(s.conf.ServiceToken)))
+	if err != nil {
+		return nil, err
+	}
+	client := service.NewIMGatewayClient(conn)
+	s.gatewayClients[gateway_addr] = client
+	return client, nil
+}
 
+func (s *Server) ackUpLink(connection *Connection, ack *plato.MessageUpLinkAck) {
+	if connection == nil {
+		return
+	}
+	if err := s.write(connection, plato.MsgTypeUpLinkAck, ack); err != nil {
+		s.logger.Error("failed to write uplink ack", "error", err, "client_msg_id", ack.GetClientMsgId())
+	}
+}
+
+// 写入连接的写队列，队列已满且策略为断开时关闭连接
+// 上行确认等丢失后客户端会重试的帧不需要补拉
+func (s *Server) write(connection *Connection, msgType int8, msg proto.Message) error {
+	err := connection.encoder.EncodeMessage(msgType, msg)
+	if errors.Is(err, ErrWriteQueueFull) && s.conf.WriteQueuePolicy != WriteQueuePolicyDrop {
+		s.logger.Error("write queue full, close connection", "conn_uuid", connection.conn_uuid)
+		s.closeConnection(connection.conn_uuid)
+	}
+	return err
+}
+
+// 下发消息并加入待确认窗口，超时未确认时重传
+func (s *Server) pushDownLink(conn_uuid string, connection *Connection, msg *plato.MessageDownLink) error {
+	if !connection.AddPending(msg, s.conf.AckWindow) {
+		// 客户端长时间不确认，断开后由客户端重连并通过历史消息补齐
+		s.logger.Error("ack window full, close connection", "conn_uuid", conn_uuid, "pending", connection.PendingCount())
+		s.closeConnection(conn_uuid)
+		return errors.New("ack window full")
+	}
+	if err := s.writeDownLink(connection, msg); err != nil {
+		return err
+	}
+	s.watchAck(conn_uuid, msg.GetMessageUuid())
+	return nil
+}
+
+// 写入下行消息，队列已满时按策略断开连接或丢弃消息并稍后通知客户端补拉
+func (s *Server) writeDownLink(connection *Connection, msg *plato.MessageDownLink) error {
+	err := s.write(connection, plato.MsgTypeMessageDownLink, msg)
+	if !errors.Is(err, ErrWriteQueueFull) || s.conf.WriteQueuePolicy != WriteQueuePolicyDrop {
+		return err
+	}
+	connection.Ack(msg.GetMessageUuid())
+	s.logger.Warn("write queue full, drop downlink", "conn_uuid", connection.conn_uuid, "session_uuid", msg.GetSessionUuid(), "seq_id", msg.GetSeqId())
+	if connection.MarkResync(msg.GetSessionUuid(), msg.GetSeqId()) {
+		s.watchResync(connection.conn_uuid)
 	}
-	logger.Info("close")
+	return err
+}
+
+// 队列恢复后通知客户端补拉被丢弃的消息，仍然写不进去时稍后重试
+func (s *Server) watchResync(conn_uuid string) {
+	s.timeWheel.AddDelayTask(func() {
+		connection := s.manager.GetConnection(conn_uuid)
+		if connection == nil {
+			return
+		}
+		retry := false
+		for session_uuid, seq_id := range connection.TakeResync() {
+			if err := connection.encoder.EncodeMessage(plato.MsgTypeResync, &plato.MessageResync{
+				SessionUuid: session_uuid,
+				StartSeqid:  seq_id - 1,
+			}); err != nil {
+				connection.MarkResync(session_uuid, seq_id)
+				retry = true
+			}
+		}
+		if retry {
+			s.watchResync(conn_uuid)
+		}
+	}, time.Second)
+}
+
+func (s *Server) watchAck(conn_uuid string, message_uuid string) {
+	s.timeWheel.AddDelayTask(func() {
+		connection := s.manager.GetConnection(conn_uuid)
+		if connection == nil {
+			return
+		}
+		msg, retries := connection.Retry(message_uuid)
+		if msg == nil {
+			return
+		}
+		if retries > s.conf.MaxRetransmit {
+			s.logger.Error("retransmit exceeded, close connection", "conn_uuid", conn_uuid, "message_uuid", message_uuid)
+			s.closeConnection(conn_uuid)
+			return
+		}
+		s.logger.Info("retransmit downlink", "conn_uuid", conn_uuid, "message_uuid", message_uuid, "retries", retries)
+		if err := s.writeDownLink(connection, msg); err != nil {
+			s.logger.Error("failed to retransmit downlink", "error", err, "conn_uuid", conn_uuid)
+			return
+		}
+		s.watchAck(conn_uuid, message_uuid)
+	}, time.Duration(s.conf.AckTimeout)*time.Second)
 }
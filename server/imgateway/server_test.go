@@ -0,0 +1,975 @@
+package imgateway
+
+import (
+	"context"
+	"fmt"
+	"im/model"
+	"im/pkg/config"
+	"im/pkg/event"
+	"im/pkg/grpcmiddreware"
+	"im/pkg/jwt"
+	"im/pkg/plato"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	apigatewayService "im/server/apigateway/rpc/service"
+	"im/server/imgateway/rpc/service"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gorilla/websocket"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// 内存实现的apigateway客户端
+type fakeAPIGatewayClient struct {
+	apigatewayService.APIGatewayClient
+	mu       sync.Mutex
+	sessions map[string][]string
+	messages []*apigatewayService.SendMessageRequest
+	seqIds   []int64
+	seqs     map[string]int64
+}
+
+func newFakeAPIGatewayClient() *fakeAPIGatewayClient {
+	return &fakeAPIGatewayClient{sessions: make(map[string][]string), seqs: make(map[string]int64)}
+}
+
+func (c *fakeAPIGatewayClient) GetSessionUserList(ctx context.Context, in *apigatewayService.GetSessionUserListRequest, opts ...grpc.CallOption) (*apigatewayService.GetSessionUserListResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &apigatewayService.GetSessionUserListResponse{}
+	for _, user_uuid := range c.sessions[in.SessionUuid] {
+		resp.Users = append(resp.Users, &apigatewayService.SessionUserListItem{UserUuid: user_uuid})
+	}
+	return resp, nil
+}
+
+func (c *fakeAPIGatewayClient) SendMessage(ctx context.Context, in *apigatewayService.SendMessageRequest, opts ...grpc.CallOption) (*apigatewayService.SendMessageResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, message := range c.messages {
+		if len(in.ClientMsgId) > 0 && message.SenderUuid == in.SenderUuid && message.ClientMsgId == in.ClientMsgId {
+			return &apigatewayService.SendMessageResponse{MessageUuid: fmt.Sprintf("message-%d", i), SeqId: c.seqIds[i], Duplicate: true}, nil
+		}
+	}
+	return c.appendMessage(in), nil
+}
+
+func (c *fakeAPIGatewayClient) appendMessage(in *apigatewayService.SendMessageRequest) *apigatewayService.SendMessageResponse {
+	c.seqs[in.SessionUuid]++
+	c.messages = append(c.messages, in)
+	c.seqIds = append(c.seqIds, c.seqs[in.SessionUuid])
+	return &apigatewayService.SendMessageResponse{MessageUuid: fmt.Sprintf("message-%d", len(c.messages)-1), SeqId: c.seqs[in.SessionUuid]}
+}
+
+func (c *fakeAPIGatewayClient) systemMessage(session_uuid string, operator_uuid string, content string) *apigatewayService.Message {
+	resp := c.appendMessage(&apigatewayService.SendMessageRequest{SessionUuid: session_uuid, SenderUuid: operator_uuid, Payload: content})
+	return &apigatewayService.Message{MessageUuid: resp.MessageUuid, SessionUuid: session_uuid, SeqId: resp.SeqId, Content: content, SenderUuid: operator_uuid}
+}
+
+// 和API网关一样从令牌中获取操作者
+func tokenUser(ctx context.Context) (string, error) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	token := md.Get("token")
+	if len(token) == 0 {
+		return "", status.Errorf(codes.Unauthenticated, "token is required")
+	}
+	claims, err := jwt.ValidateToken(token[0])
+	if err != nil {
+		return "", status.Errorf(codes.Unauthenticated, "validate token error: %v", err)
+	}
+	return claims.GetSubject()
+}
+
+func (c *fakeAPIGatewayClient) CreateSession(ctx context.Context, in *apigatewayService.CreateSessionRequest, opts ...grpc.CallOption) (*apigatewayService.CreateSessionResponse, error) {
+	creator_uuid, err := tokenUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	session_uuid := fmt.Sprintf("group-%d", len(c.sessions))
+	c.sessions[session_uuid] = append([]string{creator_uuid}, in.MemberUuids...)
+	return &apigatewayService.CreateSessionResponse{
+		SessionUuid: session_uuid,
+		MemberUuids: c.sessions[session_uuid],
+		Message:     c.systemMessage(session_uuid, creator_uuid, "created"),
+	}, nil
+}
+
+func (c *fakeAPIGatewayClient) JoinSession(ctx context.Context, in *apigatewayService.JoinSessionRequest, opts ...grpc.CallOption) (*apigatewayService.JoinSessionResponse, error) {
+	operator_uuid, err := tokenUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.sessions[in.SessionUuid]; !ok {
+		return nil, fmt.Errorf("session %s not found", in.SessionUuid)
+	}
+	c.sessions[in.SessionUuid] = append(c.sessions[in.SessionUuid], in.UserUuids...)
+	return &apigatewayService.JoinSessionResponse{
+		JoinedUuids: in.UserUuids,
+		MemberUuids: c.sessions[in.SessionUuid],
+		Message:     c.systemMessage(in.SessionUuid, operator_uuid, "joined"),
+	}, nil
+}
+
+func (c *fakeAPIGatewayClient) LeaveSession(ctx context.Context, in *apigatewayService.LeaveSessionRequest, opts ...grpc.CallOption) (*apigatewayService.LeaveSessionResponse, error) {
+	leaver_uuid, err := tokenUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	members := make([]string, 0)
+	for _, user_uuid := range c.sessions[in.SessionUuid] {
+		if user_uuid != leaver_uuid {
+			members = append(members, user_uuid)
+		}
+	}
+	c.sessions[in.SessionUuid] = members
+	return &apigatewayService.LeaveSessionResponse{
+		MemberUuids: members,
+		Message:     c.systemMessage(in.SessionUuid, leaver_uuid, "left"),
+	}, nil
+}
+
+func (c *fakeAPIGatewayClient) setSession(session_uuid string, user_uuids ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sessions[session_uuid] = user_uuids
+}
+
+// 记录上下线事件
+type recordPresence struct {
+	events chan PresenceEvent
+}
+
+func newRecordPresence() *recordPresence {
+	return &recordPresence{events: make(chan PresenceEvent, 16)}
+}
+
+func (p *recordPresence) Online(ctx context.Context, user_uuid string, conn_uuid string) error {
+	p.events <- PresenceEvent{UserUUID: user_uuid, ConnUUID: conn_uuid, Status: PresenceStatusOnline}
+	return nil
+}
+
+func (p *recordPresence) Offline(ctx context.Context, user_uuid string, conn_uuid string) error {
+	p.events <- PresenceEvent{UserUUID: user_uuid, ConnUUID: conn_uuid, Status: PresenceStatusOffline}
+	return nil
+}
+
+func (p *recordPresence) wait(t *testing.T, status string) PresenceEvent {
+	t.Helper()
+	select {
+	case event := <-p.events:
+		if event.Status != status {
+			t.Fatalf("expected %s, got %v", status, event)
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatalf("wait presence %s timeout", status)
+	}
+	return PresenceEvent{}
+}
+
+func newTestConf() *config.IMGatewayConfig {
+	conf := &config.IMGatewayConfig{}
+	config.Unmarshal(conf)
+	conf.HeartbeatInterval = 1
+	conf.HeartbeatMaxMiss = 1
+	return conf
+}
+
+func newTestRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	redisServer := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: redisServer.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+	return redisClient
+}
+
+// 启动网关，包括长连接和RPC服务，conf.RpcAddr会被替换为实际监听地址
+func startTestServer(t *testing.T, conf *config.IMGatewayConfig, apiGatewayClient apigatewayService.APIGatewayClient, presence Presence, routes RouteStore) (*Server, string) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	rpcListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	conf.RpcAddr = rpcListener.Addr().String()
+	server := NewServer(ctx, conf, logger, apiGatewayClient, presence, routes)
+	rpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(grpcmiddreware.ServiceTokenUnaryInterceptor(conf.ServiceToken, service.InternalMethods...)))
+	service.RegisterIMGatewayServer(rpcServer, service.NewIMGatewayService(ctx, logger, conf, server))
+	go rpcServer.Serve(rpcListener)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		listener.Close()
+		rpcServer.Stop()
+		cancel()
+	})
+	return server, listener.Addr().String()
+}
+
+type testClient struct {
+	conn    net.Conn
+	encoder *plato.Encoder
+	decoder *plato.Decoder
+}
+
+func dialTestClient(t *testing.T, addr string, user_uuid string) *testClient {
+	t.Helper()
+	return dialTestDevice(t, addr, user_uuid, "", "")
+}
+
+func dialTestDevice(t *testing.T, addr string, user_uuid string, device_id string, platform string) *testClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	return newTestClient(t, conn, user_uuid, device_id, platform)
+}
+
+func newTestClient(t *testing.T, conn net.Conn, user_uuid string, device_id string, platform string) *testClient {
+	t.Helper()
+	t.Cleanup(func() { conn.Close() })
+	client := &testClient{conn: conn, encoder: plato.NewEncoder(conn), decoder: plato.NewDecoder(conn)}
+	if len(user_uuid) > 0 {
+		token, _, err := jwt.GenerateToken(user_uuid, 3600, nil)
+		if err != nil {
+			t.Fatalf("failed to generate token: %v", err)
+		}
+		if err := client.encoder.EncodeMessage(plato.MsgTypeCreateConn, &plato.MessageCreateConn{
+			Token:    token,
+			DeviceId: device_id,
+			Platform: platform,
+		}); err != nil {
+			t.Fatalf("failed to create conn: %v", err)
+		}
+	}
+	return client
+}
+
+// 读取下一个指定类型的帧，跳过其他类型
+func (c *testClient) read(t *testing.T, msgType int) *plato.Frame {
+	t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		frame, err := c.decoder.Decode()
+		if err != nil {
+			t.Fatalf("failed to read frame type %d: %v", msgType, err)
+		}
+		if frame.MsgType == msgType {
+			return frame
+		}
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	presence := newRecordPresence()
+	_, addr := startTestServer(t, newTestConf(), newFakeAPIGatewayClient(), presence, NewRedisRouteStore(newTestRedis(t)))
+
+	t.Run("ping pong", func(t *testing.T) {
+		client := dialTestClient(t, addr, "")
+		if err := client.encoder.EncodeMessage(plato.MsgTypePing, &plato.MessagePing{Timestamp: 123}); err != nil {
+			t.Fatalf("failed to ping: %v", err)
+		}
+		pong := &plato.MessagePong{}
+		if err := client.read(t, plato.MsgTypePong).UnmarshalBody(pong); err != nil || pong.GetTimestamp() != 123 {
+			t.Fatalf("unexpected pong: %v %v", pong, err)
+		}
+	})
+
+	t.Run("evict idle connection", func(t *testing.T) {
+		client := dialTestClient(t, addr, "user-idle")
+		online := presence.wait(t, PresenceStatusOnline)
+		offline := presence.wait(t, PresenceStatusOffline)
+		if online.ConnUUID != offline.ConnUUID || offline.UserUUID != "user-idle" {
+			t.Fatalf("unexpected presence: %v %v", online, offline)
+		}
+		client.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		if _, err := client.decoder.Decode(); err != io.EOF {
+			t.Fatalf("expected connection closed, got %v", err)
+		}
+	})
+}
+
+func (c *testClient) send(t *testing.T, session_uuid string, payload string, client_msg_id string) *plato.MessageUpLinkAck {
+	t.Helper()
+	if err := c.encoder.EncodeMessage(plato.MsgTypeMessageUpLink, &plato.MessageUpLink{
+		SessionUuid: session_uuid,
+		Payload:     payload,
+		ClientMsgId: client_msg_id,
+	}); err != nil {
+		t.Fatalf("failed to send: %v", err)
+	}
+	ack := &plato.MessageUpLinkAck{}
+	if err := c.read(t, plato.MsgTypeUpLinkAck).UnmarshalBody(ack); err != nil {
+		t.Fatalf("failed to unmarshal uplink ack: %v", err)
+	}
+	if ack.GetClientMsgId() != client_msg_id || len(ack.GetError()) > 0 {
+		t.Fatalf("unexpected uplink ack: %v", ack)
+	}
+	return ack
+}
+
+func (c *testClient) receive(t *testing.T) *plato.MessageDownLink {
+	t.Helper()
+	msg := &plato.MessageDownLink{}
+	if err := c.read(t, plato.MsgTypeMessageDownLink).UnmarshalBody(msg); err != nil {
+		t.Fatalf("failed to unmarshal downlink: %v", err)
+	}
+	return msg
+}
+
+func (c *testClient) ack(t *testing.T, message_uuid string) {
+	t.Helper()
+	if err := c.encoder.EncodeMessage(plato.MsgTypeAck, &plato.MessageAck{MessageUuid: message_uuid}); err != nil {
+		t.Fatalf("failed to ack: %v", err)
+	}
+}
+
+// 等待连接建立完成，避免CreateConn与后续帧的处理顺序影响断言
+func waitOnline(t *testing.T, presence *recordPresence, count int) {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		presence.wait(t, PresenceStatusOnline)
+	}
+}
+
+func TestReliableDelivery(t *testing.T) {
+	conf := newTestConf()
+	conf.HeartbeatInterval = 60
+	conf.AckTimeout = 2
+	apiGatewayClient := newFakeAPIGatewayClient()
+	apiGatewayClient.setSession("session-1", "user-a", "user-b")
+	presence := newRecordPresence()
+	_, addr := startTestServer(t, conf, apiGatewayClient, presence, NewRedisRouteStore(newTestRedis(t)))
+
+	clientA := dialTestClient(t, addr, "user-a")
+	clientB := dialTestClient(t, addr, "user-b")
+	waitOnline(t, presence, 2)
+
+	ack := clientA.send(t, "session-1", "hello", "client-msg-1")
+	msg := clientB.receive(t)
+	if msg.GetMessageUuid() != ack.GetMessageUuid() || msg.GetSeqId() != ack.GetSeqId() || msg.GetPayload() != "hello" {
+		t.Fatalf("unexpected downlink: %v, ack: %v", msg, ack)
+	}
+
+	// 未确认的消息超时后重传
+	retransmit := clientB.receive(t)
+	if retransmit.GetMessageUuid() != msg.GetMessageUuid() {
+		t.Fatalf("unexpected retransmit: %v", retransmit)
+	}
+	clientB.ack(t, msg.GetMessageUuid())
+
+	// 重复的上行消息返回首次分配的消息UUID，且不再扇出
+	duplicate := clientA.send(t, "session-1", "hello", "client-msg-1")
+	if duplicate.GetMessageUuid() != ack.GetMessageUuid() || duplicate.GetSeqId() != ack.GetSeqId() {
+		t.Fatalf("unexpected duplicate ack: %v", duplicate)
+	}
+	clientA.send(t, "session-1", "world", "client-msg-2")
+	next := clientB.receive(t)
+	if next.GetPayload() != "world" || next.GetSeqId() != msg.GetSeqId()+1 {
+		t.Fatalf("expected next message, got %v", next)
+	}
+}
+
+func TestMultiDevice(t *testing.T) {
+	conf := newTestConf()
+	conf.HeartbeatInterval = 60
+	apiGatewayClient := newFakeAPIGatewayClient()
+	apiGatewayClient.setSession("session-1", "user-a", "user-b")
+	presence := newRecordPresence()
+	_, addr := startTestServer(t, conf, apiGatewayClient, presence, NewRedisRouteStore(newTestRedis(t)))
+
+	desktop := dialTestDevice(t, addr, "user-a", "device-1", plato.PlatformDesktop)
+	mobile := dialTestDevice(t, addr, "user-a", "device-2", plato.PlatformMobile)
+	clientB := dialTestDevice(t, addr, "user-b", "device-3", plato.PlatformDesktop)
+	waitOnline(t, presence, 3)
+
+	t.Run("fan out to all devices", func(t *testing.T) {
+		ack := desktop.send(t, "session-1", "hello", "client-msg-1")
+		for _, client := range []*testClient{mobile, clientB} {
+			msg := client.receive(t)
+			if msg.GetMessageUuid() != ack.GetMessageUuid() || msg.GetSenderUserUuid() != "user-a" {
+				t.Fatalf("unexpected downlink: %v", msg)
+			}
+			client.ack(t, msg.GetMessageUuid())
+		}
+	})
+
+	t.Run("kick exclusive platform", func(t *testing.T) {
+		dialTestDevice(t, addr, "user-a", "device-4", plato.PlatformDesktop)
+		kick := &plato.MessageKick{}
+		if err := desktop.read(t, plato.MsgTypeKick).UnmarshalBody(kick); err != nil || len(kick.GetReason()) == 0 {
+			t.Fatalf("unexpected kick: %v %v", kick, err)
+		}
+		if _, err := desktop.decoder.Decode(); err != io.EOF {
+			t.Fatalf("expected connection closed, got %v", err)
+		}
+		// 旧连接先下线，新连接再上线
+		presence.wait(t, PresenceStatusOffline)
+		waitOnline(t, presence, 1)
+	})
+
+	t.Run("kick same device", func(t *testing.T) {
+		dialTestDevice(t, addr, "user-a", "device-2", plato.PlatformMobile)
+		mobile.read(t, plato.MsgTypeKick)
+		if _, err := mobile.decoder.Decode(); err != io.EOF {
+			t.Fatalf("expected connection closed, got %v", err)
+		}
+	})
+}
+
+func TestCrossGatewayRouting(t *testing.T) {
+	apiGatewayClient := newFakeAPIGatewayClient()
+	apiGatewayClient.setSession("session-1", "user-a", "user-b")
+	routes := NewRedisRouteStore(newTestRedis(t))
+	presence := newRecordPresence()
+	addrs := make([]string, 0, 2)
+	for i := 0; i < 2; i++ {
+		conf := newTestConf()
+		conf.HeartbeatInterval = 60
+		_, addr := startTestServer(t, conf, apiGatewayClient, presence, routes)
+		addrs = append(addrs, addr)
+	}
+
+	desktop := dialTestDevice(t, addrs[0], "user-a", "device-1", plato.PlatformDesktop)
+	mobile := dialTestDevice(t, addrs[1], "user-a", "device-2", plato.PlatformMobile)
+	clientB := dialTestDevice(t, addrs[1], "user-b", "device-3", plato.PlatformDesktop)
+	waitOnline(t, presence, 3)
+
+	ack := desktop.send(t, "session-1", "hello", "client-msg-1")
+	for _, client := range []*testClient{mobile, clientB} {
+		msg := client.receive(t)
+		if msg.GetMessageUuid() != ack.GetMessageUuid() || msg.GetSeqId() != ack.GetSeqId() {
+			t.Fatalf("unexpected downlink: %v", msg)
+		}
+		client.ack(t, msg.GetMessageUuid())
+	}
+
+	// 连接断开后路由被清理
+	clientB.conn.Close()
+	event := presence.wait(t, PresenceStatusOffline)
+	userRoutes, err := routes.Lookup(context.Background(), []string{"user-b"})
+	if err != nil {
+		t.Fatalf("failed to lookup routes: %v", err)
+	}
+	if _, ok := userRoutes["user-b"][event.ConnUUID]; ok {
+		t.Fatalf("route not removed: %v", userRoutes)
+	}
+
+	// 反方向投递
+	mobile.send(t, "session-1", "world", "client-msg-2")
+	msg := desktop.receive(t)
+	if msg.GetPayload() != "world" {
+		t.Fatalf("unexpected downlink: %v", msg)
+	}
+}
+
+func TestRouteExpire(t *testing.T) {
+	ctx := context.Background()
+	redisClient := newTestRedis(t)
+	routes := NewRedisRouteStore(redisClient)
+	now := time.Now()
+	routes.(*redisRouteStore).now = func() time.Time { return now }
+
+	if err := routes.Add(ctx, "user-a", "conn-1", "10.0.0.1:8087", time.Minute); err != nil {
+		t.Fatalf("failed to add route: %v", err)
+	}
+	if err := routes.Add(ctx, "user-a", "conn-2", "10.0.0.2:8087", 3*time.Minute); err != nil {
+		t.Fatalf("failed to add route: %v", err)
+	}
+	// 旧版本写入的路由没有过期时间
+	if err := redisClient.HSet(ctx, routeKey("user-a"), "conn-3", "10.0.0.3:8087").Err(); err != nil {
+		t.Fatalf("failed to add legacy route: %v", err)
+	}
+	if ttl := redisClient.TTL(ctx, routeKey("user-a")).Val(); ttl <= time.Minute {
+		t.Fatalf("expected key ttl refreshed, got %v", ttl)
+	}
+
+	// conn-1未续期已过期，查询时被过滤并清理
+	now = now.Add(2 * time.Minute)
+	userRoutes, err := routes.Lookup(ctx, []string{"user-a", "user-b"})
+	if err != nil {
+		t.Fatalf("failed to lookup routes: %v", err)
+	}
+	expected := map[string]string{"conn-2": "10.0.0.2:8087", "conn-3": "10.0.0.3:8087"}
+	if len(userRoutes) != 1 || len(userRoutes["user-a"]) != len(expected) {
+		t.Fatalf("unexpected routes: %v", userRoutes)
+	}
+	for conn_uuid, gateway_addr := range expected {
+		if userRoutes["user-a"][conn_uuid] != gateway_addr {
+			t.Fatalf("unexpected routes: %v", userRoutes)
+		}
+	}
+	if redisClient.HExists(ctx, routeKey("user-a"), "conn-1").Val() {
+		t.Fatalf("expired route not removed")
+	}
+
+	// 心跳续期后重新生效
+	if err := routes.Add(ctx, "user-a", "conn-1", "10.0.0.1:8087", time.Minute); err != nil {
+		t.Fatalf("failed to refresh route: %v", err)
+	}
+	userRoutes, err = routes.Lookup(ctx, []string{"user-a"})
+	if err != nil {
+		t.Fatalf("failed to lookup routes: %v", err)
+	}
+	if userRoutes["user-a"]["conn-1"] != "10.0.0.1:8087" {
+		t.Fatalf("route not refreshed: %v", userRoutes)
+	}
+}
+
+func newTestGatewayClient(t *testing.T, conf *config.IMGatewayConfig) service.IMGatewayClient {
+	t.Helper()
+	conn, err := grpc.NewClient(conf.RpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(grpcmiddreware.ServiceTokenClientInterceptor(conf.ServiceToken)))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return service.NewIMGatewayClient(conn)
+}
+
+func TestConnectionAdmin(t *testing.T) {
+	ctx := context.Background()
+	routes := NewRedisRouteStore(newTestRedis(t))
+	presence := newRecordPresence()
+	confs := make([]*config.IMGatewayConfig, 0, 2)
+	addrs := make([]string, 0, 2)
+	for i := 0; i < 2; i++ {
+		conf := newTestConf()
+		conf.HeartbeatInterval = 60
+		conf.ServiceToken = "secret"
+		_, addr := startTestServer(t, conf, newFakeAPIGatewayClient(), presence, routes)
+		confs = append(confs, conf)
+		addrs = append(addrs, addr)
+	}
+	client := newTestGatewayClient(t, confs[0])
+
+	desktop := dialTestDevice(t, addrs[0], "user-a", "device-1", plato.PlatformDesktop)
+	mobile := dialTestDevice(t, addrs[1], "user-a", "device-2", plato.PlatformMobile)
+	clientB := dialTestDevice(t, addrs[0], "user-b", "device-3", plato.PlatformDesktop)
+	clientC := dialTestDevice(t, addrs[0], "user-c", "device-4", plato.PlatformDesktop)
+	waitOnline(t, presence, 4)
+
+	t.Run("service token required", func(t *testing.T) {
+		for _, token := range []string{"", "wrong"} {
+			conf := *confs[0]
+			conf.ServiceToken = token
+			_, err := newTestGatewayClient(t, &conf).ListConnections(ctx, &service.ListConnectionsRequest{})
+			if status.Code(err) != codes.Unauthenticated {
+				t.Fatalf("expected unauthenticated with token %q, got %v", token, err)
+			}
+		}
+	})
+
+	t.Run("list connections", func(t *testing.T) {
+		resp, err := client.ListConnections(ctx, &service.ListConnectionsRequest{PageSize: 2, Page: 2})
+		if err != nil {
+			t.Fatalf("failed to list connections: %v", err)
+		}
+		if resp.GetTotal() != 3 || len(resp.GetConnections()) != 1 {
+			t.Fatalf("unexpected page: %v", resp)
+		}
+		resp, err = client.ListConnections(ctx, &service.ListConnectionsRequest{UserUuid: "user-a"})
+		if err != nil {
+			t.Fatalf("failed to list connections: %v", err)
+		}
+		if resp.GetTotal() != 1 || resp.GetConnections()[0].GetDeviceId() != "device-1" {
+			t.Fatalf("unexpected connections: %v", resp)
+		}
+	})
+
+	t.Run("get connection", func(t *testing.T) {
+		resp, err := client.ListConnections(ctx, &service.ListConnectionsRequest{UserUuid: "user-b"})
+		if err != nil || len(resp.GetConnections()) != 1 {
+			t.Fatalf("failed to list connections: %v %v", resp, err)
+		}
+		info, err := client.GetConnection(ctx, &service.GetConnectionRequest{ConnUuid: resp.GetConnections()[0].GetConnUuid()})
+		if err != nil {
+			t.Fatalf("failed to get connection: %v", err)
+		}
+		connection := info.GetConnection()
+		if connection.GetRemoteAddr() != clientB.conn.LocalAddr().String() || connection.GetBytesIn() == 0 || connection.GetConnectedAt() == 0 || connection.GetLastActiveAt() == 0 {
+			t.Fatalf("unexpected connection: %v", connection)
+		}
+		if _, err := client.GetConnection(ctx, &service.GetConnectionRequest{ConnUuid: "not-exist"}); status.Code(err) != codes.NotFound {
+			t.Fatalf("expected not found, got %v", err)
+		}
+	})
+
+	t.Run("del conn", func(t *testing.T) {
+		resp, err := client.ListConnections(ctx, &service.ListConnectionsRequest{UserUuid: "user-c"})
+		if err != nil || len(resp.GetConnections()) != 1 {
+			t.Fatalf("failed to list connections: %v %v", resp, err)
+		}
+		if _, err := client.DelConn(ctx, &service.DelConnRequest{ConnId: resp.GetConnections()[0].GetConnUuid(), Reason: "debug"}); err != nil {
+			t.Fatalf("failed to del conn: %v", err)
+		}
+		kick := &plato.MessageKick{}
+		if err := clientC.read(t, plato.MsgTypeKick).UnmarshalBody(kick); err != nil || kick.GetReason() != "debug" {
+			t.Fatalf("unexpected kick: %v %v", kick, err)
+		}
+		presence.wait(t, PresenceStatusOffline)
+		if _, err := client.DelConn(ctx, &service.DelConnRequest{ConnId: "not-exist"}); status.Code(err) != codes.NotFound {
+			t.Fatalf("expected not found, got %v", err)
+		}
+	})
+
+	t.Run("kick user across gateways", func(t *testing.T) {
+		resp, err := client.KickUser(ctx, &service.KickUserRequest{UserUuid: "user-a", Reason: "compromised"})
+		if err != nil {
+			t.Fatalf("failed to kick user: %v", err)
+		}
+		if resp.GetKicked() != 2 {
+			t.Fatalf("expected 2 kicked, got %d", resp.GetKicked())
+		}
+		for _, c := range []*testClient{desktop, mobile} {
+			kick := &plato.MessageKick{}
+			if err := c.read(t, plato.MsgTypeKick).UnmarshalBody(kick); err != nil || kick.GetReason() != "compromised" {
+				t.Fatalf("unexpected kick: %v %v", kick, err)
+			}
+		}
+	})
+}
+
+func (c *testClient) sessionResult(t *testing.T, msgType int) *plato.MessageSessionResult {
+	t.Helper()
+	result := &plato.MessageSessionResult{}
+	if err := c.read(t, msgType).UnmarshalBody(result); err != nil {
+		t.Fatalf("failed to unmarshal session result: %v", err)
+	}
+	if len(result.GetError()) > 0 {
+		t.Fatalf("session operation failed: %v", result)
+	}
+	return result
+}
+
+func (c *testClient) receiveSystem(t *testing.T, result *plato.MessageSessionResult) {
+	t.Helper()
+	msg := c.receive(t)
+	if msg.GetMessageType() != model.MessageTypeSystem || msg.GetMessageUuid() != result.GetMessageUuid() || msg.GetSeqId() != result.GetSeqId() {
+		t.Fatalf("unexpected system message: %v, result: %v", msg, result)
+	}
+	c.ack(t, msg.GetMessageUuid())
+}
+
+func TestSessionMembership(t *testing.T) {
+	conf := newTestConf()
+	conf.HeartbeatInterval = 60
+	presence := newRecordPresence()
+	_, addr := startTestServer(t, conf, newFakeAPIGatewayClient(), presence, NewRedisRouteStore(newTestRedis(t)))
+
+	clientA := dialTestClient(t, addr, "user-a")
+	clientB := dialTestClient(t, addr, "user-b")
+	clientC := dialTestClient(t, addr, "user-c")
+	waitOnline(t, presence, 3)
+
+	if err := clientA.encoder.EncodeMessage(plato.MsgTypeOpenSession, &plato.MessageOpenSession{
+		RequestId:   "request-1",
+		Name:        "group",
+		MemberUuids: []string{"user-b"},
+	}); err != nil {
+		t.Fatalf("failed to open session: %v", err)
+	}
+	opened := clientA.sessionResult(t, plato.MsgTypeOpenSession)
+	if opened.GetRequestId() != "request-1" || len(opened.GetSessionUuid()) == 0 {
+		t.Fatalf("unexpected result: %v", opened)
+	}
+	session_uuid := opened.GetSessionUuid()
+	clientB.receiveSystem(t, opened)
+
+	if err := clientA.encoder.EncodeMessage(plato.MsgTypeJoinSession, &plato.MessageJoinSession{
+		RequestId:   "request-2",
+		SessionUuid: session_uuid,
+		UserUuids:   []string{"user-c"},
+	}); err != nil {
+		t.Fatalf("failed to join session: %v", err)
+	}
+	joined := clientA.sessionResult(t, plato.MsgTypeJoinSession)
+	clientB.receiveSystem(t, joined)
+	clientC.receiveSystem(t, joined)
+
+	// 会话缓存已刷新，新成员能收到消息
+	clientA.send(t, session_uuid, "hello", "client-msg-1")
+	if msg := clientC.receive(t); msg.GetPayload() != "hello" {
+		t.Fatalf("unexpected downlink: %v", msg)
+	}
+	if msg := clientB.receive(t); msg.GetPayload() != "hello" {
+		t.Fatalf("unexpected downlink: %v", msg)
+	}
+
+	if err := clientB.encoder.EncodeMessage(plato.MsgTypeLeaveSession, &plato.MessageLeaveSession{
+		RequestId:   "request-3",
+		SessionUuid: session_uuid,
+	}); err != nil {
+		t.Fatalf("failed to leave session: %v", err)
+	}
+	left := clientB.sessionResult(t, plato.MsgTypeLeaveSession)
+	clientA.receiveSystem(t, left)
+	clientC.receiveSystem(t, left)
+
+	// 退出后不再收到会话消息
+	clientA.send(t, session_uuid, "bye", "client-msg-2")
+	if msg := clientC.receive(t); msg.GetPayload() != "bye" {
+		t.Fatalf("unexpected downlink: %v", msg)
+	}
+	clientB.conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	for {
+		frame, err := clientB.decoder.Decode()
+		if err != nil {
+			break
+		}
+		if frame.MsgType == plato.MsgTypeMessageDownLink {
+			t.Fatalf("unexpected downlink after leave")
+		}
+	}
+}
+
+func TestSessionInvalidation(t *testing.T) {
+	conf := newTestConf()
+	conf.HeartbeatInterval = 60
+	apiGatewayClient := newFakeAPIGatewayClient()
+	apiGatewayClient.setSession("session-1", "user-a", "user-b")
+	redisClient := newTestRedis(t)
+	presence := newRecordPresence()
+	server, addr := startTestServer(t, conf, apiGatewayClient, presence, NewRedisRouteStore(redisClient))
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go event.SubscribeSessionMembersChanged(ctx, redisClient, server.InvalidateSession)
+
+	clientA := dialTestClient(t, addr, "user-a")
+	clientB := dialTestClient(t, addr, "user-b")
+	clientC := dialTestClient(t, addr, "user-c")
+	waitOnline(t, presence, 3)
+
+	clientA.send(t, "session-1", "hello", "client-msg-1")
+	clientB.ack(t, clientB.receive(t).GetMessageUuid())
+
+	// 成员变更后通过发布订阅使网关缓存失效
+	apiGatewayClient.setSession("session-1", "user-a", "user-b", "user-c")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		numSub, err := redisClient.PubSubNumSub(ctx, event.ChannelSessionMembers).Result()
+		if err != nil {
+			t.Fatalf("failed to get subscribers: %v", err)
+		}
+		if numSub[event.ChannelSessionMembers] > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("subscriber not ready")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := event.PublishSessionMembersChanged(ctx, redisClient, "session-1"); err != nil {
+		t.Fatalf("failed to publish: %v", err)
+	}
+	for server.manager.GetSession("session-1") != nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("session not invalidated")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	clientA.send(t, "session-1", "world", "client-msg-2")
+	if msg := clientC.receive(t); msg.GetPayload() != "world" {
+		t.Fatalf("unexpected downlink: %v", msg)
+	}
+}
+
+// 通过内存管道连接网关，客户端不读取时网关的写操作会一直阻塞
+func pipeTestClient(t *testing.T, server *Server, user_uuid string) *testClient {
+	t.Helper()
+	serverConn, clientConn := net.Pipe()
+	go server.accept(serverConn)
+	return newTestClient(t, clientConn, user_uuid, "", "")
+}
+
+func TestSlowConsumer(t *testing.T) {
+	newSlowConsumer := func(t *testing.T, policy string) (*Server, *recordPresence, *testClient) {
+		conf := newTestConf()
+		conf.HeartbeatInterval = 60
+		conf.AckTimeout = 60
+		conf.WriteQueueSize = 2
+		conf.WriteQueuePolicy = policy
+		presence := newRecordPresence()
+		server, _ := startTestServer(t, conf, newFakeAPIGatewayClient(), presence, NewRedisRouteStore(newTestRedis(t)))
+		client := pipeTestClient(t, server, "user-a")
+		waitOnline(t, presence, 1)
+		for i := 1; i <= 10; i++ {
+			server.Push([]string{"user-a"}, "", &plato.MessageDownLink{
+				SessionUuid: "session-1",
+				Payload:     fmt.Sprintf("message-%d", i),
+				SeqId:       int64(i),
+				MessageUuid: fmt.Sprintf("message-uuid-%d", i),
+			})
+		}
+		return server, presence, client
+	}
+
+	t.Run("drop", func(t *testing.T) {
+		_, _, client := newSlowConsumer(t, WriteQueuePolicyDrop)
+		// 队列写满后的消息被丢弃，客户端按resync补拉
+		received := int64(0)
+		client.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		for {
+			frame, err := client.decoder.Decode()
+			if err != nil {
+				t.Fatalf("failed to read frame: %v", err)
+			}
+			if frame.MsgType == plato.MsgTypeMessageDownLink {
+				msg := &plato.MessageDownLink{}
+				if err := frame.UnmarshalBody(msg); err != nil {
+					t.Fatalf("failed to unmarshal downlink: %v", err)
+				}
+				if msg.GetSeqId() != received+1 {
+					t.Fatalf("unexpected downlink order: %v", msg)
+				}
+				received = msg.GetSeqId()
+				continue
+			}
+			if frame.MsgType != plato.MsgTypeResync {
+				continue
+			}
+			resync := &plato.MessageResync{}
+			if err := frame.UnmarshalBody(resync); err != nil {
+				t.Fatalf("failed to unmarshal resync: %v", err)
+			}
+			if resync.GetSessionUuid() != "session-1" || resync.GetStartSeqid() != received || received >= 10 {
+				t.Fatalf("unexpected resync: %v, received: %d", resync, received)
+			}
+			return
+		}
+	})
+
+	t.Run("disconnect", func(t *testing.T) {
+		_, presence, client := newSlowConsumer(t, WriteQueuePolicyDisconnect)
+		presence.wait(t, PresenceStatusOffline)
+		// 已入队的帧写完后连接被关闭
+		client.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		for {
+			if _, err := client.decoder.Decode(); err != nil {
+				if err != io.EOF {
+					t.Fatalf("expected connection closed, got %v", err)
+				}
+				return
+			}
+		}
+	})
+}
+
+func dialWebSocketClient(t *testing.T, server *Server, user_uuid string) *testClient {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go server.ServeWebSocket(listener)
+	conn, _, err := websocket.DefaultDialer.Dial(fmt.Sprintf("ws://%s%s", listener.Addr().String(), server.conf.WebSocketPath), nil)
+	if err != nil {
+		t.Fatalf("failed to dial websocket: %v", err)
+	}
+	return newTestClient(t, newWSConn(conn), user_uuid, "", "")
+}
+
+func TestWebSocketTransport(t *testing.T) {
+	conf := newTestConf()
+	conf.HeartbeatInterval = 60
+	apiGatewayClient := newFakeAPIGatewayClient()
+	apiGatewayClient.setSession("session-1", "user-a", "user-b")
+	presence := newRecordPresence()
+	server, addr := startTestServer(t, conf, apiGatewayClient, presence, NewRedisRouteStore(newTestRedis(t)))
+
+	tcpClient := dialTestClient(t, addr, "user-a")
+	wsClient := dialWebSocketClient(t, server, "user-b")
+	waitOnline(t, presence, 2)
+
+	// 两种传输共享连接管理和消息扇出
+	ack := tcpClient.send(t, "session-1", "from tcp", "client-msg-1")
+	msg := wsClient.receive(t)
+	if msg.GetMessageUuid() != ack.GetMessageUuid() || msg.GetPayload() != "from tcp" {
+		t.Fatalf("unexpected downlink: %v, ack: %v", msg, ack)
+	}
+	wsClient.ack(t, msg.GetMessageUuid())
+
+	ack = wsClient.send(t, "session-1", "from websocket", "client-msg-2")
+	msg = tcpClient.receive(t)
+	if msg.GetMessageUuid() != ack.GetMessageUuid() || msg.GetPayload() != "from websocket" {
+		t.Fatalf("unexpected downlink: %v, ack: %v", msg, ack)
+	}
+	tcpClient.ack(t, msg.GetMessageUuid())
+
+	if err := wsClient.encoder.EncodeMessage(plato.MsgTypePing, &plato.MessagePing{Timestamp: 1}); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+	pong := &plato.MessagePong{}
+	if err := wsClient.read(t, plato.MsgTypePong).UnmarshalBody(pong); err != nil || pong.GetTimestamp() != 1 {
+		t.Fatalf("unexpected pong: %v, error: %v", pong, err)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	conf := newTestConf()
+	conf.HeartbeatInterval = 60
+	apiGatewayClient := newFakeAPIGatewayClient()
+	apiGatewayClient.setSession("session-1", "user-a", "user-b")
+	presence := newRecordPresence()
+	server, addr := startTestServer(t, conf, apiGatewayClient, presence, NewRedisRouteStore(newTestRedis(t)))
+
+	clientA := dialTestClient(t, addr, "user-a")
+	clientB := dialTestClient(t, addr, "user-b")
+	waitOnline(t, presence, 2)
+	ack := clientA.send(t, "session-1", "before shutdown", "client-msg-1")
+	if msg := clientB.receive(t); msg.GetMessageUuid() != ack.GetMessageUuid() {
+		t.Fatalf("unexpected downlink: %v", msg)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		server.Shutdown(ctx)
+		close(done)
+	}()
+
+	// 收到重连通知后连接被关闭
+	for _, client := range []*testClient{clientA, clientB} {
+		client.read(t, plato.MsgTypeReconnect)
+		if _, err := client.decoder.Decode(); err != io.EOF {
+			t.Fatalf("expected connection closed, got %v", err)
+		}
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("shutdown timeout")
+	}
+	if connections := server.Connections(""); len(connections) != 0 {
+		t.Fatalf("expected no connections, got %d", len(connections))
+	}
+
+	// 下线期间的新连接直接通知重连
+	clientC := dialTestClient(t, addr, "user-c")
+	clientC.read(t, plato.MsgTypeReconnect)
+}
@@ -0,0 +1,121 @@
+package imgateway
+
+import (
+	"context"
+	"im/model"
+	"im/pkg/plato"
+	"time"
+
+	apigatewayService "im/server/apigateway/rpc/service"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// 携带连接的令牌，API网关以令牌中的用户作为操作者
+func (s *Server) userContext(conn_uuid string) context.Context {
+	ctx := context.Background()
+	if connection := s.manager.GetConnection(conn_uuid); connection != nil {
+		ctx = metadata.AppendToOutgoingContext(ctx, "token", connection.token)
+	}
+	return ctx
+}
+
+// 创建群聊会话
+func (s *Server) handleOpenSession(conn_uuid string, user_uuid string, msg *plato.MessageOpenSession) {
+	epoch := s.manager.SessionEpoch()
+	resp, err := s.apiGatewayClient.CreateSession(s.userContext(conn_uuid), &apigatewayService.CreateSessionRequest{
+		Name:        msg.GetName(),
+		MemberUuids: msg.GetMemberUuids(),
+	})
+	if err != nil {
+		s.logger.Error("failed to create session", "error", err, "user_uuid", user_uuid)
+		s.replySession(conn_uuid, plato.MsgTypeOpenSession, &plato.MessageSessionResult{RequestId: msg.GetRequestId(), Error: err.Error()})
+		return
+	}
+	s.membershipChanged(conn_uuid, plato.MsgTypeOpenSession, epoch, msg.GetRequestId(), resp.GetSessionUuid(), resp.GetMemberUuids(), resp.GetMemberUuids(), resp.GetMessage())
+}
+
+// 邀请用户加入会话
+func (s *Server) handleJoinSession(conn_uuid string, user_uuid string, msg *plato.MessageJoinSession) {
+	epoch := s.manager.SessionEpoch()
+	resp, err := s.apiGatewayClient.JoinSession(s.userContext(conn_uuid), &apigatewayService.JoinSessionRequest{
+		SessionUuid: msg.GetSessionUuid(),
+		UserUuids:   msg.GetUserUuids(),
+	})
+	if err != nil {
+		s.logger.Error("failed to join session", "error", err, "user_uuid", user_uuid, "session_uuid", msg.GetSessionUuid())
+		s.replySession(conn_uuid, plato.MsgTypeJoinSession, &plato.MessageSessionResult{RequestId: msg.GetRequestId(), SessionUuid: msg.GetSessionUuid(), Error: err.Error()})
+		return
+	}
+	s.membershipChanged(conn_uuid, plato.MsgTypeJoinSession, epoch, msg.GetRequestId(), msg.GetSessionUuid(), resp.GetMemberUuids(), resp.GetMemberUuids(), resp.GetMessage())
+}
+
+// 退出会话，退出者的其他设备也需要收到通知
+func (s *Server) handleLeaveSession(conn_uuid string, user_uuid string, msg *plato.MessageLeaveSession) {
+	epoch := s.manager.SessionEpoch()
+	resp, err := s.apiGatewayClient.LeaveSession(s.userContext(conn_uuid), &apigatewayService.LeaveSessionRequest{
+		SessionUuid: msg.GetSessionUuid(),
+	})
+	if err != nil {
+		s.logger.Error("failed to leave session", "error", err, "user_uuid", user_uuid, "session_uuid", msg.GetSessionUuid())
+		s.replySession(conn_uuid, plato.MsgTypeLeaveSession, &plato.MessageSessionResult{RequestId: msg.GetRequestId(), SessionUuid: msg.GetSessionUuid(), Error: err.Error()})
+		return
+	}
+	s.membershipChanged(conn_uuid, plato.MsgTypeLeaveSession, epoch, msg.GetRequestId(), msg.GetSessionUuid(), resp.GetMemberUuids(), append(resp.GetMemberUuids(), user_uuid), resp.GetMessage())
+}
+
+// 刷新会话成员缓存，回复操作结果，并向其他成员推送成员变更系统消息
+func (s *Server) membershipChanged(conn_uuid string, msgType int8, epoch int64, request_id string, session_uuid string, member_uuids []string, recipients []string, message *apigatewayService.Message) {
+	s.manager.AddSession(session_uuid, member_uuids, epoch)
+	result := &plato.MessageSessionResult{
+		RequestId:   request_id,
+		SessionUuid: session_uuid,
+	}
+	if message == nil {
+		s.replySession(conn_uuid, msgType, result)
+		return
+	}
+	result.MessageUuid = message.GetMessageUuid()
+	result.SeqId = message.GetSeqId()
+	s.replySession(conn_uuid, msgType, result)
+	s.route(recipients, conn_uuid, &plato.MessageDownLink{
+		SessionUuid:    session_uuid,
+		SenderUserUuid: message.GetSenderUuid(),
+		Payload:        message.GetContent(),
+		SeqId:          message.GetSeqId(),
+		MessageUuid:    message.GetMessageUuid(),
+		MessageType:    model.MessageTypeSystem,
+	})
+}
+
+// InvalidateSession 会话成员变更后丢弃缓存，下次发送消息时重新查询
+func (s *Server) InvalidateSession(session_uuid string) {
+	s.logger.Debug("invalidate session", "session_uuid", session_uuid)
+	s.manager.RemoveSession(session_uuid)
+}
+
+// 定时清理过期的会话缓存并输出命中率
+func (s *Server) monitorSessionCache(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			purged := s.manager.PurgeExpiredSessions()
+			hits, misses, size := s.manager.SessionCacheStats()
+			s.logger.Info("session cache info", "hits", hits, "misses", misses, "size", size, "purged", purged)
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Server) replySession(conn_uuid string, msgType int8, result *plato.MessageSessionResult) {
+	connection := s.manager.GetConnection(conn_uuid)
+	if connection == nil {
+		return
+	}
+	if err := s.write(connection, msgType, result); err != nil {
+		s.logger.Error("failed to write session result", "error", err, "conn_uuid", conn_uuid)
+	}
+}
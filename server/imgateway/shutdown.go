@@ -0,0 +1,43 @@
+package imgateway
+
+import (
+	"context"
+	"im/pkg/plato"
+	"time"
+)
+
+const reconnectReason = "网关下线，请重新连接"
+
+// Shutdown 通知所有连接重连到其他网关，等待写队列排空后关闭连接
+// ctx到期后强制关闭剩余的连接
+func (s *Server) Shutdown(ctx context.Context) {
+	s.draining.Store(true)
+	connections := s.manager.ListConnections()
+	s.logger.Info("drain connections", "count", len(connections))
+	for _, connection := range connections {
+		if err := connection.encoder.EncodeMessage(plato.MsgTypeReconnect, &plato.MessageReconnect{Reason: reconnectReason}); err != nil {
+			s.logger.Error("failed to write reconnect", "error", err, "conn_uuid", connection.conn_uuid)
+		}
+		// writer写完队列中剩余的帧后关闭连接，读循环随之退出并清理路由
+		connection.writer.Close()
+	}
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		remaining := s.manager.ListConnections()
+		if len(remaining) == 0 {
+			s.logger.Info("drain connections done")
+			return
+		}
+		select {
+		case <-ctx.Done():
+			s.logger.Warn("drain connections timeout, force close", "remaining", len(remaining))
+			for _, connection := range remaining {
+				connection.conn.Close()
+				s.closeConnection(connection.conn_uuid)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package imgateway
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	TransportTCP       = "tcp"
+	TransportWebSocket = "websocket"
+)
+
+// 将WebSocket连接适配为net.Conn，每个plato帧作为一条二进制消息收发
+// 读取时按字节流处理，兼容客户端将多个帧合并到一条消息中
+type wsConn struct {
+	*websocket.Conn
+	reader io.Reader
+}
+
+func newWSConn(conn *websocket.Conn) *wsConn {
+	return &wsConn{Conn: conn}
+}
+
+func (c *wsConn) Read(p []byte) (int, error) {
+	for {
+		if c.reader == nil {
+			messageType, reader, err := c.NextReader()
+			if err != nil {
+				if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+					return 0, io.EOF
+				}
+				return 0, err
+			}
+			if messageType != websocket.BinaryMessage {
+				continue
+			}
+			c.reader = reader
+		}
+		n, err := c.reader.Read(p)
+		if err == io.EOF {
+			c.reader = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+// Write 每次调用发送一条二进制消息，plato.Encoder保证每次写入一个完整的帧
+func (c *wsConn) Write(p []byte) (int, error) {
+	if err := c.WriteMessage(websocket.BinaryMessage, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *wsConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
+
+// ServeWebSocket 接收WebSocket长连接，直到listener关闭
+// 升级后与TCP连接走相同的认证和消息处理流程
+func (s *Server) ServeWebSocket(listener net.Listener) error {
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  4096,
+		WriteBufferSize: 4096,
+		// 认证通过MessageCreateConn中的token完成，不依赖cookie，允许跨域连接
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(s.conf.WebSocketPath, func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			s.logger.Error("failed to upgrade websocket", "error", err, "remote_addr", r.RemoteAddr)
+			return
+		}
+		s.accept(newWSConn(conn))
+	})
+	server := &http.Server{Handler: mux}
+	go func() {
+		<-s.ctx.Done()
+		server.Close()
+	}()
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed && !errors.Is(err, net.ErrClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package imgateway
+
+import (
+	"bufio"
+	"errors"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	WriteQueuePolicyDisconnect = "disconnect" // 写队列满时断开连接，客户端重连后补拉
+	WriteQueuePolicyDrop       = "drop"       // 写队列满时丢弃下行消息，队列恢复后通知客户端补拉
+)
+
+// 单次flush合并的最大字节数
+const maxFlushBytes = 64 << 10
+
+var (
+	ErrWriteQueueFull = errors.New("imgateway: write queue full")
+	ErrWriterClosed   = errors.New("imgateway: writer closed")
+)
+
+// 连接的有界写队列，由独立的goroutine写入网络，避免慢连接阻塞发送方
+// 每次Write都是一个完整的帧，plato.Encoder保证这一点
+type connWriter struct {
+	conn      net.Conn
+	logger    *slog.Logger
+	timeout   time.Duration
+	framed    bool // 基于消息的传输（WebSocket），每帧单独写入，不合并
+	queue     chan []byte
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+func newConnWriter(conn net.Conn, logger *slog.Logger, size int, timeout time.Duration, framed bool) *connWriter {
+	w := &connWriter{
+		conn:    conn,
+		logger:  logger,
+		timeout: timeout,
+		framed:  framed,
+		queue:   make(chan []byte, size),
+		closing: make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write 将帧放入写队列，队列已满时立即返回ErrWriteQueueFull
+func (w *connWriter) Write(p []byte) (int, error) {
+	select {
+	case <-w.closing:
+		return 0, ErrWriterClosed
+	default:
+	}
+	frame := make([]byte, len(p))
+	copy(frame, p)
+	select {
+	case w.queue <- frame:
+		return len(p), nil
+	default:
+		return 0, ErrWriteQueueFull
+	}
+}
+
+// Close 写完队列中剩余的帧后关闭连接，不等待写入完成
+func (w *connWriter) Close() {
+	w.closeOnce.Do(func() {
+		close(w.closing)
+	})
+}
+
+// Len 队列中等待写入的帧数
+func (w *connWriter) Len() int {
+	return len(w.queue)
+}
+
+func (w *connWriter) run() {
+	defer w.conn.Close()
+	bw := bufio.NewWriterSize(w.conn, maxFlushBytes)
+	for {
+		select {
+		case frame := <-w.queue:
+			if err := w.flush(bw, frame); err != nil {
+				w.logger.Error("failed to write connection", "error", err, "remote_addr", w.conn.RemoteAddr().String())
+				w.Close()
+				return
+			}
+		case <-w.closing:
+			// 尽量写完剩余的帧，如踢下线通知
+			for {
+				select {
+				case frame := <-w.queue:
+					if err := w.flush(bw, frame); err != nil {
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// 写入一帧，并合并队列中已有的帧后一次flush
+func (w *connWriter) flush(bw *bufio.Writer, frame []byte) error {
+	w.conn.SetWriteDeadline(time.Now().Add(w.timeout))
+	if w.framed {
+		_, err := w.conn.Write(frame)
+		return err
+	}
+	bw.Write(frame)
+	for bw.Buffered() < maxFlushBytes {
+		select {
+		case frame := <-w.queue:
+			bw.Write(frame)
+			continue
+		default:
+		}
+		break
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,66 @@
+package imgateway
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestConnWriter(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	writer := newConnWriter(server, logger, 2, time.Second, false)
+
+	// 对端不读取时写入阻塞，队列写满后立即返回错误
+	written := 0
+	for ; written < 10; written++ {
+		if _, err := writer.Write([]byte{byte(written)}); err != nil {
+			if !errors.Is(err, ErrWriteQueueFull) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			break
+		}
+	}
+	if written == 10 {
+		t.Fatalf("expected write queue full")
+	}
+
+	// 关闭后写完剩余的帧再关闭连接
+	writer.Close()
+	if _, err := writer.Write([]byte{0}); !errors.Is(err, ErrWriterClosed) {
+		t.Fatalf("expected writer closed, got %v", err)
+	}
+	client.SetReadDeadline(time.Now().Add(5 * time.Second))
+	data, err := io.ReadAll(client)
+	if err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+	if len(data) != written {
+		t.Fatalf("expected %d bytes, got %d", written, len(data))
+	}
+	for i, b := range data {
+		if int(b) != i {
+			t.Fatalf("unexpected frame order: %v", data)
+		}
+	}
+}
+
+func TestConnWriterTimeout(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	writer := newConnWriter(server, logger, 2, 50*time.Millisecond, false)
+
+	// 对端一直不读取，写超时后关闭连接
+	writer.Write([]byte{1})
+	client.SetReadDeadline(time.Now().Add(5 * time.Second))
+	time.Sleep(200 * time.Millisecond)
+	if _, err := client.Read(make([]byte, 1)); err != io.EOF {
+		t.Fatalf("expected connection closed, got %v", err)
+	}
+}
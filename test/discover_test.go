@@ -5,25 +5,61 @@ import (
 	"fmt"
 	"im/server/discovery"
 	"im/server/discovery/rpc/service"
-	"os"
+	"net"
 	"strconv"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	_ "github.com/joho/godotenv/autoload"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
+// 在随机端口启动发现服务，等待就绪后返回地址
+func startDiscovery(t *testing.T, storage string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+	t.Setenv("IM_DISCOVERY_ADDR", addr)
+	t.Setenv("IM_DISCOVERY_STORAGE", storage)
+	go discovery.Run()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// 先等待端口可连接，避免gRPC连接失败后进入重连退避
+	for {
+		if c, err := net.Dial("tcp", addr); err == nil {
+			c.Close()
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("discovery %s not listening", addr)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+	ready, err := service.NewDiscoveryClient(conn).Ready(ctx, &service.ReadyRequest{})
+	if err != nil || !ready.Ready {
+		t.Fatalf("discovery %s not ready: %v", addr, err)
+	}
+	return addr
+}
+
 func TestDiscover(t *testing.T) {
-	go func() {
-		os.Setenv("IM_DISCOVERY_ADDR", ":8085")
-		discovery.Run()
-	}()
+	addr := startDiscovery(t, service.StorageMemory)
 
-	conn, err := grpc.NewClient("localhost:8085", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		t.Fatalf("failed to dial: %v", err)
 	}
@@ -111,14 +147,17 @@ func TestMultiDiscover(t *testing.T) {
 	clientNum := 100
 	loopNum := 100
 	ip := "localhost"
-	port := 8085
+	// 多个副本通过Redis共享注册信息
+	redisServer := miniredis.RunT(t)
+	redisServer.RequireAuth("test")
+	t.Setenv("IM_DISCOVERY_REDIS_ADDR", redisServer.Addr())
+	t.Setenv("IM_DISCOVERY_REDIS_PASSWORD", "test")
+	addrs := make([]string, 0, discoveryNum)
 	for i := 0; i < discoveryNum; i++ {
-		os.Setenv("IM_DISCOVERY_ADDR", ip+":"+strconv.Itoa(port+i))
-		go discovery.Run()
-		time.Sleep(time.Millisecond * 100)
+		addrs = append(addrs, startDiscovery(t, service.StorageRedis))
 	}
 
-	conn, err := grpc.NewClient(ip+":"+strconv.Itoa(port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(addrs[0], grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		t.Fatalf("failed to dial: %v", err)
 	}
@@ -127,27 +166,16 @@ func TestMultiDiscover(t *testing.T) {
 	discoveryClient := service.NewDiscoveryClient(conn)
 
 	ctx := context.Background()
-	discoveryClient.Ready(ctx, &service.ReadyRequest{})
-
-	if ready, err := discoveryClient.Ready(ctx, &service.ReadyRequest{}); err != nil {
-		t.Fatalf("failed to get ready: %v", err)
-	} else {
-		if !ready.Ready {
-			t.Fatal("failed to get ready")
+	for _, port := range []string{"8085", "8086"} {
+		if _, err := discoveryClient.Register(ctx, &service.RegisterRequest{
+			ServiceName:    "test",
+			ServiceAddress: ip,
+			ServicePort:    port,
+		}); err != nil {
+			t.Fatalf("failed to register: %v", err)
 		}
 	}
 
-	discoveryClient.Register(ctx, &service.RegisterRequest{
-		ServiceName:    "test",
-		ServiceAddress: ip,
-		ServicePort:    "8085",
-	})
-	discoveryClient.Register(ctx, &service.RegisterRequest{
-		ServiceName:    "test",
-		ServiceAddress: ip,
-		ServicePort:    "8086",
-	})
-
 	var wg sync.WaitGroup
 	ipMap := make(map[string]*atomic.Int64, 0)
 
@@ -158,7 +186,7 @@ func TestMultiDiscover(t *testing.T) {
 		wg.Add(1)
 		go func(i int) {
 			defer wg.Done()
-			conn, err := grpc.NewClient(ip+":"+strconv.Itoa(port+i%discoveryNum), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			conn, err := grpc.NewClient(addrs[i%discoveryNum], grpc.WithTransportCredentials(insecure.NewCredentials()))
 			if err != nil {
 				t.Errorf("failed to dial: %v", err)
 			}
@@ -2,14 +2,13 @@ package test
 
 import (
 	"fmt"
+	"im/pkg/jwt"
 	"im/pkg/plato"
 	"io"
 	"log"
 	"net"
 	"testing"
 	"time"
-
-	"google.golang.org/protobuf/proto"
 )
 
 func TestImgateway(t *testing.T) {
@@ -19,76 +18,82 @@ func TestImgateway(t *testing.T) {
 	}
 	defer conn.Close()
 
-	conn.Write(plato.Marshal(1, plato.MsgTypeOpenSession, nil, nil))
+	token, _, err := jwt.GenerateToken("test-user", 3600, nil)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	encoder := plato.NewEncoder(conn)
+	if err := encoder.EncodeMessage(plato.MsgTypeCreateConn, &plato.MessageCreateConn{
+		Token:    token,
+		DeviceId: "test-device",
+		Platform: plato.PlatformDesktop,
+	}); err != nil {
+		t.Fatalf("failed to create conn: %v", err)
+	}
+	if err := encoder.EncodeMessage(plato.MsgTypeOpenSession, &plato.MessageOpenSession{
+		RequestId: "test-request",
+		Name:      "test",
+	}); err != nil {
+		t.Fatalf("failed to open session: %v", err)
+	}
 
 	sessionidChan := make(chan string)
-	go read(conn, sessionidChan)
-
-	go write(conn, sessionidChan)
-
-	select {}
+	go read(conn, encoder, sessionidChan)
 
+	write(encoder, sessionidChan)
 }
 
-func write(conn net.Conn, sessionidChan chan string) {
+func write(encoder *plato.Encoder, sessionidChan chan string) {
 	sessionid := <-sessionidChan
+	timeout := time.After(10 * time.Second)
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
 	for {
 		select {
-		case <-time.After(2 * time.Second):
-			msg, err := proto.Marshal(&plato.MessageUpLink{
-				SessionId:  sessionid,
-				FromUserId: "456",
-				Payload:    "Hello, World!",
-				Timestamp:  time.Now().Unix(),
-			})
-			if err != nil {
-				log.Fatalf("failed to marshal: %v", err)
-			}
-
-			data := plato.Marshal(1, 1, nil, msg)
-			if n, err := conn.Write(data); err != nil {
+		case <-ticker.C:
+			if err := encoder.EncodeMessage(plato.MsgTypeMessageUpLink, &plato.MessageUpLink{
+				SessionUuid: sessionid,
+				Payload:     "Hello, World!",
+				ClientMsgId: fmt.Sprintf("test-%d", time.Now().UnixNano()),
+			}); err != nil {
 				log.Printf("failed to write: %v", err)
-			} else {
-				log.Printf("write: %d", n)
 			}
-		case <-time.After(10 * time.Second):
+		case <-timeout:
 			return
 		}
 	}
 
 }
 
-func read(conn net.Conn, sessionidChan chan string) {
+func read(conn net.Conn, encoder *plato.Encoder, sessionidChan chan string) {
+	decoder := plato.NewDecoder(conn)
 	for {
-		buf := make([]byte, 10)
-		n, err := conn.Read(buf)
+		frame, err := decoder.Decode()
 		if err != nil {
 			if err != io.EOF {
-				fmt.Printf("\nfailed to read: %v\n", err)
+				fmt.Printf("\nfailed to decode: %v\n", err)
 			}
 			break
 		}
-		fixHeader := &plato.FixHeaderProtocol{}
-		if err := fixHeader.Unmarshal(buf[:n]); err != nil {
-			fmt.Printf("\nfailed to unmarshal: %v\n", err)
-			break
-		}
-		content := make([]byte, fixHeader.GetVarHeaderLen()+fixHeader.GetBodyLen())
-		if _, err := conn.Read(content); err != nil {
-			if err != io.EOF {
-				fmt.Printf("\nfailed to read content: %v\n", err)
-				break
-			}
-		}
-		switch fixHeader.GetMsgType() {
+		switch frame.MsgType {
 		case plato.MsgTypeMessageDownLink:
 			msg := plato.MessageDownLink{}
-			proto.Unmarshal(content[fixHeader.GetVarHeaderLen():], &msg)
-			fmt.Println("msg:", msg.GetSessionId(), msg.GetFromUserId(), msg.GetPayload(), msg.GetTimestamp())
+			frame.UnmarshalBody(&msg)
+			fmt.Println("msg:", msg.GetSessionUuid(), msg.GetSenderUserUuid(), msg.GetPayload(), msg.GetSeqId())
+			encoder.EncodeMessage(plato.MsgTypeAck, &plato.MessageAck{MessageUuid: msg.GetMessageUuid()})
+		case plato.MsgTypeUpLinkAck:
+			msg := plato.MessageUpLinkAck{}
+			frame.UnmarshalBody(&msg)
+			fmt.Println("ack:", msg.GetClientMsgId(), msg.GetMessageUuid(), msg.GetSeqId(), msg.GetError())
 		case plato.MsgTypeOpenSession:
-			sessionid := string(content[fixHeader.GetVarHeaderLen():])
-			sessionidChan <- sessionid
-			fmt.Println("sessionid:", sessionid)
+			msg := plato.MessageSessionResult{}
+			frame.UnmarshalBody(&msg)
+			if len(msg.GetError()) > 0 {
+				fmt.Println("open session failed:", msg.GetError())
+				continue
+			}
+			sessionidChan <- msg.GetSessionUuid()
+			fmt.Println("sessionid:", msg.GetSessionUuid())
 		}
 	}
 }